@@ -7,6 +7,8 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	// Embedded so SPARKYFITNESS_TIMEZONE resolves in images without tzdata
+	_ "time/tzdata"
 
 	"github.com/chickenzord/sparkyfitness-mcp/internal/config"
 	"github.com/chickenzord/sparkyfitness-mcp/internal/logger"
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"net/http"
 	"os"
+	"strconv"
+	"time"
 )
 
 // TransportMode defines the transport protocol for the MCP server
@@ -40,12 +42,41 @@ const (
 	LogFormatJSON LogFormat = "json"
 )
 
+// Defaults for the SparkyFitness HTTP client policy
+const (
+	// DefaultRequestTimeout bounds a single backend request attempt
+	DefaultRequestTimeout = 30 * time.Second
+	// DefaultMaxRetries is the number of retries after the first attempt
+	DefaultMaxRetries = 3
+	// DefaultRetryBaseDelay is the initial backoff delay between retries
+	DefaultRetryBaseDelay = 500 * time.Millisecond
+	// DefaultRetryMaxDelay caps the backoff delay and any Retry-After wait
+	DefaultRetryMaxDelay = 10 * time.Second
+	// DefaultMaxConcurrency bounds the backend requests run at once by batch operations
+	DefaultMaxConcurrency = 4
+)
+
 // Config holds the application configuration
 type Config struct {
 	// SparkyFitnessAPIURL is the base URL for the SparkyFitness API
 	SparkyFitnessAPIURL string
 	// SparkyFitnessAPIKey is the authentication credential for the API
 	SparkyFitnessAPIKey string
+	// RequestTimeout bounds each backend request attempt (0 disables the timeout)
+	RequestTimeout time.Duration
+	// MaxRetries is the number of retries for failed idempotent requests (0 disables retries)
+	MaxRetries int
+	// RetryBaseDelay is the initial exponential backoff delay between retries
+	RetryBaseDelay time.Duration
+	// RetryMaxDelay caps the backoff delay and the accepted Retry-After wait
+	RetryMaxDelay time.Duration
+	// RetryWrites enables retries for POST requests, sent with an Idempotency-Key
+	// header; only safe when the backend deduplicates on that header
+	RetryWrites bool
+	// MaxConcurrency bounds how many items batch operations process at once
+	MaxConcurrency int
+	// Timezone determines the current date when a diary tool defaults to today
+	Timezone *time.Location
 	// Transport defines the transport mode (stdio or http)
 	Transport TransportMode
 	// HTTPHost is the host to bind to when using HTTP transport
@@ -74,6 +105,60 @@ func LoadFromEnv() (*Config, error) {
 		return nil, fmt.Errorf("SPARKYFITNESS_API_KEY environment variable is required")
 	}
 
+	// Backend request timeout (default: 30s)
+	requestTimeout, err := durationFromEnv("SPARKYFITNESS_TIMEOUT", DefaultRequestTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	// Retry policy (default: 3 retries, 500ms base delay, 10s max delay, reads only)
+	maxRetries := DefaultMaxRetries
+	if v := os.Getenv("SPARKYFITNESS_MAX_RETRIES"); v != "" {
+		maxRetries, err = strconv.Atoi(v)
+		if err != nil || maxRetries < 0 {
+			return nil, fmt.Errorf("invalid SPARKYFITNESS_MAX_RETRIES value: %s (must be a non-negative integer)", v)
+		}
+	}
+
+	retryBaseDelay, err := durationFromEnv("SPARKYFITNESS_RETRY_BASE_DELAY", DefaultRetryBaseDelay)
+	if err != nil {
+		return nil, err
+	}
+
+	retryMaxDelay, err := durationFromEnv("SPARKYFITNESS_RETRY_MAX_DELAY", DefaultRetryMaxDelay)
+	if err != nil {
+		return nil, err
+	}
+	if retryMaxDelay < retryBaseDelay {
+		return nil, fmt.Errorf("invalid SPARKYFITNESS_RETRY_MAX_DELAY value: %s (must not be less than SPARKYFITNESS_RETRY_BASE_DELAY)", retryMaxDelay)
+	}
+
+	retryWrites := false
+	if v := os.Getenv("SPARKYFITNESS_RETRY_WRITES"); v != "" {
+		retryWrites, err = strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SPARKYFITNESS_RETRY_WRITES value: %s (must be 'true' or 'false')", v)
+		}
+	}
+
+	// Batch concurrency (default: 4)
+	maxConcurrency := DefaultMaxConcurrency
+	if v := os.Getenv("SPARKYFITNESS_MAX_CONCURRENCY"); v != "" {
+		maxConcurrency, err = strconv.Atoi(v)
+		if err != nil || maxConcurrency < 1 {
+			return nil, fmt.Errorf("invalid SPARKYFITNESS_MAX_CONCURRENCY value: %s (must be a positive integer)", v)
+		}
+	}
+
+	// Diary timezone (default: the server's local timezone)
+	timezone := time.Local
+	if v := os.Getenv("SPARKYFITNESS_TIMEZONE"); v != "" {
+		timezone, err = time.LoadLocation(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SPARKYFITNESS_TIMEZONE value: %s (must be an IANA time zone such as 'Europe/Berlin')", v)
+		}
+	}
+
 	// Transport mode (default: stdio)
 	transport := TransportMode(os.Getenv("MCP_TRANSPORT"))
 	if transport == "" {
@@ -119,6 +204,13 @@ func LoadFromEnv() (*Config, error) {
 	return &Config{
 		SparkyFitnessAPIURL:   apiURL,
 		SparkyFitnessAPIKey:   apiKey,
+		RequestTimeout:        requestTimeout,
+		MaxRetries:            maxRetries,
+		RetryBaseDelay:        retryBaseDelay,
+		RetryMaxDelay:         retryMaxDelay,
+		RetryWrites:           retryWrites,
+		MaxConcurrency:        maxConcurrency,
+		Timezone:              timezone,
 		Transport:             transport,
 		HTTPHost:              httpHost,
 		HTTPPort:              httpPort,
@@ -129,6 +221,22 @@ func LoadFromEnv() (*Config, error) {
 	}, nil
 }
 
+// durationFromEnv parses a non-negative duration (e.g., "30s", "500ms") from an
+// environment variable, returning def when the variable is unset
+func durationFromEnv(key string, def time.Duration) (time.Duration, error) {
+	v := os.Getenv(key)
+	if v == "" {
+		return def, nil
+	}
+
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid %s value: %s (must be a non-negative duration such as '30s' or '500ms')", key, v)
+	}
+
+	return d, nil
+}
+
 // BasicAuthEnabled returns true if basic authentication is configured
 func (c *Config) BasicAuthEnabled() bool {
 	return c.HTTPBasicAuthUser != "" && c.HTTPBasicAuthPassword != ""
@@ -4,6 +4,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestLoadFromEnv(t *testing.T) {
@@ -227,3 +228,149 @@ func TestBasicAuthEnabled(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadFromEnvClientPolicy(t *testing.T) {
+	tests := []struct {
+		name            string
+		env             map[string]string
+		wantErr         bool
+		errContains     string
+		wantTimeout     time.Duration
+		wantMaxRetries  int
+		wantBaseDelay   time.Duration
+		wantMaxDelay    time.Duration
+		wantRetryWrites bool
+		wantConcurrency int
+		wantTimezone    string
+	}{
+		{
+			name:            "defaults",
+			env:             map[string]string{},
+			wantTimeout:     DefaultRequestTimeout,
+			wantMaxRetries:  DefaultMaxRetries,
+			wantBaseDelay:   DefaultRetryBaseDelay,
+			wantMaxDelay:    DefaultRetryMaxDelay,
+			wantConcurrency: DefaultMaxConcurrency,
+			wantTimezone:    time.Local.String(),
+		},
+		{
+			name: "custom values",
+			env: map[string]string{
+				"SPARKYFITNESS_TIMEOUT":          "5s",
+				"SPARKYFITNESS_MAX_RETRIES":      "0",
+				"SPARKYFITNESS_RETRY_BASE_DELAY": "100ms",
+				"SPARKYFITNESS_RETRY_MAX_DELAY":  "2s",
+				"SPARKYFITNESS_RETRY_WRITES":     "true",
+				"SPARKYFITNESS_MAX_CONCURRENCY":  "8",
+				"SPARKYFITNESS_TIMEZONE":         "Asia/Jakarta",
+			},
+			wantTimeout:     5 * time.Second,
+			wantMaxRetries:  0,
+			wantBaseDelay:   100 * time.Millisecond,
+			wantMaxDelay:    2 * time.Second,
+			wantRetryWrites: true,
+			wantConcurrency: 8,
+			wantTimezone:    "Asia/Jakarta",
+		},
+		{
+			name:        "invalid timeout",
+			env:         map[string]string{"SPARKYFITNESS_TIMEOUT": "soon"},
+			wantErr:     true,
+			errContains: "SPARKYFITNESS_TIMEOUT",
+		},
+		{
+			name:        "negative max retries",
+			env:         map[string]string{"SPARKYFITNESS_MAX_RETRIES": "-1"},
+			wantErr:     true,
+			errContains: "SPARKYFITNESS_MAX_RETRIES",
+		},
+		{
+			name: "max delay below base delay",
+			env: map[string]string{
+				"SPARKYFITNESS_RETRY_BASE_DELAY": "5s",
+				"SPARKYFITNESS_RETRY_MAX_DELAY":  "1s",
+			},
+			wantErr:     true,
+			errContains: "SPARKYFITNESS_RETRY_MAX_DELAY",
+		},
+		{
+			name:        "invalid retry writes",
+			env:         map[string]string{"SPARKYFITNESS_RETRY_WRITES": "maybe"},
+			wantErr:     true,
+			errContains: "SPARKYFITNESS_RETRY_WRITES",
+		},
+		{
+			name:        "zero max concurrency",
+			env:         map[string]string{"SPARKYFITNESS_MAX_CONCURRENCY": "0"},
+			wantErr:     true,
+			errContains: "SPARKYFITNESS_MAX_CONCURRENCY",
+		},
+		{
+			name:        "unknown timezone",
+			env:         map[string]string{"SPARKYFITNESS_TIMEZONE": "Mars/Olympus_Mons"},
+			wantErr:     true,
+			errContains: "SPARKYFITNESS_TIMEZONE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SPARKYFITNESS_API_URL", "https://api.sparkyfitness.com")
+			t.Setenv("SPARKYFITNESS_API_KEY", "test-key")
+			for _, k := range []string{
+				"SPARKYFITNESS_TIMEOUT",
+				"SPARKYFITNESS_MAX_RETRIES",
+				"SPARKYFITNESS_RETRY_BASE_DELAY",
+				"SPARKYFITNESS_RETRY_MAX_DELAY",
+				"SPARKYFITNESS_RETRY_WRITES",
+				"SPARKYFITNESS_MAX_CONCURRENCY",
+				"SPARKYFITNESS_TIMEZONE",
+			} {
+				t.Setenv(k, "")
+			}
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			cfg, err := LoadFromEnv()
+
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("LoadFromEnv() expected error, got nil")
+					return
+				}
+				if !strings.Contains(err.Error(), tt.errContains) {
+					t.Errorf("LoadFromEnv() error = %v, should contain %q", err, tt.errContains)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Errorf("LoadFromEnv() unexpected error: %v", err)
+				return
+			}
+
+			if cfg.RequestTimeout != tt.wantTimeout {
+				t.Errorf("RequestTimeout = %v, want %v", cfg.RequestTimeout, tt.wantTimeout)
+			}
+			if cfg.MaxRetries != tt.wantMaxRetries {
+				t.Errorf("MaxRetries = %v, want %v", cfg.MaxRetries, tt.wantMaxRetries)
+			}
+			if cfg.RetryBaseDelay != tt.wantBaseDelay {
+				t.Errorf("RetryBaseDelay = %v, want %v", cfg.RetryBaseDelay, tt.wantBaseDelay)
+			}
+			if cfg.RetryMaxDelay != tt.wantMaxDelay {
+				t.Errorf("RetryMaxDelay = %v, want %v", cfg.RetryMaxDelay, tt.wantMaxDelay)
+			}
+			if cfg.RetryWrites != tt.wantRetryWrites {
+				t.Errorf("RetryWrites = %v, want %v", cfg.RetryWrites, tt.wantRetryWrites)
+			}
+			if cfg.MaxConcurrency != tt.wantConcurrency {
+				t.Errorf("MaxConcurrency = %v, want %v", cfg.MaxConcurrency, tt.wantConcurrency)
+			}
+			if cfg.Timezone.String() != tt.wantTimezone {
+				t.Errorf("Timezone = %v, want %v", cfg.Timezone, tt.wantTimezone)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package nutrition
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// KJPerKcal is the number of kilojoules in a kilocalorie
+const KJPerKcal = 4.184
+
+const (
+	// energyTolerance is the relative difference tolerated between a label's
+	// kcal and kJ values, which are often computed with separate factors
+	energyTolerance = 0.05
+	// energySlackKJ keeps small values from tripping the relative check
+	energySlackKJ = 10
+)
+
+// ErrEnergyMismatch is returned (wrapped) by ResolveEnergy when the kcal and
+// kJ values of a label disagree
+var ErrEnergyMismatch = errors.New("nutrition: kcal and kJ values disagree")
+
+// KJToKcal converts kilojoules to kilocalories
+func KJToKcal(kj float64) float64 {
+	return kj / KJPerKcal
+}
+
+// KcalToKJ converts kilocalories to kilojoules
+func KcalToKJ(kcal float64) float64 {
+	return kcal * KJPerKcal
+}
+
+// ResolveEnergy returns the energy in kcal from a kcal value, a kJ value or
+// both. When both are given the kcal value is used after checking that the
+// two agree; a mismatch usually means the values were swapped or misread.
+func ResolveEnergy(kcal, kj *float64) (float64, error) {
+	switch {
+	case kcal == nil && kj == nil:
+		return 0, fmt.Errorf("no energy value given")
+	case kj == nil:
+		return *kcal, nil
+	case kcal == nil:
+		return KJToKcal(*kj), nil
+	}
+
+	expected := KcalToKJ(*kcal)
+	if diff := math.Abs(expected - *kj); diff > energySlackKJ && diff > energyTolerance*math.Max(expected, *kj) {
+		hint := ""
+		if *kcal > *kj {
+			hint = "; the values look swapped"
+		}
+		return 0, fmt.Errorf("%w: %g kcal is %.0f kJ, not %g kJ%s", ErrEnergyMismatch, *kcal, expected, *kj, hint)
+	}
+	return *kcal, nil
+}
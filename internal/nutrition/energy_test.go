@@ -0,0 +1,53 @@
+package nutrition
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestResolveEnergy(t *testing.T) {
+	f := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name         string
+		kcal         *float64
+		kj           *float64
+		want         float64
+		wantErr      error
+		wantContains string
+	}{
+		{name: "kcal only", kcal: f(368), want: 368},
+		{name: "kJ only", kj: f(1540), want: 368.07},
+		{name: "both agree", kcal: f(368), kj: f(1540), want: 368},
+		{name: "both agree within label rounding", kcal: f(52), kj: f(222), want: 52},
+		{name: "both disagree", kcal: f(368), kj: f(1200), wantErr: ErrEnergyMismatch},
+		{name: "swapped", kcal: f(1540), kj: f(368), wantErr: ErrEnergyMismatch, wantContains: "swapped"},
+		{name: "neither", wantContains: "no energy value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveEnergy(tt.kcal, tt.kj)
+			if tt.wantErr != nil || tt.wantContains != "" {
+				if err == nil {
+					t.Fatalf("ResolveEnergy() = %v, want error", got)
+				}
+				if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Errorf("ResolveEnergy() error = %v, want %v", err, tt.wantErr)
+				}
+				if !strings.Contains(err.Error(), tt.wantContains) {
+					t.Errorf("ResolveEnergy() error = %q, should contain %q", err, tt.wantContains)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ResolveEnergy() unexpected error: %v", err)
+			}
+			if math.Abs(got-tt.want) > 0.01 {
+				t.Errorf("ResolveEnergy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
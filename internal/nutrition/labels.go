@@ -0,0 +1,81 @@
+package nutrition
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// SodiumPerGramSalt is the sodium in milligrams per gram of salt, as used
+// for the "salt" line of EU labels (salt = sodium × 2.5)
+const SodiumPerGramSalt = 400
+
+// DailyValue is the reference amount behind a %DV label value
+type DailyValue struct {
+	Amount float64
+	Unit   string
+}
+
+// DailyValues are the adult reference daily values of the US FDA label
+// rules (2016), keyed by nutrient field name. Amounts are in the units the
+// nutrient fields are stored in.
+var DailyValues = map[string]DailyValue{
+	"saturated_fat": {Amount: 20, Unit: "g"},
+	"cholesterol":   {Amount: 300, Unit: "mg"},
+	"sodium":        {Amount: 2300, Unit: "mg"},
+	"potassium":     {Amount: 4700, Unit: "mg"},
+	"dietary_fiber": {Amount: 28, Unit: "g"},
+	"vitamin_a":     {Amount: 900, Unit: "mcg"},
+	"vitamin_c":     {Amount: 90, Unit: "mg"},
+	"calcium":       {Amount: 1300, Unit: "mg"},
+	"iron":          {Amount: 18, Unit: "mg"},
+}
+
+const (
+	// sodiumTolerance is the relative difference tolerated between a label's
+	// salt and sodium values, which are rounded separately
+	sodiumTolerance = 0.1
+	// sodiumSlackMg keeps small values from tripping the relative check
+	sodiumSlackMg = 20
+)
+
+// ErrSodiumMismatch is returned (wrapped) by CheckSaltSodium when a label's
+// salt and sodium values disagree
+var ErrSodiumMismatch = errors.New("nutrition: salt and sodium values disagree")
+
+// SaltToSodium converts grams of salt to milligrams of sodium
+func SaltToSodium(saltGrams float64) float64 {
+	return saltGrams * SodiumPerGramSalt
+}
+
+// CheckSaltSodium reports whether a label's salt (g) and sodium (mg) values
+// describe the same amount
+func CheckSaltSodium(saltGrams, sodiumMg float64) error {
+	expected := SaltToSodium(saltGrams)
+	if diff := math.Abs(expected - sodiumMg); diff > sodiumSlackMg && diff > sodiumTolerance*math.Max(expected, sodiumMg) {
+		return fmt.Errorf("%w: %g g salt is %.0f mg sodium, not %g mg", ErrSodiumMismatch, saltGrams, expected, sodiumMg)
+	}
+	return nil
+}
+
+// FromPercentDV converts a %DV label value of a nutrient to its absolute
+// amount using DailyValues
+func FromPercentDV(field string, percent float64) (float64, DailyValue, error) {
+	dv, ok := DailyValues[field]
+	if !ok {
+		return 0, DailyValue{}, fmt.Errorf("no daily value for %q (supported: %s)", field, strings.Join(DailyValueFields(), ", "))
+	}
+	return percent / 100 * dv.Amount, dv, nil
+}
+
+// DailyValueFields lists the nutrients that can be given as %DV, sorted
+func DailyValueFields() []string {
+	fields := make([]string, 0, len(DailyValues))
+	for field := range DailyValues {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
@@ -0,0 +1,53 @@
+package nutrition
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckSaltSodium(t *testing.T) {
+	tests := []struct {
+		name    string
+		salt    float64
+		sodium  float64
+		wantErr bool
+	}{
+		{name: "exact", salt: 1.5, sodium: 600},
+		{name: "label rounding", salt: 0.03, sodium: 10},
+		{name: "mismatch", salt: 1.5, sodium: 1500, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckSaltSodium(tt.salt, tt.sodium)
+			if tt.wantErr != errors.Is(err, ErrSodiumMismatch) {
+				t.Errorf("CheckSaltSodium(%g, %g) error = %v, wantErr %v", tt.salt, tt.sodium, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFromPercentDV(t *testing.T) {
+	tests := []struct {
+		field   string
+		percent float64
+		want    float64
+		wantErr bool
+	}{
+		{field: "calcium", percent: 15, want: 195},
+		{field: "vitamin_a", percent: 10, want: 90},
+		{field: "iron", percent: 50, want: 9},
+		{field: "protein", percent: 10, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		got, _, err := FromPercentDV(tt.field, tt.percent)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("FromPercentDV(%s) error = %v, wantErr %v", tt.field, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("FromPercentDV(%s, %g) = %v, want %v", tt.field, tt.percent, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// Package nutrition implements nutrition math on top of the SparkyFitness
+// types: unit conversion and scaling of serving variants
+package nutrition
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Dimension is the physical quantity a unit measures
+type Dimension string
+
+const (
+	// DimensionMass covers weight units, converted via grams
+	DimensionMass Dimension = "mass"
+	// DimensionVolume covers volume units, converted via milliliters
+	DimensionVolume Dimension = "volume"
+)
+
+// Unit is a convertible serving unit
+type Unit struct {
+	// Name is the canonical unit name (e.g., "g", "fl oz")
+	Name string
+	// Dimension is the quantity the unit measures
+	Dimension Dimension
+	// Factor is the size of one unit in grams (mass) or milliliters (volume)
+	Factor float64
+}
+
+// Sentinel errors returned (wrapped) by Convert and the variant helpers
+var (
+	ErrUnknownUnit     = errors.New("nutrition: unknown unit")
+	ErrDensityRequired = errors.New("nutrition: density required to convert between mass and volume")
+)
+
+var (
+	microgram  = Unit{Name: "mcg", Dimension: DimensionMass, Factor: 0.000001}
+	milligram  = Unit{Name: "mg", Dimension: DimensionMass, Factor: 0.001}
+	gram       = Unit{Name: "g", Dimension: DimensionMass, Factor: 1}
+	kilogram   = Unit{Name: "kg", Dimension: DimensionMass, Factor: 1000}
+	ounce      = Unit{Name: "oz", Dimension: DimensionMass, Factor: 28.349523125}
+	pound      = Unit{Name: "lb", Dimension: DimensionMass, Factor: 453.59237}
+	milliliter = Unit{Name: "ml", Dimension: DimensionVolume, Factor: 1}
+	liter      = Unit{Name: "l", Dimension: DimensionVolume, Factor: 1000}
+	cup        = Unit{Name: "cup", Dimension: DimensionVolume, Factor: 236.5882365}
+	tablespoon = Unit{Name: "tbsp", Dimension: DimensionVolume, Factor: 14.78676478125}
+	teaspoon   = Unit{Name: "tsp", Dimension: DimensionVolume, Factor: 4.92892159375}
+	fluidOunce = Unit{Name: "fl oz", Dimension: DimensionVolume, Factor: 29.5735295625}
+)
+
+// units maps every accepted spelling to its unit. Volume units are US
+// customary measures.
+var units = map[string]Unit{
+	"mcg": microgram, "µg": microgram, "ug": microgram, "microgram": microgram, "micrograms": microgram,
+	"mg": milligram, "milligram": milligram, "milligrams": milligram,
+	"g": gram, "gr": gram, "gram": gram, "grams": gram,
+	"kg": kilogram, "kilogram": kilogram, "kilograms": kilogram,
+	"oz": ounce, "ounce": ounce, "ounces": ounce,
+	"lb": pound, "lbs": pound, "pound": pound, "pounds": pound,
+	"ml": milliliter, "milliliter": milliliter, "milliliters": milliliter, "millilitre": milliliter, "millilitres": milliliter,
+	"l": liter, "liter": liter, "liters": liter, "litre": liter, "litres": liter,
+	"cup": cup, "cups": cup,
+	"tbsp": tablespoon, "tablespoon": tablespoon, "tablespoons": tablespoon,
+	"tsp": teaspoon, "teaspoon": teaspoon, "teaspoons": teaspoon,
+	"fl oz": fluidOunce, "floz": fluidOunce, "fluid ounce": fluidOunce, "fluid ounces": fluidOunce,
+}
+
+// normalizeUnit lowercases a unit name and collapses whitespace and
+// trailing dots (e.g., " Fl.  Oz. " → "fl oz")
+func normalizeUnit(name string) string {
+	fields := strings.Fields(strings.ToLower(name))
+	for i, f := range fields {
+		fields[i] = strings.TrimRight(f, ".")
+	}
+	return strings.Join(fields, " ")
+}
+
+// LookupUnit returns the convertible unit for a serving unit name
+func LookupUnit(name string) (Unit, bool) {
+	u, ok := units[normalizeUnit(name)]
+	return u, ok
+}
+
+// SameUnit reports whether two unit names denote the same unit, including
+// units that cannot be converted (e.g., "piece" and "Piece")
+func SameUnit(a, b string) bool {
+	ua, okA := LookupUnit(a)
+	ub, okB := LookupUnit(b)
+	if okA && okB {
+		return ua == ub
+	}
+	return normalizeUnit(a) == normalizeUnit(b)
+}
+
+// Convert converts amount from one unit to another. Converting between mass
+// and volume uses density in g/ml and fails with ErrDensityRequired when
+// density is not positive. Identical units always convert, even if they are
+// not convertible units (e.g., "piece").
+func Convert(amount float64, from, to string, density float64) (float64, error) {
+	if SameUnit(from, to) {
+		return amount, nil
+	}
+
+	src, ok := LookupUnit(from)
+	if !ok {
+		return 0, fmt.Errorf("%w %q", ErrUnknownUnit, from)
+	}
+	dst, ok := LookupUnit(to)
+	if !ok {
+		return 0, fmt.Errorf("%w %q", ErrUnknownUnit, to)
+	}
+
+	base := amount * src.Factor
+	if src.Dimension != dst.Dimension {
+		if density <= 0 {
+			return 0, fmt.Errorf("%w (%s to %s)", ErrDensityRequired, src.Name, dst.Name)
+		}
+		if src.Dimension == DimensionVolume {
+			base *= density
+		} else {
+			base /= density
+		}
+	}
+
+	return base / dst.Factor, nil
+}
@@ -0,0 +1,69 @@
+package nutrition
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  float64
+		from    string
+		to      string
+		density float64
+		want    float64
+		wantErr error
+	}{
+		{name: "kg to g", amount: 1.5, from: "kg", to: "g", want: 1500},
+		{name: "mg to mcg", amount: 0.25, from: "mg", to: "µg", want: 250},
+		{name: "lb to oz", amount: 1, from: "lb", to: "oz", want: 16},
+		{name: "oz to g", amount: 2, from: "ounces", to: "grams", want: 56.699},
+		{name: "cup to tbsp", amount: 1, from: "cup", to: "tbsp", want: 16},
+		{name: "tbsp to tsp", amount: 1, from: "Tbsp.", to: "tsp", want: 3},
+		{name: "l to fl oz", amount: 1, from: "L", to: "fl  oz", want: 33.814},
+		{name: "same non-convertible unit", amount: 2, from: "piece", to: "Piece", want: 2},
+		{name: "ml to g with density", amount: 250, from: "ml", to: "g", density: 1.03, want: 257.5},
+		{name: "g to cup with density", amount: 473.1765, from: "g", to: "cups", density: 1, want: 2},
+		{name: "mass to volume without density", amount: 1, from: "g", to: "ml", wantErr: ErrDensityRequired},
+		{name: "unknown unit", amount: 1, from: "piece", to: "g", wantErr: ErrUnknownUnit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Convert(tt.amount, tt.from, tt.to, tt.density)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Convert() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Convert() unexpected error: %v", err)
+			}
+			if math.Abs(got-tt.want) > 0.001 {
+				t.Errorf("Convert() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSameUnit(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{a: "g", b: "grams", want: true},
+		{a: "fl oz", b: "Fluid Ounce", want: true},
+		{a: "oz", b: "fl oz", want: false},
+		{a: "slice", b: "Slice ", want: true},
+		{a: "slice", b: "piece", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := SameUnit(tt.a, tt.b); got != tt.want {
+			t.Errorf("SameUnit(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package nutrition
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+)
+
+// Severity tells how serious a label issue is
+type Severity string
+
+const (
+	// SeverityWarning marks values that are unusual but possible
+	SeverityWarning Severity = "warning"
+	// SeverityError marks values that cannot be right (e.g., negative amounts)
+	SeverityError Severity = "error"
+)
+
+// Issue is a problem found in a variant's nutrition label
+type Issue struct {
+	Field    string
+	Severity Severity
+	Message  string
+}
+
+// String formats the issue for error messages (e.g., "fat: ...")
+func (i Issue) String() string {
+	return fmt.Sprintf("%s: %s", i.Field, i.Message)
+}
+
+const (
+	// kcalPerGramProtein, kcalPerGramCarbs and kcalPerGramFat are the
+	// Atwater general factors
+	kcalPerGramProtein = 4
+	kcalPerGramCarbs   = 4
+	kcalPerGramFat     = 9
+
+	// atwaterTolerance is the relative difference between stated and
+	// computed energy tolerated for label rounding, fiber and alcohol
+	atwaterTolerance = 0.2
+	// atwaterSlackKcal keeps small servings from tripping the relative check
+	atwaterSlackKcal = 10
+	// roundingSlackGrams allows for labels rounding each amount separately
+	roundingSlackGrams = 0.5
+)
+
+// perHundredGramLimits are the highest plausible amounts per 100 g; pure
+// fat and table salt are the extremes
+var perHundredGramLimits = []struct {
+	field string
+	value func(sparkyfitness.Nutrients) float64
+	limit float64
+}{
+	{"calories", func(n sparkyfitness.Nutrients) float64 { return n.Calories }, 900},
+	{"sodium", func(n sparkyfitness.Nutrients) float64 { return n.Sodium }, 40000},
+	{"cholesterol", func(n sparkyfitness.Nutrients) float64 { return n.Cholesterol }, 3000},
+	{"potassium", func(n sparkyfitness.Nutrients) float64 { return n.Potassium }, 10000},
+}
+
+// ValidateLabel checks a variant's nutrition for the mistakes typical of
+// label extraction: negative values, energy that does not match the macros
+// (including kJ entered as kcal), sub-components exceeding their totals and
+// amounts that are implausible for the serving weight
+func ValidateLabel(v sparkyfitness.FoodVariant) []Issue {
+	var issues []Issue
+	add := func(field string, severity Severity, format string, args ...any) {
+		issues = append(issues, Issue{Field: field, Severity: severity, Message: fmt.Sprintf(format, args...)})
+	}
+	n := v.Nutrients
+
+	// Amounts can never be negative
+	for _, f := range nutrientFields(&n) {
+		if *f.value < 0 {
+			add(f.name, SeverityError, "must not be negative (got %g)", *f.value)
+		}
+	}
+
+	// Energy should match the Atwater estimate from the macros
+	estimate := n.Protein*kcalPerGramProtein + n.Carbs*kcalPerGramCarbs + n.Fat*kcalPerGramFat
+	if diff := math.Abs(n.Calories - estimate); estimate > 0 && diff > atwaterSlackKcal && diff > atwaterTolerance*math.Max(n.Calories, estimate) {
+		if math.Abs(n.Calories-KcalToKJ(estimate)) <= atwaterTolerance*KcalToKJ(estimate) {
+			add("calories", SeverityWarning, "%g looks like kJ rather than kcal: the macros give about %.0f kcal (%.0f kJ)",
+				n.Calories, estimate, KcalToKJ(estimate))
+		} else {
+			add("calories", SeverityWarning, "%g does not match the macros, which give about %.0f kcal (4 kcal/g protein and carbs, 9 kcal/g fat)",
+				n.Calories, estimate)
+		}
+	}
+
+	// Sub-components cannot exceed their totals
+	fats := n.SaturatedFat + n.MonounsaturatedFat + n.PolyunsaturatedFat + n.TransFat
+	if fats > n.Fat+roundingSlackGrams {
+		add("fat", SeverityError, "saturated, mono-, polyunsaturated and trans fat add up to %g g, more than the %g g total fat", fats, n.Fat)
+	}
+	if n.Sugars > n.Carbs+roundingSlackGrams {
+		add("sugars", SeverityError, "%g g is more than the %g g total carbs", n.Sugars, n.Carbs)
+	} else if n.Sugars+n.DietaryFiber > n.Carbs+roundingSlackGrams {
+		add("carbs", SeverityWarning, "sugars and fiber add up to %g g, more than the %g g carbs; "+
+			"fine only if the label lists carbs without fiber (EU style)", n.Sugars+n.DietaryFiber, n.Carbs)
+	}
+
+	// Amounts must fit in the serving when its weight is known
+	grams, err := Convert(v.ServingSize, v.ServingUnit, "g", 0)
+	if err != nil || grams <= 0 {
+		return issues
+	}
+	if macros := n.Protein + n.Carbs + n.Fat; macros > grams+roundingSlackGrams {
+		add("serving_size", SeverityError, "protein, carbs and fat add up to %g g, more than the %g g serving; "+
+			"the values may be for a different serving (e.g., per 100 g)", macros, grams)
+	}
+	per100g := n.Scale(100 / grams)
+	for _, l := range perHundredGramLimits {
+		if value := l.value(per100g); value > l.limit && !flagged(issues, l.field) {
+			add(l.field, SeverityWarning, "%.0f per 100 g is implausibly high (at most about %g)", value, l.limit)
+		}
+	}
+
+	return issues
+}
+
+// flagged reports whether issues already include one for field, so a single
+// mistake is not reported twice
+func flagged(issues []Issue, field string) bool {
+	for _, issue := range issues {
+		if issue.Field == field {
+			return true
+		}
+	}
+	return false
+}
+
+// nutrientField is a named nutrient amount
+type nutrientField struct {
+	name  string
+	value *float64
+}
+
+// nutrientFields lists every nutrient of n with its JSON name
+func nutrientFields(n *sparkyfitness.Nutrients) []nutrientField {
+	return []nutrientField{
+		{"calories", &n.Calories},
+		{"protein", &n.Protein},
+		{"carbs", &n.Carbs},
+		{"fat", &n.Fat},
+		{"saturated_fat", &n.SaturatedFat},
+		{"polyunsaturated_fat", &n.PolyunsaturatedFat},
+		{"monounsaturated_fat", &n.MonounsaturatedFat},
+		{"trans_fat", &n.TransFat},
+		{"cholesterol", &n.Cholesterol},
+		{"sodium", &n.Sodium},
+		{"potassium", &n.Potassium},
+		{"dietary_fiber", &n.DietaryFiber},
+		{"sugars", &n.Sugars},
+		{"vitamin_a", &n.VitaminA},
+		{"vitamin_c", &n.VitaminC},
+		{"calcium", &n.Calcium},
+		{"iron", &n.Iron},
+	}
+}
+
+// NutrientField returns the field of n with the given JSON name (e.g.,
+// "vitamin_c"), for code that addresses nutrients by name
+func NutrientField(n *sparkyfitness.Nutrients, name string) (*float64, bool) {
+	for _, f := range nutrientFields(n) {
+		if f.name == name {
+			return f.value, true
+		}
+	}
+	return nil, false
+}
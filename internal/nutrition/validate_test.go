@@ -0,0 +1,111 @@
+package nutrition
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+)
+
+func TestValidateLabel(t *testing.T) {
+	// Cheddar per 100 g; consistent with the 4/4/9 factors
+	cheddar := sparkyfitness.Nutrients{Calories: 403, Protein: 25, Carbs: 1.3, Fat: 33, SaturatedFat: 21, Sodium: 620}
+
+	tests := []struct {
+		name      string
+		size      float64
+		unit      string
+		modify    func(n *sparkyfitness.Nutrients)
+		want      []Issue
+		wantClean bool
+	}{
+		{
+			name:      "valid label",
+			size:      100,
+			unit:      "g",
+			wantClean: true,
+		},
+		{
+			name:   "negative value",
+			size:   100,
+			unit:   "g",
+			modify: func(n *sparkyfitness.Nutrients) { n.Iron = -1 },
+			want:   []Issue{{Field: "iron", Severity: SeverityError}},
+		},
+		{
+			name:   "kJ entered as kcal",
+			size:   100,
+			unit:   "g",
+			modify: func(n *sparkyfitness.Nutrients) { n.Calories = 1686 },
+			want:   []Issue{{Field: "calories", Severity: SeverityWarning, Message: "kJ"}},
+		},
+		{
+			name:   "calories do not match macros",
+			size:   100,
+			unit:   "g",
+			modify: func(n *sparkyfitness.Nutrients) { n.Calories = 200 },
+			want:   []Issue{{Field: "calories", Severity: SeverityWarning, Message: "does not match"}},
+		},
+		{
+			name:   "fat components exceed fat",
+			size:   100,
+			unit:   "g",
+			modify: func(n *sparkyfitness.Nutrients) { n.MonounsaturatedFat = 10; n.PolyunsaturatedFat = 3 },
+			want:   []Issue{{Field: "fat", Severity: SeverityError}},
+		},
+		{
+			name:   "sugars exceed carbs",
+			size:   100,
+			unit:   "g",
+			modify: func(n *sparkyfitness.Nutrients) { n.Sugars = 5 },
+			want:   []Issue{{Field: "sugars", Severity: SeverityError}},
+		},
+		{
+			name:   "fiber outside carbs",
+			size:   100,
+			unit:   "g",
+			modify: func(n *sparkyfitness.Nutrients) { n.Sugars = 1; n.DietaryFiber = 2 },
+			want:   []Issue{{Field: "carbs", Severity: SeverityWarning, Message: "EU"}},
+		},
+		{
+			name: "macros exceed serving weight",
+			size: 1,
+			unit: "oz",
+			want: []Issue{
+				{Field: "serving_size", Severity: SeverityError},
+				{Field: "calories", Severity: SeverityWarning, Message: "per 100 g"},
+			},
+		},
+		{
+			name:      "weight unknown",
+			size:      1,
+			unit:      "slice",
+			wantClean: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := cheddar
+			if tt.modify != nil {
+				tt.modify(&n)
+			}
+			got := ValidateLabel(sparkyfitness.FoodVariant{ServingSize: tt.size, ServingUnit: tt.unit, Nutrients: n})
+
+			if tt.wantClean {
+				if len(got) != 0 {
+					t.Errorf("ValidateLabel() = %v, want no issues", got)
+				}
+				return
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ValidateLabel() = %v, want %d issues", got, len(tt.want))
+			}
+			for i, want := range tt.want {
+				if got[i].Field != want.Field || got[i].Severity != want.Severity || !strings.Contains(got[i].Message, want.Message) {
+					t.Errorf("issue[%d] = %+v, want %s %s containing %q", i, got[i], want.Severity, want.Field, want.Message)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package nutrition
+
+import (
+	"fmt"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+)
+
+// ScaleVariant derives a variant for a different serving from an existing
+// one: the result has the requested serving size and unit and the source's
+// nutrients scaled to match. Density (g/ml) is only needed when the units
+// are of different dimensions. The result has no ID and is not the default.
+func ScaleVariant(v sparkyfitness.FoodVariant, size float64, unit string, density float64) (sparkyfitness.FoodVariant, error) {
+	if v.ServingSize <= 0 {
+		return sparkyfitness.FoodVariant{}, fmt.Errorf("source variant has no serving size")
+	}
+	if size <= 0 {
+		return sparkyfitness.FoodVariant{}, fmt.Errorf("serving size must be greater than 0")
+	}
+
+	// Express the new serving in the source unit
+	amount, err := Convert(size, unit, v.ServingUnit, density)
+	if err != nil {
+		return sparkyfitness.FoodVariant{}, err
+	}
+	factor := amount / v.ServingSize
+
+	return sparkyfitness.FoodVariant{
+		FoodID:          v.FoodID,
+		ServingSize:     size,
+		ServingUnit:     unit,
+		Nutrients:       v.Nutrients.Scale(factor),
+		GlycemicIndex:   v.GlycemicIndex,
+		CustomNutrients: scaleCustomNutrients(v.CustomNutrients, factor),
+	}, nil
+}
+
+// Normalize derives the per-100 g variant of a mass-based variant or the
+// per-100 ml variant of a volume-based one. Variants in other units (e.g.,
+// "piece") cannot be normalized.
+func Normalize(v sparkyfitness.FoodVariant) (sparkyfitness.FoodVariant, error) {
+	u, ok := LookupUnit(v.ServingUnit)
+	if !ok {
+		return sparkyfitness.FoodVariant{}, fmt.Errorf("%w %q: cannot normalize to 100 g or 100 ml", ErrUnknownUnit, v.ServingUnit)
+	}
+
+	base := gram
+	if u.Dimension == DimensionVolume {
+		base = milliliter
+	}
+	return ScaleVariant(v, 100, base.Name, 0)
+}
+
+// scaleCustomNutrients scales the numeric custom nutrient amounts and
+// copies the others unchanged
+func scaleCustomNutrients(custom map[string]interface{}, factor float64) map[string]interface{} {
+	if custom == nil {
+		return nil
+	}
+	scaled := make(map[string]interface{}, len(custom))
+	for name, value := range custom {
+		if amount, ok := value.(float64); ok {
+			value = amount * factor
+		}
+		scaled[name] = value
+	}
+	return scaled
+}
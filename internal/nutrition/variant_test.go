@@ -0,0 +1,127 @@
+package nutrition
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+)
+
+func TestScaleVariant(t *testing.T) {
+	rice := sparkyfitness.FoodVariant{
+		ID:              "v1",
+		FoodID:          "f1",
+		ServingSize:     100,
+		ServingUnit:     "g",
+		Nutrients:       sparkyfitness.Nutrients{Calories: 130, Protein: 2.7, Sodium: 1},
+		IsDefault:       true,
+		CustomNutrients: map[string]interface{}{"choline": 2.0, "note": "raw"},
+	}
+	milk := sparkyfitness.FoodVariant{ServingSize: 1, ServingUnit: "cup", Nutrients: sparkyfitness.Nutrients{Calories: 150}}
+
+	tests := []struct {
+		name         string
+		source       sparkyfitness.FoodVariant
+		size         float64
+		unit         string
+		density      float64
+		wantCalories float64
+		wantErr      error
+	}{
+		{name: "same unit", source: rice, size: 150, unit: "g", wantCalories: 195},
+		{name: "mass conversion", source: rice, size: 1, unit: "kg", wantCalories: 1300},
+		{name: "volume conversion", source: milk, size: 8, unit: "tbsp", wantCalories: 75},
+		{name: "volume to mass with density", source: milk, size: 100, unit: "g", density: 1.03, wantCalories: 61.56},
+		{name: "volume to mass without density", source: milk, size: 100, unit: "g", wantErr: ErrDensityRequired},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ScaleVariant(tt.source, tt.size, tt.unit, tt.density)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ScaleVariant() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ScaleVariant() unexpected error: %v", err)
+			}
+			if got.ServingSize != tt.size || got.ServingUnit != tt.unit {
+				t.Errorf("serving = %g %s, want %g %s", got.ServingSize, got.ServingUnit, tt.size, tt.unit)
+			}
+			if math.Abs(got.Calories-tt.wantCalories) > 0.01 {
+				t.Errorf("Calories = %v, want %v", got.Calories, tt.wantCalories)
+			}
+			if got.ID != "" || got.IsDefault {
+				t.Errorf("derived variant = %+v, want no ID and not default", got)
+			}
+		})
+	}
+
+	t.Run("scales all fields", func(t *testing.T) {
+		got, err := ScaleVariant(rice, 50, "g", 0)
+		if err != nil {
+			t.Fatalf("ScaleVariant() unexpected error: %v", err)
+		}
+		if got.Protein != 1.35 || got.Sodium != 0.5 || got.FoodID != "f1" {
+			t.Errorf("derived variant = %+v", got)
+		}
+		if got.CustomNutrients["choline"] != 1.0 || got.CustomNutrients["note"] != "raw" {
+			t.Errorf("CustomNutrients = %v", got.CustomNutrients)
+		}
+		if rice.CustomNutrients["choline"] != 2.0 {
+			t.Errorf("source custom nutrients were modified")
+		}
+	})
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name         string
+		source       sparkyfitness.FoodVariant
+		wantUnit     string
+		wantCalories float64
+		wantErr      bool
+	}{
+		{
+			name:         "mass",
+			source:       sparkyfitness.FoodVariant{ServingSize: 1, ServingUnit: "oz", Nutrients: sparkyfitness.Nutrients{Calories: 160}},
+			wantUnit:     "g",
+			wantCalories: 564.38,
+		},
+		{
+			name:         "volume",
+			source:       sparkyfitness.FoodVariant{ServingSize: 250, ServingUnit: "ml", Nutrients: sparkyfitness.Nutrients{Calories: 110}},
+			wantUnit:     "ml",
+			wantCalories: 44,
+		},
+		{
+			name:    "count",
+			source:  sparkyfitness.FoodVariant{ServingSize: 1, ServingUnit: "piece", Nutrients: sparkyfitness.Nutrients{Calories: 78}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Normalize(tt.source)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Normalize() expected error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Normalize() unexpected error: %v", err)
+			}
+			if got.ServingSize != 100 || got.ServingUnit != tt.wantUnit {
+				t.Errorf("serving = %g %s, want 100 %s", got.ServingSize, got.ServingUnit, tt.wantUnit)
+			}
+			if math.Abs(got.Calories-tt.wantCalories) > 0.01 {
+				t.Errorf("Calories = %v, want %v", got.Calories, tt.wantCalories)
+			}
+		})
+	}
+}
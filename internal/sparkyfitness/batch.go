@@ -0,0 +1,178 @@
+package sparkyfitness
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// CopyFoodEntriesRequest describes which diary entries to copy and where to
+type CopyFoodEntriesRequest struct {
+	// SourceDate is the date to copy from (YYYY-MM-DD)
+	SourceDate string
+	// SourceMealType restricts the copy to one meal ("" copies the whole day)
+	SourceMealType string
+	// TargetDate is the date to copy to (YYYY-MM-DD)
+	TargetDate string
+	// TargetMealType logs every copy to this meal ("" keeps each entry's meal)
+	TargetMealType string
+}
+
+// CopyFoodEntryResult reports the outcome of copying a single entry
+type CopyFoodEntryResult struct {
+	// Source is the entry that was copied
+	Source FoodEntry
+	// Entry is the new entry, nil if the copy failed
+	Entry *FoodEntry
+	// Err is the reason the copy failed, nil on success
+	Err error
+}
+
+// CopyFoodEntries copies diary entries from one date (and optionally meal) to
+// another by re-logging each entry. Entries are copied concurrently, at most
+// MaxConcurrency at once, and every outcome is reported in diary order, so a
+// partial failure leaves the successful copies in place. The returned error is
+// only set when the source entries cannot be listed.
+func (c *Client) CopyFoodEntries(ctx context.Context, req *CopyFoodEntriesRequest) ([]CopyFoodEntryResult, error) {
+	entries, err := c.ListFoodEntries(ctx, req.SourceDate)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []CopyFoodEntryResult
+	for _, entry := range entries {
+		if req.SourceMealType != "" && entry.MealType != req.SourceMealType {
+			continue
+		}
+		results = append(results, CopyFoodEntryResult{Source: entry})
+	}
+
+	c.Concurrently(len(results), func(i int) {
+		result := &results[i]
+
+		// Stop logging once the caller gives up, but report every entry
+		if err := ctx.Err(); err != nil {
+			result.Err = fmt.Errorf("not copied: %w", err)
+			return
+		}
+
+		mealType := result.Source.MealType
+		if req.TargetMealType != "" {
+			mealType = req.TargetMealType
+		}
+
+		result.Entry, result.Err = c.CreateFoodEntry(ctx, &CreateFoodEntryRequest{
+			FoodID:    result.Source.FoodID,
+			VariantID: result.Source.VariantID,
+			MealType:  mealType,
+			Quantity:  result.Source.Quantity,
+			Unit:      result.Source.Unit,
+			EntryDate: req.TargetDate,
+		})
+	})
+
+	return results, nil
+}
+
+// ListFoodEntriesMatching lists the diary entries between two dates,
+// inclusive, for which keep returns true. The backend cannot filter entries
+// itself, so the range is listed in one request and split in half whenever
+// the response is too large, and only the matching entries are kept. Entries
+// are returned in date order.
+func (c *Client) ListFoodEntriesMatching(ctx context.Context, startDate, endDate string, keep func(*FoodEntry) bool) ([]FoodEntry, error) {
+	start, err := time.Parse(time.DateOnly, startDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start date: %w", err)
+	}
+	end, err := time.Parse(time.DateOnly, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("invalid end date: %w", err)
+	}
+
+	var matched []FoodEntry
+	var scan func(from, to time.Time) error
+	scan = func(from, to time.Time) error {
+		entries, err := c.ListFoodEntriesRange(ctx, from.Format(time.DateOnly), to.Format(time.DateOnly))
+		if errors.Is(err, ErrResponseTooLarge) && to.After(from) {
+			// Day arithmetic, since time.Duration overflows after 292 years
+			days := (to.Unix() - from.Unix()) / (24 * 60 * 60)
+			mid := from.AddDate(0, 0, int(days/2))
+			if err := scan(from, mid); err != nil {
+				return err
+			}
+			return scan(mid.AddDate(0, 0, 1), to)
+		}
+		if err != nil {
+			return err
+		}
+		for i := range entries {
+			if keep(&entries[i]) {
+				matched = append(matched, entries[i])
+			}
+		}
+		return nil
+	}
+
+	if err := scan(start, end); err != nil {
+		return nil, err
+	}
+	return matched, nil
+}
+
+// LogMealResult reports the outcome of logging one meal component
+type LogMealResult struct {
+	// Food is the meal component
+	Food MealFood
+	// Entry is the new diary entry, nil if logging failed
+	Entry *FoodEntry
+	// Err is the reason logging failed, nil on success
+	Err error
+}
+
+// LogMeal logs every component of a saved meal to the diary as a separate
+// entry, with quantities multiplied by scale (1 logs the meal as saved).
+// Every outcome is reported; a partial failure leaves the logged entries in place.
+func (c *Client) LogMeal(ctx context.Context, meal *Meal, mealType, date string, scale float64) []LogMealResult {
+	results := make([]LogMealResult, 0, len(meal.Foods))
+	for _, food := range meal.Foods {
+		result := LogMealResult{Food: food}
+
+		if err := ctx.Err(); err != nil {
+			result.Err = fmt.Errorf("not logged: %w", err)
+			results = append(results, result)
+			continue
+		}
+
+		result.Entry, result.Err = c.CreateFoodEntry(ctx, &CreateFoodEntryRequest{
+			FoodID:    food.FoodID,
+			VariantID: food.VariantID,
+			MealType:  mealType,
+			Quantity:  food.Quantity * scale,
+			Unit:      food.Unit,
+			EntryDate: date,
+		})
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// Concurrently calls fn for every index in [0, n), running at most the
+// configured MaxConcurrency calls at once, and returns when all of them have
+// returned. fn must be safe for concurrent use. Every index is processed even
+// after the caller gives up, so fn should check its context and report items
+// it did not process, as the other batch operations do.
+func (c *Client) Concurrently(n int, fn func(i int)) {
+	sem := make(chan struct{}, c.concurrency)
+	var wg sync.WaitGroup
+	for i := range n {
+		sem <- struct{}{}
+		wg.Go(func() {
+			defer func() { <-sem }()
+			fn(i)
+		})
+	}
+	wg.Wait()
+}
@@ -6,18 +6,24 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"net/url"
 	"strconv"
+	"time"
 
 	"github.com/chickenzord/sparkyfitness-mcp/internal/config"
 )
 
+// maxResponseBytes caps how much of a backend response body is read
+const maxResponseBytes = 10 << 20
+
 // Client is a manual HTTP client for the SparkyFitness backend API
 type Client struct {
-	httpClient *http.Client
-	baseURL    string
-	config     *config.Config
+	httpClient  *http.Client
+	baseURL     string
+	config      *config.Config
+	concurrency int
 }
 
 // authInterceptor adds authentication to outgoing requests
@@ -48,18 +54,41 @@ func NewClient(cfg *config.Config) (*Client, error) {
 		return nil, fmt.Errorf("API key is required")
 	}
 
-	// Create HTTP client with auth interceptor
+	// Retry policy and per-attempt timeout from configuration
+	policy := retryPolicy{
+		timeout:     cfg.RequestTimeout,
+		maxRetries:  cfg.MaxRetries,
+		baseDelay:   cfg.RetryBaseDelay,
+		maxDelay:    cfg.RetryMaxDelay,
+		retryWrites: cfg.RetryWrites,
+	}
+	if policy.baseDelay <= 0 {
+		policy.baseDelay = config.DefaultRetryBaseDelay
+	}
+	if policy.maxDelay <= 0 {
+		policy.maxDelay = config.DefaultRetryMaxDelay
+	}
+
+	// Create HTTP client with retries wrapping the auth interceptor,
+	// so every attempt is authenticated
 	httpClient := &http.Client{
-		Transport: &authInterceptor{
+		Transport: newRetryTransport(policy, &authInterceptor{
 			apiKey: cfg.SparkyFitnessAPIKey,
 			next:   http.DefaultTransport,
-		},
+		}),
+	}
+
+	// Batch operations fall back to the default concurrency if unset
+	concurrency := cfg.MaxConcurrency
+	if concurrency <= 0 {
+		concurrency = config.DefaultMaxConcurrency
 	}
 
 	return &Client{
-		httpClient: httpClient,
-		baseURL:    cfg.SparkyFitnessAPIURL,
-		config:     cfg,
+		httpClient:  httpClient,
+		baseURL:     cfg.SparkyFitnessAPIURL,
+		config:      cfg,
+		concurrency: concurrency,
 	}, nil
 }
 
@@ -79,97 +108,297 @@ func (c *Client) SearchFoods(ctx context.Context, name string, broadMatch bool,
 
 	params.Set("limit", strconv.Itoa(limit))
 
-	// Build request URL
-	reqURL := fmt.Sprintf("%s/foods?%s", c.baseURL, params.Encode())
-
-	// Create request
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	var searchResp SearchFoodsResponse
+	if err := c.do(ctx, http.MethodGet, "/foods", params, nil, http.StatusOK, &searchResp); err != nil {
+		return nil, err
 	}
 
-	// Execute request (auth interceptor will add Bearer token)
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+	return searchResp.SearchResults, nil
+}
+
+// GetFood fetches a single food with its default variant
+// Backend endpoint: GET /foods/{id}
+func (c *Client) GetFood(ctx context.Context, foodID string) (*Food, error) {
+	var food Food
+	if err := c.do(ctx, http.MethodGet, "/foods/"+url.PathEscape(foodID), nil, nil, http.StatusOK, &food); err != nil {
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+	return &food, nil
+}
+
+// ListFoodVariants lists every variant of a food
+// Backend endpoint: GET /foods/food-variants?food_id={id}
+func (c *Client) ListFoodVariants(ctx context.Context, foodID string) ([]FoodVariant, error) {
+	params := url.Values{}
+	params.Set("food_id", foodID)
+
+	var variants []FoodVariant
+	if err := c.do(ctx, http.MethodGet, "/foods/food-variants", params, nil, http.StatusOK, &variants); err != nil {
+		return nil, err
 	}
 
-	// Check status code
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(body))
+	return variants, nil
+}
+
+// GetFoodVariant fetches a single food variant
+// Backend endpoint: GET /foods/food-variants/{id}
+func (c *Client) GetFoodVariant(ctx context.Context, variantID string) (*FoodVariant, error) {
+	var variant FoodVariant
+	if err := c.do(ctx, http.MethodGet, "/foods/food-variants/"+url.PathEscape(variantID), nil, nil, http.StatusOK, &variant); err != nil {
+		return nil, err
 	}
 
-	// Parse response
-	var searchResp SearchFoodsResponse
-	if err := json.Unmarshal(body, &searchResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	return &variant, nil
+}
+
+// CreateFood creates a new food together with its default variant
+// Backend endpoint: POST /foods
+// Returns 201 Created with the food and nested default variant
+func (c *Client) CreateFood(ctx context.Context, req *CreateFoodRequest) (*CreateFoodResponse, error) {
+	var createResp CreateFoodResponse
+	if err := c.do(ctx, http.MethodPost, "/foods", nil, req, http.StatusCreated, &createResp); err != nil {
+		return nil, err
 	}
 
-	return searchResp.SearchResults, nil
+	return &createResp, nil
 }
 
 // AddFoodVariant adds a new variant to an existing food
 // Backend endpoint: POST /foods/food-variants
 // Returns 201 Created with variant ID
 func (c *Client) AddFoodVariant(ctx context.Context, req *AddFoodVariantRequest) (*AddFoodVariantResponse, error) {
+	var addVariantResp AddFoodVariantResponse
+	if err := c.do(ctx, http.MethodPost, "/foods/food-variants", nil, req, http.StatusCreated, &addVariantResp); err != nil {
+		return nil, err
+	}
+
+	return &addVariantResp, nil
+}
+
+// UpdateFood replaces a food's metadata (name, brand, sharing)
+// Backend endpoint: PUT /foods/{id}
+// Returns 200 OK with the updated food
+func (c *Client) UpdateFood(ctx context.Context, foodID string, req *UpdateFoodRequest) (*Food, error) {
+	var food Food
+	if err := c.do(ctx, http.MethodPut, "/foods/"+url.PathEscape(foodID), nil, req, http.StatusOK, &food); err != nil {
+		return nil, err
+	}
+
+	return &food, nil
+}
+
+// UpdateFoodVariant replaces a variant's serving and nutrition data
+// Backend endpoint: PUT /foods/food-variants/{id}
+// Returns 200 OK with the updated variant
+func (c *Client) UpdateFoodVariant(ctx context.Context, variantID string, req *UpdateFoodVariantRequest) (*FoodVariant, error) {
+	var variant FoodVariant
+	if err := c.do(ctx, http.MethodPut, "/foods/food-variants/"+url.PathEscape(variantID), nil, req, http.StatusOK, &variant); err != nil {
+		return nil, err
+	}
+
+	return &variant, nil
+}
+
+// DeleteFood deletes a food together with all of its variants
+// Backend endpoint: DELETE /foods/{id}
+// Returns 200 OK
+func (c *Client) DeleteFood(ctx context.Context, foodID string) error {
+	return c.do(ctx, http.MethodDelete, "/foods/"+url.PathEscape(foodID), nil, nil, http.StatusOK, nil)
+}
+
+// DeleteFoodVariant deletes a single food variant
+// Backend endpoint: DELETE /foods/food-variants/{id}
+// Returns 200 OK
+func (c *Client) DeleteFoodVariant(ctx context.Context, variantID string) error {
+	return c.do(ctx, http.MethodDelete, "/foods/food-variants/"+url.PathEscape(variantID), nil, nil, http.StatusOK, nil)
+}
+
+// CreateFoodEntry logs a food variant to the food diary
+// Backend endpoint: POST /food-entries
+// Returns 201 Created with the entry, including the nutrient snapshot
+func (c *Client) CreateFoodEntry(ctx context.Context, req *CreateFoodEntryRequest) (*FoodEntry, error) {
+	var entry FoodEntry
+	if err := c.do(ctx, http.MethodPost, "/food-entries", nil, req, http.StatusCreated, &entry); err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// ListFoodEntries lists the food diary entries of a single date (YYYY-MM-DD)
+// Backend endpoint: GET /food-entries/by-date/{date}
+func (c *Client) ListFoodEntries(ctx context.Context, date string) ([]FoodEntry, error) {
+	var entries []FoodEntry
+	if err := c.do(ctx, http.MethodGet, "/food-entries/by-date/"+url.PathEscape(date), nil, nil, http.StatusOK, &entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// ListFoodEntriesRange lists the food diary entries between two dates, inclusive
+// Backend endpoint: GET /food-entries/range/{start}/{end}
+func (c *Client) ListFoodEntriesRange(ctx context.Context, startDate, endDate string) ([]FoodEntry, error) {
+	var entries []FoodEntry
+	path := "/food-entries/range/" + url.PathEscape(startDate) + "/" + url.PathEscape(endDate)
+	if err := c.do(ctx, http.MethodGet, path, nil, nil, http.StatusOK, &entries); err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// GetFoodEntry fetches a single food diary entry
+// Backend endpoint: GET /food-entries/{id}
+func (c *Client) GetFoodEntry(ctx context.Context, entryID string) (*FoodEntry, error) {
+	var entry FoodEntry
+	if err := c.do(ctx, http.MethodGet, "/food-entries/"+url.PathEscape(entryID), nil, nil, http.StatusOK, &entry); err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// UpdateFoodEntry replaces a food diary entry (full replacement; merge partial changes before calling)
+// Backend endpoint: PUT /food-entries/{id}
+// Returns 200 OK with the updated entry
+func (c *Client) UpdateFoodEntry(ctx context.Context, entryID string, req *UpdateFoodEntryRequest) (*FoodEntry, error) {
+	var entry FoodEntry
+	if err := c.do(ctx, http.MethodPut, "/food-entries/"+url.PathEscape(entryID), nil, req, http.StatusOK, &entry); err != nil {
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// DeleteFoodEntry deletes a food diary entry
+// Backend endpoint: DELETE /food-entries/{id}
+// Returns 200 OK
+func (c *Client) DeleteFoodEntry(ctx context.Context, entryID string) error {
+	return c.do(ctx, http.MethodDelete, "/food-entries/"+url.PathEscape(entryID), nil, nil, http.StatusOK, nil)
+}
+
+// CreateMeal saves a meal made of food variants and quantities
+// Backend endpoint: POST /meals
+// Returns 201 Created with the meal
+func (c *Client) CreateMeal(ctx context.Context, req *CreateMealRequest) (*Meal, error) {
+	var meal Meal
+	if err := c.do(ctx, http.MethodPost, "/meals", nil, req, http.StatusCreated, &meal); err != nil {
+		return nil, err
+	}
+
+	return &meal, nil
+}
+
+// SearchMeals lists saved meals whose name contains search (all meals if empty)
+// Backend endpoint: GET /meals?search={search}
+func (c *Client) SearchMeals(ctx context.Context, search string) ([]Meal, error) {
+	params := url.Values{}
+	if search != "" {
+		params.Set("search", search)
+	}
+
+	var meals []Meal
+	if err := c.do(ctx, http.MethodGet, "/meals", params, nil, http.StatusOK, &meals); err != nil {
+		return nil, err
+	}
+
+	return meals, nil
+}
+
+// GetMeal fetches a saved meal with its components
+// Backend endpoint: GET /meals/{id}
+func (c *Client) GetMeal(ctx context.Context, mealID string) (*Meal, error) {
+	var meal Meal
+	if err := c.do(ctx, http.MethodGet, "/meals/"+url.PathEscape(mealID), nil, nil, http.StatusOK, &meal); err != nil {
+		return nil, err
+	}
+
+	return &meal, nil
+}
+
+// ListCustomNutrients fetches the user's custom nutrient definitions
+// Backend endpoint: GET /custom-nutrients
+func (c *Client) ListCustomNutrients(ctx context.Context) ([]CustomNutrient, error) {
+	var nutrients []CustomNutrient
+	if err := c.do(ctx, http.MethodGet, "/custom-nutrients", nil, nil, http.StatusOK, &nutrients); err != nil {
+		return nil, err
+	}
+
+	return nutrients, nil
+}
+
+// do performs a backend API request and decodes the response.
+// path is relative to the base URL, query and body are optional (nil), and
+// out may be nil when the response body is not needed. Any status other than
+// wantStatus is returned as an *APIError.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body any, wantStatus int, out any) error {
 	// Build request URL
-	reqURL := fmt.Sprintf("%s/foods/food-variants", c.baseURL)
+	reqURL := c.baseURL + path
+	if len(query) > 0 {
+		reqURL += "?" + query.Encode()
+	}
 
 	// Marshal request body
-	body, err := json.Marshal(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	var reqBody io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reqBody = bytes.NewReader(payload)
 	}
 
 	// Create HTTP request
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, bytes.NewReader(body))
+	httpReq, err := http.NewRequestWithContext(ctx, method, reqURL, reqBody)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	if body != nil {
+		httpReq.Header.Set("Content-Type", "application/json")
 	}
 
-	// Set content type
-	httpReq.Header.Set("Content-Type", "application/json")
-
-	// Execute request (auth interceptor will add Bearer token)
+	// Execute request (retry transport and auth interceptor are applied here)
+	start := time.Now()
 	resp, err := c.httpClient.Do(httpReq)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		slog.Debug("SparkyFitness request failed", "method", method, "path", path, "error", err)
+		return fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	// Read response body
-	respBody, err := io.ReadAll(resp.Body)
+	// Read response body, refusing oversized payloads
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes+1))
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return fmt.Errorf("failed to read response body: %w", err)
 	}
-
-	// Check status code (backend returns 201 Created)
-	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(respBody))
+	if len(respBody) > maxResponseBytes {
+		return fmt.Errorf("%w: exceeds %d bytes", ErrResponseTooLarge, maxResponseBytes)
 	}
 
-	// Parse response
-	var addVariantResp AddFoodVariantResponse
-	if err := json.Unmarshal(respBody, &addVariantResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	slog.Debug("SparkyFitness request",
+		"method", method,
+		"path", path,
+		"status", resp.StatusCode,
+		"duration", time.Since(start),
+		"bytes", len(respBody),
+	)
+
+	// Check status code
+	if resp.StatusCode != wantStatus {
+		return NewAPIError(method, path, resp.StatusCode, respBody)
 	}
 
-	return &addVariantResp, nil
-}
+	if out == nil {
+		return nil
+	}
 
-// BaseURL returns the base URL of the API
-func (c *Client) BaseURL() string {
-	return c.baseURL
-}
+	// Parse response
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("%w: failed to parse response: %w", ErrInvalidResponse, err)
+	}
 
-// HTTPClient returns the underlying HTTP client
-func (c *Client) HTTPClient() *http.Client {
-	return c.httpClient
+	return nil
 }
@@ -0,0 +1,652 @@
+package sparkyfitness_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness/sparkyfitnesstest"
+)
+
+func seedRice(srv *sparkyfitnesstest.Server) sparkyfitness.Food {
+	srv.AddFood(sparkyfitness.Food{Name: "Steamed Brown Rice", IsCustom: true},
+		sparkyfitness.FoodVariant{ServingSize: 100, ServingUnit: "g", Nutrients: sparkyfitness.Nutrients{Calories: 140, Protein: 3.5, Carbs: 29.8, Fat: 0.9}},
+	)
+	return srv.AddFood(sparkyfitness.Food{Name: "Rice", IsCustom: true},
+		sparkyfitness.FoodVariant{ServingSize: 100, ServingUnit: "g", Nutrients: sparkyfitness.Nutrients{Calories: 130, Protein: 2.7, Carbs: 28.6, Fat: 0.3}},
+	)
+}
+
+func TestSearchFoods(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		broadMatch bool
+		limit      int
+		wantNames  []string
+	}{
+		{
+			name:       "broad match is case-insensitive substring",
+			query:      "RICE",
+			broadMatch: true,
+			limit:      10,
+			wantNames:  []string{"Steamed Brown Rice", "Rice"},
+		},
+		{
+			name:       "exact match",
+			query:      "rice",
+			broadMatch: false,
+			limit:      10,
+			wantNames:  []string{"Rice"},
+		},
+		{
+			name:       "limit is applied",
+			query:      "rice",
+			broadMatch: true,
+			limit:      1,
+			wantNames:  []string{"Steamed Brown Rice"},
+		},
+		{
+			name:       "no matches",
+			query:      "quinoa",
+			broadMatch: true,
+			limit:      10,
+			wantNames:  nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := sparkyfitnesstest.NewServer(t)
+			seedRice(srv)
+			client := srv.NewClient(t)
+
+			foods, err := client.SearchFoods(context.Background(), tt.query, tt.broadMatch, tt.limit)
+			if err != nil {
+				t.Fatalf("SearchFoods() unexpected error: %v", err)
+			}
+
+			if len(foods) != len(tt.wantNames) {
+				t.Fatalf("SearchFoods() returned %d foods, want %d", len(foods), len(tt.wantNames))
+			}
+			for i, food := range foods {
+				if food.Name != tt.wantNames[i] {
+					t.Errorf("foods[%d].Name = %q, want %q", i, food.Name, tt.wantNames[i])
+				}
+				if food.DefaultVariant == nil {
+					t.Errorf("foods[%d].DefaultVariant is nil", i)
+				}
+			}
+		})
+	}
+}
+
+func TestCreateFoodAndAddVariant(t *testing.T) {
+	srv := sparkyfitnesstest.NewServer(t)
+	client := srv.NewClient(t)
+	ctx := context.Background()
+
+	created, err := client.CreateFood(ctx, &sparkyfitness.CreateFoodRequest{
+		Name:        "Organic Quinoa",
+		Brand:       "Nature's Best",
+		IsCustom:    true,
+		ServingSize: 100,
+		ServingUnit: "g",
+		Nutrients:   sparkyfitness.Nutrients{Calories: 368, Protein: 14.1, Carbs: 64.2, Fat: 6.1},
+		IsDefault:   true,
+	})
+	if err != nil {
+		t.Fatalf("CreateFood() unexpected error: %v", err)
+	}
+	if created.DefaultVariant == nil || created.DefaultVariant.ID == "" {
+		t.Fatalf("CreateFood() returned no default variant ID")
+	}
+
+	added, err := client.AddFoodVariant(ctx, &sparkyfitness.AddFoodVariantRequest{
+		FoodID:      created.ID,
+		ServingSize: 1,
+		ServingUnit: "cup",
+		Nutrients:   sparkyfitness.Nutrients{Calories: 222},
+	})
+	if err != nil {
+		t.Fatalf("AddFoodVariant() unexpected error: %v", err)
+	}
+
+	food, variants, ok := srv.Food(created.ID)
+	if !ok {
+		t.Fatalf("created food not stored")
+	}
+	if food.Brand == nil || *food.Brand != "Nature's Best" {
+		t.Errorf("Brand = %v, want Nature's Best", food.Brand)
+	}
+	if len(variants) != 2 || variants[1].ID != added.ID {
+		t.Errorf("variants = %+v, want default + added variant %s", variants, added.ID)
+	}
+	if food.DefaultVariant.ID != created.DefaultVariant.ID {
+		t.Errorf("default variant changed to %s", food.DefaultVariant.ID)
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	t.Run("invalid API key is unauthorized", func(t *testing.T) {
+		srv := sparkyfitnesstest.NewServer(t)
+		cfg := srv.Config()
+		cfg.SparkyFitnessAPIKey = "wrong-key"
+		client, err := sparkyfitness.NewClient(cfg)
+		if err != nil {
+			t.Fatalf("NewClient() unexpected error: %v", err)
+		}
+
+		_, err = client.SearchFoods(context.Background(), "rice", true, 10)
+		if !sparkyfitness.IsUnauthorized(err) {
+			t.Errorf("SearchFoods() error = %v, want unauthorized", err)
+		}
+	})
+
+	t.Run("unknown food is not found", func(t *testing.T) {
+		srv := sparkyfitnesstest.NewServer(t)
+		client := srv.NewClient(t)
+
+		_, err := client.AddFoodVariant(context.Background(), &sparkyfitness.AddFoodVariantRequest{
+			FoodID:      "missing",
+			ServingSize: 1,
+			ServingUnit: "g",
+		})
+		if !sparkyfitness.IsNotFound(err) {
+			t.Errorf("AddFoodVariant() error = %v, want not found", err)
+		}
+
+		apiErr, ok := sparkyfitness.AsAPIError(err)
+		if !ok {
+			t.Fatalf("AsAPIError() = false")
+		}
+		if apiErr.Method != http.MethodPost || apiErr.Path != "/foods/food-variants" || apiErr.Message != "Food not found" {
+			t.Errorf("APIError = %+v", apiErr)
+		}
+	})
+
+	t.Run("missing name is a validation error", func(t *testing.T) {
+		srv := sparkyfitnesstest.NewServer(t)
+		client := srv.NewClient(t)
+
+		_, err := client.CreateFood(context.Background(), &sparkyfitness.CreateFoodRequest{ServingSize: 1, ServingUnit: "g"})
+		if !sparkyfitness.IsValidation(err) {
+			t.Errorf("CreateFood() error = %v, want validation", err)
+		}
+	})
+
+	t.Run("malformed JSON response", func(t *testing.T) {
+		srv := sparkyfitnesstest.NewServer(t)
+		srv.InjectFault(sparkyfitnesstest.Fault{Path: "/foods", Status: http.StatusOK, Body: `{"searchResults": [`})
+		client := srv.NewClient(t)
+
+		_, err := client.SearchFoods(context.Background(), "rice", true, 10)
+		if err == nil {
+			t.Fatalf("SearchFoods() expected error, got nil")
+		}
+		if _, ok := sparkyfitness.AsAPIError(err); ok || !errors.Is(err, sparkyfitness.ErrInvalidResponse) {
+			t.Errorf("SearchFoods() error = %v, want invalid response", err)
+		}
+	})
+}
+
+func TestClientRetriesAndTimeouts(t *testing.T) {
+	t.Run("transient 503 is retried", func(t *testing.T) {
+		srv := sparkyfitnesstest.NewServer(t)
+		seedRice(srv)
+		srv.InjectFault(sparkyfitnesstest.Fault{Method: http.MethodGet, Path: "/foods", Status: http.StatusServiceUnavailable, Times: 2})
+
+		cfg := srv.Config()
+		cfg.MaxRetries = 2
+		client, err := sparkyfitness.NewClient(cfg)
+		if err != nil {
+			t.Fatalf("NewClient() unexpected error: %v", err)
+		}
+
+		foods, err := client.SearchFoods(context.Background(), "rice", true, 10)
+		if err != nil {
+			t.Fatalf("SearchFoods() unexpected error: %v", err)
+		}
+		if len(foods) != 2 {
+			t.Errorf("SearchFoods() returned %d foods, want 2", len(foods))
+		}
+		if got := len(srv.Requests()); got != 3 {
+			t.Errorf("backend received %d requests, want 3", got)
+		}
+	})
+
+	t.Run("persistent 500 surfaces as server error", func(t *testing.T) {
+		srv := sparkyfitnesstest.NewServer(t)
+		srv.InjectFault(sparkyfitnesstest.Fault{Status: http.StatusInternalServerError, Body: `{"error":"boom"}`})
+		client := srv.NewClient(t)
+
+		_, err := client.SearchFoods(context.Background(), "rice", true, 10)
+		if !sparkyfitness.IsServerError(err) {
+			t.Errorf("SearchFoods() error = %v, want server error", err)
+		}
+	})
+
+	t.Run("slow backend hits the request timeout", func(t *testing.T) {
+		srv := sparkyfitnesstest.NewServer(t)
+		srv.InjectFault(sparkyfitnesstest.Fault{Latency: time.Second})
+
+		cfg := srv.Config()
+		cfg.RequestTimeout = 50 * time.Millisecond
+		client, err := sparkyfitness.NewClient(cfg)
+		if err != nil {
+			t.Fatalf("NewClient() unexpected error: %v", err)
+		}
+
+		start := time.Now()
+		_, err = client.SearchFoods(context.Background(), "rice", true, 10)
+		if err == nil {
+			t.Fatalf("SearchFoods() expected timeout error, got nil")
+		}
+		if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+			t.Errorf("SearchFoods() took %v, want it bounded by the request timeout", elapsed)
+		}
+	})
+}
+
+func TestGetFoodAndListVariants(t *testing.T) {
+	srv := sparkyfitnesstest.NewServer(t)
+	food := srv.AddFood(sparkyfitness.Food{Name: "Banana"},
+		sparkyfitness.FoodVariant{ServingSize: 100, ServingUnit: "g", Nutrients: sparkyfitness.Nutrients{Calories: 89}},
+		sparkyfitness.FoodVariant{ServingSize: 1, ServingUnit: "piece", Nutrients: sparkyfitness.Nutrients{Calories: 105}},
+	)
+	client := srv.NewClient(t)
+	ctx := context.Background()
+
+	got, err := client.GetFood(ctx, food.ID)
+	if err != nil {
+		t.Fatalf("GetFood() unexpected error: %v", err)
+	}
+	if got.Name != "Banana" || got.DefaultVariant == nil || got.DefaultVariant.ServingUnit != "g" {
+		t.Errorf("GetFood() = %+v", got)
+	}
+
+	variants, err := client.ListFoodVariants(ctx, food.ID)
+	if err != nil {
+		t.Fatalf("ListFoodVariants() unexpected error: %v", err)
+	}
+	if len(variants) != 2 || variants[1].ServingUnit != "piece" {
+		t.Errorf("ListFoodVariants() = %+v", variants)
+	}
+
+	if _, err := client.GetFood(ctx, "missing"); !sparkyfitness.IsNotFound(err) {
+		t.Errorf("GetFood(missing) error = %v, want not found", err)
+	}
+}
+
+func TestUpdateFoodAndVariant(t *testing.T) {
+	srv := sparkyfitnesstest.NewServer(t)
+	food := srv.AddFood(sparkyfitness.Food{Name: "Banan"},
+		sparkyfitness.FoodVariant{ServingSize: 100, ServingUnit: "g", Nutrients: sparkyfitness.Nutrients{Calories: 89}},
+	)
+	client := srv.NewClient(t)
+	ctx := context.Background()
+
+	updatedFood, err := client.UpdateFood(ctx, food.ID, &sparkyfitness.UpdateFoodRequest{Name: "Banana", SharedWithPublic: true})
+	if err != nil {
+		t.Fatalf("UpdateFood() unexpected error: %v", err)
+	}
+	if updatedFood.Name != "Banana" || !updatedFood.SharedWithPublic {
+		t.Errorf("UpdateFood() = %+v", updatedFood)
+	}
+
+	variant, err := client.GetFoodVariant(ctx, food.DefaultVariant.ID)
+	if err != nil {
+		t.Fatalf("GetFoodVariant() unexpected error: %v", err)
+	}
+	if variant.FoodID != food.ID {
+		t.Errorf("GetFoodVariant().FoodID = %q, want %q", variant.FoodID, food.ID)
+	}
+
+	updatedVariant, err := client.UpdateFoodVariant(ctx, variant.ID, &sparkyfitness.UpdateFoodVariantRequest{
+		FoodID:      food.ID,
+		ServingSize: 118,
+		ServingUnit: "g",
+		Nutrients:   sparkyfitness.Nutrients{Calories: 105},
+		IsDefault:   true,
+	})
+	if err != nil {
+		t.Fatalf("UpdateFoodVariant() unexpected error: %v", err)
+	}
+	if updatedVariant.ServingSize != 118 || updatedVariant.Calories != 105 {
+		t.Errorf("UpdateFoodVariant() = %+v", updatedVariant)
+	}
+
+	if _, err := client.GetFoodVariant(ctx, "missing"); !sparkyfitness.IsNotFound(err) {
+		t.Errorf("GetFoodVariant(missing) error = %v, want not found", err)
+	}
+}
+
+func TestDeleteFoodAndVariant(t *testing.T) {
+	srv := sparkyfitnesstest.NewServer(t)
+	food := srv.AddFood(sparkyfitness.Food{Name: "Banana"},
+		sparkyfitness.FoodVariant{ServingSize: 100, ServingUnit: "g"},
+		sparkyfitness.FoodVariant{ServingSize: 1, ServingUnit: "piece"},
+	)
+	client := srv.NewClient(t)
+	ctx := context.Background()
+
+	_, variants, _ := srv.Food(food.ID)
+	if err := client.DeleteFoodVariant(ctx, variants[1].ID); err != nil {
+		t.Fatalf("DeleteFoodVariant() unexpected error: %v", err)
+	}
+	if _, remaining, _ := srv.Food(food.ID); len(remaining) != 1 {
+		t.Errorf("variants after DeleteFoodVariant() = %d, want 1", len(remaining))
+	}
+
+	if err := client.DeleteFood(ctx, food.ID); err != nil {
+		t.Fatalf("DeleteFood() unexpected error: %v", err)
+	}
+	if _, _, ok := srv.Food(food.ID); ok {
+		t.Error("food still exists after DeleteFood()")
+	}
+
+	if err := client.DeleteFood(ctx, food.ID); !sparkyfitness.IsNotFound(err) {
+		t.Errorf("DeleteFood(deleted) error = %v, want not found", err)
+	}
+	if err := client.DeleteFoodVariant(ctx, "missing"); !sparkyfitness.IsNotFound(err) {
+		t.Errorf("DeleteFoodVariant(missing) error = %v, want not found", err)
+	}
+}
+
+func TestCreateFoodEntry(t *testing.T) {
+	srv := sparkyfitnesstest.NewServer(t)
+	food := seedRice(srv)
+	client := srv.NewClient(t)
+	ctx := context.Background()
+
+	entry, err := client.CreateFoodEntry(ctx, &sparkyfitness.CreateFoodEntryRequest{
+		FoodID:    food.ID,
+		VariantID: food.DefaultVariant.ID,
+		MealType:  sparkyfitness.MealTypeLunch,
+		Quantity:  150,
+		Unit:      "g",
+		EntryDate: "2025-01-15",
+	})
+	if err != nil {
+		t.Fatalf("CreateFoodEntry() unexpected error: %v", err)
+	}
+	if entry.ID == "" || entry.FoodName != "Rice" || entry.ServingSize != 100 {
+		t.Errorf("CreateFoodEntry() = %+v", entry)
+	}
+	if got := entry.LoggedNutrients().Calories; got != 195 {
+		t.Errorf("LoggedNutrients().Calories = %v, want 195", got)
+	}
+
+	_, err = client.CreateFoodEntry(ctx, &sparkyfitness.CreateFoodEntryRequest{
+		VariantID: food.DefaultVariant.ID,
+		MealType:  "brunch",
+		Quantity:  1,
+		EntryDate: "2025-01-15",
+	})
+	if !sparkyfitness.IsValidation(err) {
+		t.Errorf("CreateFoodEntry(invalid meal) error = %v, want validation", err)
+	}
+}
+
+func TestListFoodEntries(t *testing.T) {
+	srv := sparkyfitnesstest.NewServer(t)
+	food := seedRice(srv)
+	for _, date := range []string{"2025-01-14", "2025-01-15", "2025-01-15", "2025-01-17"} {
+		srv.AddFoodEntry(sparkyfitness.FoodEntry{VariantID: food.DefaultVariant.ID, MealType: sparkyfitness.MealTypeLunch, Quantity: 100, EntryDate: date})
+	}
+	client := srv.NewClient(t)
+	ctx := context.Background()
+
+	entries, err := client.ListFoodEntries(ctx, "2025-01-15")
+	if err != nil {
+		t.Fatalf("ListFoodEntries() unexpected error: %v", err)
+	}
+	if len(entries) != 2 || entries[0].FoodName != "Rice" || entries[0].Calories != 130 {
+		t.Errorf("ListFoodEntries() = %+v", entries)
+	}
+
+	entries, err = client.ListFoodEntriesRange(ctx, "2025-01-15", "2025-01-17")
+	if err != nil {
+		t.Fatalf("ListFoodEntriesRange() unexpected error: %v", err)
+	}
+	if len(entries) != 3 {
+		t.Errorf("ListFoodEntriesRange() returned %d entries, want 3", len(entries))
+	}
+
+	if _, err := client.ListFoodEntries(ctx, "yesterday"); !sparkyfitness.IsValidation(err) {
+		t.Errorf("ListFoodEntries(invalid) error = %v, want validation", err)
+	}
+}
+
+func TestListFoodEntriesMatching(t *testing.T) {
+	srv := sparkyfitnesstest.NewServer(t)
+	food := seedRice(srv)
+	other := srv.AddFood(sparkyfitness.Food{Name: "Egg"},
+		sparkyfitness.FoodVariant{ServingSize: 1, ServingUnit: "piece", Nutrients: sparkyfitness.Nutrients{Calories: 78}},
+	)
+	srv.AddFoodEntry(sparkyfitness.FoodEntry{VariantID: food.DefaultVariant.ID, MealType: sparkyfitness.MealTypeLunch, Quantity: 100, EntryDate: "2019-03-01"})
+	srv.AddFoodEntry(sparkyfitness.FoodEntry{VariantID: other.DefaultVariant.ID, MealType: sparkyfitness.MealTypeLunch, Quantity: 1, EntryDate: "2024-06-01"})
+	srv.AddFoodEntry(sparkyfitness.FoodEntry{VariantID: food.DefaultVariant.ID, MealType: sparkyfitness.MealTypeDinner, Quantity: 200, EntryDate: "2025-01-15"})
+	client := srv.NewClient(t)
+
+	// The whole range is over the response size limit, so it must be split
+	srv.InjectFault(sparkyfitnesstest.Fault{
+		Method: http.MethodGet,
+		Path:   "/food-entries/range/1900-01-01/9999-12-31",
+		Status: http.StatusOK,
+		Body:   "[" + strings.Repeat(" ", 10<<20) + "]",
+		Times:  1,
+	})
+
+	entries, err := client.ListFoodEntriesMatching(context.Background(), "1900-01-01", "9999-12-31", func(e *sparkyfitness.FoodEntry) bool {
+		return e.FoodID == food.ID
+	})
+	if err != nil {
+		t.Fatalf("ListFoodEntriesMatching() unexpected error: %v", err)
+	}
+	if len(entries) != 2 || entries[0].EntryDate != "2019-03-01" || entries[1].EntryDate != "2025-01-15" {
+		t.Errorf("ListFoodEntriesMatching() = %+v, want both rice entries in date order", entries)
+	}
+
+	// A single day over the limit cannot be split further
+	srv.InjectFault(sparkyfitnesstest.Fault{
+		Method: http.MethodGet,
+		Path:   "/food-entries/range/2025-01-15/2025-01-15",
+		Status: http.StatusOK,
+		Body:   "[" + strings.Repeat(" ", 10<<20) + "]",
+	})
+	_, err = client.ListFoodEntriesMatching(context.Background(), "2025-01-15", "2025-01-15", func(*sparkyfitness.FoodEntry) bool { return true })
+	if !errors.Is(err, sparkyfitness.ErrResponseTooLarge) || !errors.Is(err, sparkyfitness.ErrInvalidResponse) {
+		t.Errorf("ListFoodEntriesMatching(one huge day) error = %v, want response too large", err)
+	}
+}
+
+func TestUpdateAndDeleteFoodEntry(t *testing.T) {
+	srv := sparkyfitnesstest.NewServer(t)
+	food := srv.AddFood(sparkyfitness.Food{Name: "Rice"},
+		sparkyfitness.FoodVariant{ServingSize: 100, ServingUnit: "g", Nutrients: sparkyfitness.Nutrients{Calories: 130}},
+		sparkyfitness.FoodVariant{ServingSize: 1, ServingUnit: "cup", Nutrients: sparkyfitness.Nutrients{Calories: 205}},
+	)
+	_, variants, _ := srv.Food(food.ID)
+	entry := srv.AddFoodEntry(sparkyfitness.FoodEntry{VariantID: variants[0].ID, MealType: sparkyfitness.MealTypeBreakfast, Quantity: 100, EntryDate: "2025-01-15"})
+	client := srv.NewClient(t)
+	ctx := context.Background()
+
+	got, err := client.GetFoodEntry(ctx, entry.ID)
+	if err != nil {
+		t.Fatalf("GetFoodEntry() unexpected error: %v", err)
+	}
+	if got.VariantID != variants[0].ID || got.Calories != 130 {
+		t.Errorf("GetFoodEntry() = %+v", got)
+	}
+
+	updated, err := client.UpdateFoodEntry(ctx, entry.ID, &sparkyfitness.UpdateFoodEntryRequest{
+		FoodID:    food.ID,
+		VariantID: variants[1].ID,
+		MealType:  sparkyfitness.MealTypeLunch,
+		Quantity:  2,
+		Unit:      "cup",
+		EntryDate: "2025-01-16",
+	})
+	if err != nil {
+		t.Fatalf("UpdateFoodEntry() unexpected error: %v", err)
+	}
+	if updated.MealType != "lunch" || updated.EntryDate != "2025-01-16" || updated.LoggedNutrients().Calories != 410 {
+		t.Errorf("UpdateFoodEntry() = %+v", updated)
+	}
+
+	if err := client.DeleteFoodEntry(ctx, entry.ID); err != nil {
+		t.Fatalf("DeleteFoodEntry() unexpected error: %v", err)
+	}
+	if _, err := client.GetFoodEntry(ctx, entry.ID); !sparkyfitness.IsNotFound(err) {
+		t.Errorf("GetFoodEntry(deleted) error = %v, want not found", err)
+	}
+}
+
+func TestCopyFoodEntries(t *testing.T) {
+	srv := sparkyfitnesstest.NewServer(t)
+	food := seedRice(srv)
+	srv.AddFoodEntry(sparkyfitness.FoodEntry{VariantID: food.DefaultVariant.ID, MealType: sparkyfitness.MealTypeBreakfast, Quantity: 100, EntryDate: "2025-01-15"})
+	srv.AddFoodEntry(sparkyfitness.FoodEntry{VariantID: food.DefaultVariant.ID, MealType: sparkyfitness.MealTypeBreakfast, Quantity: 50, EntryDate: "2025-01-15"})
+	srv.AddFoodEntry(sparkyfitness.FoodEntry{VariantID: food.DefaultVariant.ID, MealType: sparkyfitness.MealTypeDinner, Quantity: 200, EntryDate: "2025-01-15"})
+
+	// Copy one entry at a time so the fault below hits the first entry
+	cfg := srv.Config()
+	cfg.MaxConcurrency = 1
+	client, err := sparkyfitness.NewClient(cfg)
+	if err != nil {
+		t.Fatalf("NewClient() unexpected error: %v", err)
+	}
+	ctx := context.Background()
+
+	// Fail the first copy only
+	srv.InjectFault(sparkyfitnesstest.Fault{Method: http.MethodPost, Path: "/food-entries", Status: http.StatusBadRequest, Body: `{"error":"boom"}`, Times: 1})
+
+	results, err := client.CopyFoodEntries(ctx, &sparkyfitness.CopyFoodEntriesRequest{
+		SourceDate:     "2025-01-15",
+		SourceMealType: sparkyfitness.MealTypeBreakfast,
+		TargetDate:     "2025-01-16",
+		TargetMealType: sparkyfitness.MealTypeLunch,
+	})
+	if err != nil {
+		t.Fatalf("CopyFoodEntries() unexpected error: %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("CopyFoodEntries() returned %d results, want 2", len(results))
+	}
+	if results[0].Err == nil || !sparkyfitness.IsValidation(results[0].Err) {
+		t.Errorf("results[0].Err = %v, want validation error", results[0].Err)
+	}
+	if results[1].Err != nil || results[1].Entry == nil {
+		t.Fatalf("results[1] = %+v, want success", results[1])
+	}
+	if got := results[1].Entry; got.EntryDate != "2025-01-16" || got.MealType != "lunch" || got.Quantity != 50 {
+		t.Errorf("copied entry = %+v", got)
+	}
+}
+
+func TestConcurrently(t *testing.T) {
+	srv := sparkyfitnesstest.NewServer(t)
+	cfg := srv.Config()
+	cfg.MaxConcurrency = 3
+	client, err := sparkyfitness.NewClient(cfg)
+	if err != nil {
+		t.Fatalf("NewClient() unexpected error: %v", err)
+	}
+
+	var mu sync.Mutex
+	running, peak := 0, 0
+	done := make([]bool, 10)
+	client.Concurrently(len(done), func(i int) {
+		mu.Lock()
+		running++
+		peak = max(peak, running)
+		mu.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		done[i] = true
+		mu.Unlock()
+	})
+
+	if peak > 3 {
+		t.Errorf("peak concurrency = %d, want at most 3", peak)
+	}
+	for i, ok := range done {
+		if !ok {
+			t.Errorf("index %d not processed", i)
+		}
+	}
+}
+
+func TestMeals(t *testing.T) {
+	srv := sparkyfitnesstest.NewServer(t)
+	food := seedRice(srv)
+	client := srv.NewClient(t)
+	ctx := context.Background()
+
+	meal, err := client.CreateMeal(ctx, &sparkyfitness.CreateMealRequest{
+		Name:  "Rice Bowl",
+		Foods: []sparkyfitness.MealFood{{FoodID: food.ID, VariantID: food.DefaultVariant.ID, Quantity: 200, Unit: "g"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateMeal() unexpected error: %v", err)
+	}
+	if meal.ID == "" || len(meal.Foods) != 1 || meal.Foods[0].FoodName != "Rice" {
+		t.Errorf("CreateMeal() = %+v", meal)
+	}
+
+	meals, err := client.SearchMeals(ctx, "bowl")
+	if err != nil {
+		t.Fatalf("SearchMeals() unexpected error: %v", err)
+	}
+	if len(meals) != 1 || meals[0].ID != meal.ID {
+		t.Errorf("SearchMeals() = %+v", meals)
+	}
+
+	got, err := client.GetMeal(ctx, meal.ID)
+	if err != nil {
+		t.Fatalf("GetMeal() unexpected error: %v", err)
+	}
+
+	results := client.LogMeal(ctx, got, sparkyfitness.MealTypeDinner, "2025-01-15", 0.5)
+	if len(results) != 1 || results[0].Err != nil {
+		t.Fatalf("LogMeal() = %+v", results)
+	}
+	if entry := results[0].Entry; entry.Quantity != 100 || entry.MealType != "dinner" {
+		t.Errorf("logged entry = %+v", entry)
+	}
+
+	if _, err := client.GetMeal(ctx, "missing"); !sparkyfitness.IsNotFound(err) {
+		t.Errorf("GetMeal(missing) error = %v, want not found", err)
+	}
+}
+
+func TestListCustomNutrients(t *testing.T) {
+	srv := sparkyfitnesstest.NewServer(t)
+	client := srv.NewClient(t)
+
+	nutrients, err := client.ListCustomNutrients(context.Background())
+	if err != nil {
+		t.Fatalf("ListCustomNutrients() unexpected error: %v", err)
+	}
+	if len(nutrients) != 0 {
+		t.Errorf("ListCustomNutrients() = %+v, want none", nutrients)
+	}
+
+	caffeine := srv.AddCustomNutrient("Caffeine", "mg")
+	nutrients, err = client.ListCustomNutrients(context.Background())
+	if err != nil {
+		t.Fatalf("ListCustomNutrients() unexpected error: %v", err)
+	}
+	if len(nutrients) != 1 || nutrients[0] != caffeine {
+		t.Errorf("ListCustomNutrients() = %+v, want [%+v]", nutrients, caffeine)
+	}
+}
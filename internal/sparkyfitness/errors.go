@@ -0,0 +1,216 @@
+package sparkyfitness
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrorKind classifies backend API failures so callers can react to them
+// without inspecting raw status codes
+type ErrorKind string
+
+const (
+	// ErrorKindAuth means the API key was missing, invalid or lacks permission (401/403)
+	ErrorKindAuth ErrorKind = "auth"
+	// ErrorKindNotFound means the requested resource does not exist (404)
+	ErrorKindNotFound ErrorKind = "not_found"
+	// ErrorKindConflict means the request conflicts with existing data (409)
+	ErrorKindConflict ErrorKind = "conflict"
+	// ErrorKindValidation means the backend rejected the request payload (400/422)
+	ErrorKindValidation ErrorKind = "validation"
+	// ErrorKindRateLimited means the backend is throttling requests (429)
+	ErrorKindRateLimited ErrorKind = "rate_limited"
+	// ErrorKindServer means the backend failed to process a valid request (5xx)
+	ErrorKindServer ErrorKind = "server"
+	// ErrorKindUnknown covers any other unexpected status code
+	ErrorKindUnknown ErrorKind = "unknown"
+)
+
+// Sentinel errors matched by APIError.Is, one per ErrorKind.
+// Use errors.Is(err, sparkyfitness.ErrNotFound) to test a classification.
+var (
+	ErrUnauthorized = errors.New("sparkyfitness: unauthorized")
+	ErrNotFound     = errors.New("sparkyfitness: not found")
+	ErrConflict     = errors.New("sparkyfitness: conflict")
+	ErrValidation   = errors.New("sparkyfitness: validation failed")
+	ErrRateLimited  = errors.New("sparkyfitness: rate limited")
+	ErrServer       = errors.New("sparkyfitness: server error")
+)
+
+// ErrInvalidResponse is wrapped by errors for responses that have the
+// expected status but cannot be used: oversized or malformed bodies
+var ErrInvalidResponse = errors.New("sparkyfitness: invalid response")
+
+// ErrResponseTooLarge is wrapped by errors for responses over the size
+// limit; it also matches ErrInvalidResponse
+var ErrResponseTooLarge = fmt.Errorf("%w: response body too large", ErrInvalidResponse)
+
+// maxErrorBodyLength caps how much of an unparseable error body is kept in the message
+const maxErrorBodyLength = 512
+
+// APIError is returned by Client methods when the backend responds with an
+// unexpected status code
+type APIError struct {
+	// StatusCode is the HTTP status code returned by the backend
+	StatusCode int
+	// Code is the backend error code, if the response body carried one
+	Code string
+	// Message is the backend error message (or a truncated raw body)
+	Message string
+	// Method is the HTTP method of the failed request
+	Method string
+	// Path is the API path of the failed request, without the base URL
+	Path string
+	// Kind is the classification derived from StatusCode
+	Kind ErrorKind
+}
+
+// NewAPIError builds an APIError from a backend response.
+// The body is parsed as the backend's JSON error envelope when possible.
+func NewAPIError(method, path string, statusCode int, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		Method:     method,
+		Path:       path,
+		Kind:       classifyStatus(statusCode),
+	}
+
+	// Backend error envelope: {"error": "..."} or {"message": "...", "code": "..."}
+	var envelope struct {
+		Error   json.RawMessage `json:"error"`
+		Message string          `json:"message"`
+		Code    string          `json:"code"`
+	}
+	if err := json.Unmarshal(body, &envelope); err == nil {
+		apiErr.Code = envelope.Code
+		apiErr.Message = envelope.Message
+
+		if len(envelope.Error) > 0 {
+			// "error" is usually a string but some handlers nest an object
+			var errString string
+			var errObject struct {
+				Message string `json:"message"`
+				Code    string `json:"code"`
+			}
+			if json.Unmarshal(envelope.Error, &errString) == nil {
+				if apiErr.Message == "" {
+					apiErr.Message = errString
+				} else if apiErr.Code == "" {
+					apiErr.Code = errString
+				}
+			} else if json.Unmarshal(envelope.Error, &errObject) == nil {
+				if apiErr.Message == "" {
+					apiErr.Message = errObject.Message
+				}
+				if apiErr.Code == "" {
+					apiErr.Code = errObject.Code
+				}
+			}
+		}
+	}
+
+	// Fall back to the raw body so no information is lost
+	if apiErr.Message == "" {
+		raw := strings.TrimSpace(string(body))
+		if len(raw) > maxErrorBodyLength {
+			raw = raw[:maxErrorBodyLength] + "..."
+		}
+		apiErr.Message = raw
+	}
+
+	return apiErr
+}
+
+// classifyStatus maps an HTTP status code to an ErrorKind
+func classifyStatus(statusCode int) ErrorKind {
+	switch {
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		return ErrorKindAuth
+	case statusCode == http.StatusNotFound:
+		return ErrorKindNotFound
+	case statusCode == http.StatusConflict:
+		return ErrorKindConflict
+	case statusCode == http.StatusBadRequest || statusCode == http.StatusUnprocessableEntity:
+		return ErrorKindValidation
+	case statusCode == http.StatusTooManyRequests:
+		return ErrorKindRateLimited
+	case statusCode >= 500:
+		return ErrorKindServer
+	default:
+		return ErrorKindUnknown
+	}
+}
+
+// Error implements the error interface
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("%s %s: status %d", e.Method, e.Path, e.StatusCode)
+	if e.Code != "" {
+		msg += fmt.Sprintf(" (%s)", e.Code)
+	}
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	return msg
+}
+
+// Is reports whether target is the sentinel error for this error's kind
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.Kind == ErrorKindAuth
+	case ErrNotFound:
+		return e.Kind == ErrorKindNotFound
+	case ErrConflict:
+		return e.Kind == ErrorKindConflict
+	case ErrValidation:
+		return e.Kind == ErrorKindValidation
+	case ErrRateLimited:
+		return e.Kind == ErrorKindRateLimited
+	case ErrServer:
+		return e.Kind == ErrorKindServer
+	default:
+		return false
+	}
+}
+
+// AsAPIError returns the APIError wrapped in err, if any
+func AsAPIError(err error) (*APIError, bool) {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr, true
+	}
+	return nil, false
+}
+
+// IsUnauthorized reports whether err is an authentication/authorization failure
+func IsUnauthorized(err error) bool {
+	return errors.Is(err, ErrUnauthorized)
+}
+
+// IsNotFound reports whether err is a not-found failure
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+// IsConflict reports whether err is a conflict failure
+func IsConflict(err error) bool {
+	return errors.Is(err, ErrConflict)
+}
+
+// IsValidation reports whether err is a request validation failure
+func IsValidation(err error) bool {
+	return errors.Is(err, ErrValidation)
+}
+
+// IsRateLimited reports whether err is a rate-limit failure
+func IsRateLimited(err error) bool {
+	return errors.Is(err, ErrRateLimited)
+}
+
+// IsServerError reports whether err is a backend server failure
+func IsServerError(err error) bool {
+	return errors.Is(err, ErrServer)
+}
@@ -0,0 +1,125 @@
+package sparkyfitness
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestNewAPIError(t *testing.T) {
+	tests := []struct {
+		name        string
+		statusCode  int
+		body        string
+		wantKind    ErrorKind
+		wantCode    string
+		wantMessage string
+		wantIs      error
+	}{
+		{
+			name:        "unauthorized with error string",
+			statusCode:  http.StatusUnauthorized,
+			body:        `{"error":"Invalid API key"}`,
+			wantKind:    ErrorKindAuth,
+			wantMessage: "Invalid API key",
+			wantIs:      ErrUnauthorized,
+		},
+		{
+			name:        "forbidden is auth",
+			statusCode:  http.StatusForbidden,
+			body:        `{"message":"Forbidden"}`,
+			wantKind:    ErrorKindAuth,
+			wantMessage: "Forbidden",
+			wantIs:      ErrUnauthorized,
+		},
+		{
+			name:        "not found with code",
+			statusCode:  http.StatusNotFound,
+			body:        `{"message":"Food not found","code":"FOOD_NOT_FOUND"}`,
+			wantKind:    ErrorKindNotFound,
+			wantCode:    "FOOD_NOT_FOUND",
+			wantMessage: "Food not found",
+			wantIs:      ErrNotFound,
+		},
+		{
+			name:        "conflict",
+			statusCode:  http.StatusConflict,
+			body:        `{"error":"Variant already exists"}`,
+			wantKind:    ErrorKindConflict,
+			wantMessage: "Variant already exists",
+			wantIs:      ErrConflict,
+		},
+		{
+			name:        "validation with nested error object",
+			statusCode:  http.StatusBadRequest,
+			body:        `{"error":{"message":"serving_size is required","code":"VALIDATION"}}`,
+			wantKind:    ErrorKindValidation,
+			wantCode:    "VALIDATION",
+			wantMessage: "serving_size is required",
+			wantIs:      ErrValidation,
+		},
+		{
+			name:        "rate limited",
+			statusCode:  http.StatusTooManyRequests,
+			body:        `{"error":"Too many requests"}`,
+			wantKind:    ErrorKindRateLimited,
+			wantMessage: "Too many requests",
+			wantIs:      ErrRateLimited,
+		},
+		{
+			name:        "server error with plain text body",
+			statusCode:  http.StatusBadGateway,
+			body:        "Bad Gateway\n",
+			wantKind:    ErrorKindServer,
+			wantMessage: "Bad Gateway",
+			wantIs:      ErrServer,
+		},
+		{
+			name:        "unknown status",
+			statusCode:  http.StatusTeapot,
+			body:        "",
+			wantKind:    ErrorKindUnknown,
+			wantMessage: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiErr := NewAPIError(http.MethodGet, "/foods", tt.statusCode, []byte(tt.body))
+
+			if apiErr.Kind != tt.wantKind {
+				t.Errorf("Kind = %v, want %v", apiErr.Kind, tt.wantKind)
+			}
+			if apiErr.Code != tt.wantCode {
+				t.Errorf("Code = %q, want %q", apiErr.Code, tt.wantCode)
+			}
+			if apiErr.Message != tt.wantMessage {
+				t.Errorf("Message = %q, want %q", apiErr.Message, tt.wantMessage)
+			}
+
+			// Classification must survive wrapping
+			wrapped := fmt.Errorf("context: %w", apiErr)
+			if tt.wantIs != nil && !errors.Is(wrapped, tt.wantIs) {
+				t.Errorf("errors.Is(%v) = false, want true", tt.wantIs)
+			}
+			if got, ok := AsAPIError(wrapped); !ok || got != apiErr {
+				t.Errorf("AsAPIError() = %v, %v, want original error", got, ok)
+			}
+		})
+	}
+}
+
+func TestAPIErrorIsOnlyMatchesOwnKind(t *testing.T) {
+	apiErr := NewAPIError(http.MethodPost, "/foods", http.StatusNotFound, nil)
+
+	if IsUnauthorized(apiErr) || IsConflict(apiErr) || IsValidation(apiErr) || IsRateLimited(apiErr) || IsServerError(apiErr) {
+		t.Errorf("not-found error matched another kind")
+	}
+	if !IsNotFound(apiErr) {
+		t.Errorf("IsNotFound() = false, want true")
+	}
+	if IsNotFound(errors.New("plain error")) {
+		t.Errorf("IsNotFound() matched a non-API error")
+	}
+}
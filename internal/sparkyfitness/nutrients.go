@@ -0,0 +1,45 @@
+package sparkyfitness
+
+// Scale returns the nutrients multiplied by factor, e.g. to convert a
+// per-serving value into the amount actually eaten
+func (n Nutrients) Scale(factor float64) Nutrients {
+	return n.Map(func(v float64) float64 { return v * factor })
+}
+
+// Map returns the nutrients with f applied to every field (e.g., rounding)
+func (n Nutrients) Map(f func(float64) float64) Nutrients {
+	return n.combine(Nutrients{}, func(a, _ float64) float64 { return f(a) })
+}
+
+// Add returns the field-wise sum of n and o
+func (n Nutrients) Add(o Nutrients) Nutrients {
+	return n.combine(o, func(a, b float64) float64 { return a + b })
+}
+
+// Sub returns the field-wise difference n - o
+func (n Nutrients) Sub(o Nutrients) Nutrients {
+	return n.combine(o, func(a, b float64) float64 { return a - b })
+}
+
+// combine applies f to every pair of corresponding fields of n and o
+func (n Nutrients) combine(o Nutrients, f func(a, b float64) float64) Nutrients {
+	return Nutrients{
+		Calories:           f(n.Calories, o.Calories),
+		Protein:            f(n.Protein, o.Protein),
+		Carbs:              f(n.Carbs, o.Carbs),
+		Fat:                f(n.Fat, o.Fat),
+		SaturatedFat:       f(n.SaturatedFat, o.SaturatedFat),
+		PolyunsaturatedFat: f(n.PolyunsaturatedFat, o.PolyunsaturatedFat),
+		MonounsaturatedFat: f(n.MonounsaturatedFat, o.MonounsaturatedFat),
+		TransFat:           f(n.TransFat, o.TransFat),
+		Cholesterol:        f(n.Cholesterol, o.Cholesterol),
+		Sodium:             f(n.Sodium, o.Sodium),
+		Potassium:          f(n.Potassium, o.Potassium),
+		DietaryFiber:       f(n.DietaryFiber, o.DietaryFiber),
+		Sugars:             f(n.Sugars, o.Sugars),
+		VitaminA:           f(n.VitaminA, o.VitaminA),
+		VitaminC:           f(n.VitaminC, o.VitaminC),
+		Calcium:            f(n.Calcium, o.Calcium),
+		Iron:               f(n.Iron, o.Iron),
+	}
+}
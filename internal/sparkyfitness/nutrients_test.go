@@ -0,0 +1,46 @@
+package sparkyfitness
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNutrientsArithmetic(t *testing.T) {
+	a := Nutrients{Calories: 100, Protein: 10, Sodium: 200, Iron: 1}
+	b := Nutrients{Calories: 50, Protein: 2.5, Fat: 4}
+
+	tests := []struct {
+		name string
+		got  Nutrients
+		want Nutrients
+	}{
+		{
+			name: "scale",
+			got:  a.Scale(1.5),
+			want: Nutrients{Calories: 150, Protein: 15, Sodium: 300, Iron: 1.5},
+		},
+		{
+			name: "map",
+			got:  Nutrients{Calories: 1.26, Protein: 2.74}.Map(math.Round),
+			want: Nutrients{Calories: 1, Protein: 3},
+		},
+		{
+			name: "add",
+			got:  a.Add(b),
+			want: Nutrients{Calories: 150, Protein: 12.5, Fat: 4, Sodium: 200, Iron: 1},
+		},
+		{
+			name: "sub",
+			got:  a.Sub(b),
+			want: Nutrients{Calories: 50, Protein: 7.5, Fat: -4, Sodium: 200, Iron: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %+v, want %+v", tt.got, tt.want)
+			}
+		})
+	}
+}
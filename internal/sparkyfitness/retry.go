@@ -0,0 +1,233 @@
+package sparkyfitness
+
+import (
+	"context"
+	cryptorand "crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// idempotencyKeyHeader lets the backend deduplicate retried POST requests.
+// The SparkyFitness backend must honour it for retryWrites to be safe.
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// retryPolicy controls timeouts and retries for backend requests
+type retryPolicy struct {
+	// timeout bounds each attempt, including reading the response body (0 = none)
+	timeout time.Duration
+	// maxRetries is the number of retries after the first attempt
+	maxRetries int
+	// baseDelay is the backoff delay before the first retry
+	baseDelay time.Duration
+	// maxDelay caps the backoff delay and the accepted Retry-After wait
+	maxDelay time.Duration
+	// retryWrites allows retrying POST requests, which then carry an Idempotency-Key
+	retryWrites bool
+}
+
+// retryTransport retries failed requests according to a retryPolicy
+type retryTransport struct {
+	policy retryPolicy
+	next   http.RoundTripper
+	// sleep waits for d or until ctx is done; replaceable in tests
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+func newRetryTransport(policy retryPolicy, next http.RoundTripper) *retryTransport {
+	return &retryTransport{
+		policy: policy,
+		next:   next,
+		sleep:  sleepContext,
+	}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	retryable := isIdempotentMethod(req.Method)
+	if req.Method == http.MethodPost && t.policy.retryWrites {
+		// Creates are only retried when the backend can deduplicate them.
+		// PUT and DELETE are never retried: the backend documents no
+		// idempotency support for them.
+		if req.Header.Get(idempotencyKeyHeader) == "" {
+			req = req.Clone(req.Context())
+			req.Header.Set(idempotencyKeyHeader, newIdempotencyKey())
+		}
+		retryable = true
+	}
+
+	// A body that cannot be replayed cannot be retried
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		retryable = false
+	}
+
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			attemptReq = req.Clone(req.Context())
+			attemptReq.Body = body
+		}
+
+		resp, err := t.roundTripOnce(attemptReq)
+
+		if !retryable || attempt >= t.policy.maxRetries || !shouldRetry(req.Context(), resp, err) {
+			return resp, err
+		}
+
+		delay := t.backoff(attempt)
+		if resp != nil {
+			if retryAfter, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+				if retryAfter > t.policy.maxDelay {
+					// The backend asked us to wait longer than we are willing to; give up
+					return resp, nil
+				}
+				delay = max(delay, retryAfter)
+			}
+			// Drain so the connection can be reused
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+			resp.Body.Close()
+		}
+
+		slog.Debug("Retrying SparkyFitness request",
+			"method", req.Method,
+			"path", req.URL.Path,
+			"attempt", attempt+1,
+			"delay", delay,
+			"error", err,
+		)
+
+		if err := t.sleep(req.Context(), delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// roundTripOnce performs a single attempt bounded by the per-attempt timeout.
+// The timeout stays armed until the response body is closed.
+func (t *retryTransport) roundTripOnce(req *http.Request) (*http.Response, error) {
+	if t.policy.timeout <= 0 {
+		return t.next.RoundTrip(req)
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), t.policy.timeout)
+	resp, err := t.next.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	resp.Body = &cancelOnCloseBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// backoff returns the exponential backoff delay with jitter for a retry
+func (t *retryTransport) backoff(attempt int) time.Duration {
+	delay := t.policy.baseDelay << attempt
+	if delay <= 0 || delay > t.policy.maxDelay {
+		delay = t.policy.maxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+
+	// Jitter in [delay/2, delay) spreads out concurrent retries
+	half := delay / 2
+	return half + rand.N(delay-half)
+}
+
+// shouldRetry reports whether a failed attempt is worth retrying
+func shouldRetry(ctx context.Context, resp *http.Response, err error) bool {
+	if ctx.Err() != nil {
+		// The caller gave up; retrying cannot succeed
+		return false
+	}
+	if err != nil {
+		// Network errors and per-attempt timeouts are transient
+		return !errors.Is(err, context.Canceled)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// isIdempotentMethod reports whether a request can be safely repeated
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseRetryAfter parses a Retry-After header in seconds or HTTP-date form
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+
+	return 0, false
+}
+
+// newIdempotencyKey returns a random key identifying one logical write request
+func newIdempotencyKey() string {
+	b := make([]byte, 16)
+	// crypto/rand.Read never returns an error
+	_, _ = cryptorand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// cancelOnCloseBody releases a per-attempt context once the body is consumed
+type cancelOnCloseBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (b *cancelOnCloseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
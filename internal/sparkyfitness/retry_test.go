@@ -0,0 +1,210 @@
+package sparkyfitness
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryTransport(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		policy       retryPolicy
+		statuses     []int
+		retryAfter   string
+		wantStatus   int
+		wantAttempts int32
+	}{
+		{
+			name:         "GET retries transient 503 until success",
+			method:       http.MethodGet,
+			policy:       retryPolicy{maxRetries: 3, baseDelay: time.Millisecond, maxDelay: time.Second},
+			statuses:     []int{503, 502, 200},
+			wantStatus:   200,
+			wantAttempts: 3,
+		},
+		{
+			name:         "GET gives up after max retries",
+			method:       http.MethodGet,
+			policy:       retryPolicy{maxRetries: 2, baseDelay: time.Millisecond, maxDelay: time.Second},
+			statuses:     []int{503, 503, 503, 503},
+			wantStatus:   503,
+			wantAttempts: 3,
+		},
+		{
+			name:         "GET does not retry client errors",
+			method:       http.MethodGet,
+			policy:       retryPolicy{maxRetries: 3, baseDelay: time.Millisecond, maxDelay: time.Second},
+			statuses:     []int{404},
+			wantStatus:   404,
+			wantAttempts: 1,
+		},
+		{
+			name:         "POST is not retried by default",
+			method:       http.MethodPost,
+			policy:       retryPolicy{maxRetries: 3, baseDelay: time.Millisecond, maxDelay: time.Second},
+			statuses:     []int{503, 201},
+			wantStatus:   503,
+			wantAttempts: 1,
+		},
+		{
+			name:         "POST is retried when writes are enabled",
+			method:       http.MethodPost,
+			policy:       retryPolicy{maxRetries: 3, baseDelay: time.Millisecond, maxDelay: time.Second, retryWrites: true},
+			statuses:     []int{503, 201},
+			wantStatus:   201,
+			wantAttempts: 2,
+		},
+		{
+			name:         "PUT is not retried even when writes are enabled",
+			method:       http.MethodPut,
+			policy:       retryPolicy{maxRetries: 3, baseDelay: time.Millisecond, maxDelay: time.Second, retryWrites: true},
+			statuses:     []int{503, 200},
+			wantStatus:   503,
+			wantAttempts: 1,
+		},
+		{
+			name:         "DELETE is not retried even when writes are enabled",
+			method:       http.MethodDelete,
+			policy:       retryPolicy{maxRetries: 3, baseDelay: time.Millisecond, maxDelay: time.Second, retryWrites: true},
+			statuses:     []int{503, 200},
+			wantStatus:   503,
+			wantAttempts: 1,
+		},
+		{
+			name:         "Retry-After beyond max delay stops retrying",
+			method:       http.MethodGet,
+			policy:       retryPolicy{maxRetries: 3, baseDelay: time.Millisecond, maxDelay: time.Second},
+			statuses:     []int{429, 200},
+			retryAfter:   "120",
+			wantStatus:   429,
+			wantAttempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			var keys []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				n := attempts.Add(1)
+				keys = append(keys, r.Header.Get(idempotencyKeyHeader))
+
+				status := tt.statuses[min(int(n), len(tt.statuses))-1]
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(status)
+			}))
+			defer srv.Close()
+
+			var slept []time.Duration
+			transport := newRetryTransport(tt.policy, http.DefaultTransport)
+			transport.sleep = func(ctx context.Context, d time.Duration) error {
+				slept = append(slept, d)
+				return nil
+			}
+			httpClient := &http.Client{Transport: transport}
+
+			req, err := http.NewRequest(tt.method, srv.URL, strings.NewReader(`{"name":"rice"}`))
+			if err != nil {
+				t.Fatalf("NewRequest() error: %v", err)
+			}
+
+			resp, err := httpClient.Do(req)
+			if err != nil {
+				t.Fatalf("Do() error: %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if got := attempts.Load(); got != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", got, tt.wantAttempts)
+			}
+			if len(slept) != int(tt.wantAttempts)-1 {
+				t.Errorf("sleeps = %d, want %d", len(slept), tt.wantAttempts-1)
+			}
+			for _, d := range slept {
+				if d > tt.policy.maxDelay {
+					t.Errorf("backoff delay %v exceeds max delay %v", d, tt.policy.maxDelay)
+				}
+			}
+
+			// Retried writes must reuse one idempotency key
+			if tt.policy.retryWrites && tt.method == http.MethodPost {
+				if keys[0] == "" {
+					t.Errorf("POST sent without %s header", idempotencyKeyHeader)
+				}
+				for _, k := range keys[1:] {
+					if k != keys[0] {
+						t.Errorf("idempotency key changed between attempts: %q != %q", k, keys[0])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRetryTransportPerAttemptTimeout(t *testing.T) {
+	var attempts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			// Hang longer than the per-attempt timeout
+			select {
+			case <-r.Context().Done():
+			case <-time.After(200 * time.Millisecond):
+			}
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"searchResults":[]}`))
+	}))
+	defer srv.Close()
+
+	policy := retryPolicy{timeout: 50 * time.Millisecond, maxRetries: 1, baseDelay: time.Millisecond, maxDelay: time.Millisecond}
+	httpClient := &http.Client{Transport: newRetryTransport(policy, http.DefaultTransport)}
+
+	resp, err := httpClient.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("Get() error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusOK)
+	}
+	if got := attempts.Load(); got != 2 {
+		t.Errorf("attempts = %d, want 2", got)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{value: "", wantOK: false},
+		{value: "3", want: 3 * time.Second, wantOK: true},
+		{value: "-1", wantOK: false},
+		{value: "Wed, 01 Jan 2025 12:00:10 GMT", want: 10 * time.Second, wantOK: true},
+		{value: "Wed, 01 Jan 2025 11:00:00 GMT", want: 0, wantOK: true},
+		{value: "later", wantOK: false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if ok != tt.wantOK || got != tt.want {
+			t.Errorf("parseRetryAfter(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
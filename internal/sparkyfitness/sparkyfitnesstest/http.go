@@ -0,0 +1,49 @@
+package sparkyfitnesstest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// readBody reads the request body and restores it so it can be read again
+func readBody(r *http.Request) []byte {
+	if r.Body == nil {
+		return nil
+	}
+
+	body, _ := io.ReadAll(r.Body)
+	r.Body.Close()
+	r.Body = io.NopCloser(bytes.NewReader(body))
+
+	return body
+}
+
+// writeJSON writes v as a JSON response with the given status
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes the backend's JSON error envelope
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{"error": message})
+}
+
+// parseLimit parses the required positive limit query parameter
+func parseLimit(value string) (int, error) {
+	if value == "" {
+		return 0, fmt.Errorf("limit is required")
+	}
+
+	limit, err := strconv.Atoi(value)
+	if err != nil || limit <= 0 {
+		return 0, fmt.Errorf("limit must be a positive integer")
+	}
+
+	return limit, nil
+}
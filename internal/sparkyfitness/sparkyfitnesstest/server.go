@@ -0,0 +1,962 @@
+// Package sparkyfitnesstest provides an in-process fake SparkyFitness backend
+// for tests, in the spirit of net/http/httptest.
+//
+// The fake keeps foods and variants in memory, enforces Bearer token
+// authentication, mimics the backend's broad/exact match search semantics and
+// supports fault injection (latency, error statuses, malformed bodies) so
+// client and MCP tool behavior can be tested end to end.
+package sparkyfitnesstest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/config"
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+)
+
+// DefaultAPIKey is the API key accepted by a Server unless changed
+const DefaultAPIKey = "test-api-key"
+
+// Fault describes an injected failure applied to matching requests
+type Fault struct {
+	// Method restricts the fault to one HTTP method ("" matches any)
+	Method string
+	// Path restricts the fault to one request path ("" matches any)
+	Path string
+	// Latency delays the response (applied before Status/Body)
+	Latency time.Duration
+	// Status, when non-zero, replaces the normal response with this status code
+	Status int
+	// Body is written with Status; use invalid JSON to simulate malformed responses
+	Body string
+	// Header is added to the faulted response (e.g., Retry-After)
+	Header http.Header
+	// Times limits how many requests the fault affects (0 = every request)
+	Times int
+}
+
+// Request records a request received by the fake backend
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+}
+
+// Server is an in-process fake SparkyFitness backend
+type Server struct {
+	// URL is the base URL of the fake backend, suitable for SparkyFitnessAPIURL
+	URL string
+	// APIKey is the Bearer token the fake backend accepts
+	APIKey string
+
+	server *httptest.Server
+
+	mu       sync.Mutex
+	foods    []*storedFood
+	entries  []*sparkyfitness.FoodEntry
+	meals    []*sparkyfitness.Meal
+	custom   []sparkyfitness.CustomNutrient
+	faults   []*Fault
+	requests []Request
+	nextID   int
+}
+
+// storedFood is a food together with all of its variants
+type storedFood struct {
+	food     sparkyfitness.Food
+	variants []*sparkyfitness.FoodVariant
+}
+
+// NewServer starts a fake backend that is closed when the test finishes
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+
+	s := &Server{APIKey: DefaultAPIKey}
+
+	s.server = httptest.NewServer(s.handler())
+	s.URL = s.server.URL
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+// Close shuts down the fake backend
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Config returns a configuration pointing at the fake backend.
+// Retries are disabled so faults surface directly; tests exercising the
+// retry policy can adjust the returned config.
+func (s *Server) Config() *config.Config {
+	return &config.Config{
+		SparkyFitnessAPIURL: s.URL,
+		SparkyFitnessAPIKey: s.APIKey,
+		RequestTimeout:      5 * time.Second,
+		MaxRetries:          0,
+		RetryBaseDelay:      time.Millisecond,
+		RetryMaxDelay:       10 * time.Millisecond,
+		Transport:           config.TransportStdio,
+		LogLevel:            config.LogLevelInfo,
+		LogFormat:           config.LogFormatText,
+	}
+}
+
+// NewClient returns a SparkyFitness client connected to the fake backend
+func (s *Server) NewClient(t testing.TB) *sparkyfitness.Client {
+	t.Helper()
+
+	client, err := sparkyfitness.NewClient(s.Config())
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+
+	return client
+}
+
+// AddFood seeds a food with its variants and returns the stored food.
+// Empty IDs are generated; when no variant is marked default, the first one is.
+func (s *Server) AddFood(food sparkyfitness.Food, variants ...sparkyfitness.FoodVariant) sparkyfitness.Food {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if food.ID == "" {
+		food.ID = s.newID()
+	}
+	food.DefaultVariant = nil
+
+	stored := &storedFood{food: food}
+	hasDefault := false
+	for i := range variants {
+		v := variants[i]
+		if v.ID == "" {
+			v.ID = s.newID()
+		}
+		v.FoodID = food.ID
+		if v.IsDefault {
+			if hasDefault {
+				v.IsDefault = false
+			}
+			hasDefault = true
+		}
+		stored.variants = append(stored.variants, &v)
+	}
+	if !hasDefault && len(stored.variants) > 0 {
+		stored.variants[0].IsDefault = true
+	}
+
+	s.foods = append(s.foods, stored)
+	return stored.view()
+}
+
+// Food returns a stored food and all of its variants
+func (s *Server) Food(id string) (sparkyfitness.Food, []sparkyfitness.FoodVariant, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := s.findFood(id)
+	if stored == nil {
+		return sparkyfitness.Food{}, nil, false
+	}
+
+	variants := make([]sparkyfitness.FoodVariant, 0, len(stored.variants))
+	for _, v := range stored.variants {
+		variants = append(variants, *v)
+	}
+
+	return stored.view(), variants, true
+}
+
+// Foods returns every stored food with its default variant
+func (s *Server) Foods() []sparkyfitness.Food {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	foods := make([]sparkyfitness.Food, 0, len(s.foods))
+	for _, stored := range s.foods {
+		foods = append(foods, stored.view())
+	}
+
+	return foods
+}
+
+// AddFoodEntry seeds a food diary entry and returns the stored entry.
+// When VariantID refers to a stored variant, the food name, serving and
+// nutrients are snapshotted from it as the backend does.
+func (s *Server) AddFoodEntry(entry sparkyfitness.FoodEntry) sparkyfitness.FoodEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if entry.ID == "" {
+		entry.ID = s.newID()
+	}
+	if stored, variant := s.findVariant(entry.VariantID); variant != nil {
+		snapshot(&entry, stored, variant)
+	}
+
+	s.entries = append(s.entries, &entry)
+	return entry
+}
+
+// Entries returns every food diary entry, in the order they were logged
+func (s *Server) Entries() []sparkyfitness.FoodEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := make([]sparkyfitness.FoodEntry, 0, len(s.entries))
+	for _, e := range s.entries {
+		entries = append(entries, *e)
+	}
+
+	return entries
+}
+
+// Meals returns every saved meal, in creation order
+func (s *Server) Meals() []sparkyfitness.Meal {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	meals := make([]sparkyfitness.Meal, 0, len(s.meals))
+	for _, m := range s.meals {
+		meals = append(meals, *m)
+	}
+
+	return meals
+}
+
+// AddCustomNutrient defines a custom nutrient, assigning its ID, and returns it
+func (s *Server) AddCustomNutrient(name, unit string) sparkyfitness.CustomNutrient {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	nutrient := sparkyfitness.CustomNutrient{ID: s.newID(), Name: name, Unit: unit}
+	s.custom = append(s.custom, nutrient)
+
+	return nutrient
+}
+
+// InjectFault registers a fault for subsequent matching requests
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes all injected faults
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.faults = nil
+}
+
+// Requests returns every request received so far, in order
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Request(nil), s.requests...)
+}
+
+// handler builds the routing table wrapped with recording, faults and auth
+func (s *Server) handler() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /foods", s.handleSearchFoods)
+	mux.HandleFunc("POST /foods", s.handleCreateFood)
+	mux.HandleFunc("GET /foods/{id}", s.handleGetFood)
+	mux.HandleFunc("PUT /foods/{id}", s.handleUpdateFood)
+	mux.HandleFunc("DELETE /foods/{id}", s.handleDeleteFood)
+	mux.HandleFunc("GET /foods/food-variants", s.handleListFoodVariants)
+	mux.HandleFunc("POST /foods/food-variants", s.handleCreateFoodVariant)
+	mux.HandleFunc("GET /foods/food-variants/{id}", s.handleGetFoodVariant)
+	mux.HandleFunc("PUT /foods/food-variants/{id}", s.handleUpdateFoodVariant)
+	mux.HandleFunc("DELETE /foods/food-variants/{id}", s.handleDeleteFoodVariant)
+	mux.HandleFunc("POST /food-entries", s.handleCreateFoodEntry)
+	mux.HandleFunc("GET /food-entries/by-date/{date}", s.handleListFoodEntries)
+	mux.HandleFunc("GET /food-entries/range/{start}/{end}", s.handleListFoodEntries)
+	mux.HandleFunc("GET /food-entries/{id}", s.handleGetFoodEntry)
+	mux.HandleFunc("PUT /food-entries/{id}", s.handleUpdateFoodEntry)
+	mux.HandleFunc("DELETE /food-entries/{id}", s.handleDeleteFoodEntry)
+	mux.HandleFunc("GET /meals", s.handleSearchMeals)
+	mux.HandleFunc("POST /meals", s.handleCreateMeal)
+	mux.HandleFunc("GET /meals/{id}", s.handleGetMeal)
+	mux.HandleFunc("GET /custom-nutrients", s.handleListCustomNutrients)
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body := readBody(r)
+		s.record(r, body)
+
+		if fault := s.matchFault(r); fault != nil {
+			if fault.Latency > 0 {
+				select {
+				case <-time.After(fault.Latency):
+				case <-r.Context().Done():
+					return
+				}
+			}
+			if fault.Status != 0 {
+				for k, values := range fault.Header {
+					for _, v := range values {
+						w.Header().Add(k, v)
+					}
+				}
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(fault.Status)
+				w.Write([]byte(fault.Body))
+				return
+			}
+		}
+
+		if r.Header.Get("Authorization") != "Bearer "+s.APIKey {
+			writeError(w, http.StatusUnauthorized, "Authentication required")
+			return
+		}
+
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// record stores a request for later inspection
+func (s *Server) record(r *http.Request, body []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+	})
+}
+
+// matchFault returns the first active fault matching the request, consuming one use
+func (s *Server) matchFault(r *http.Request) *Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, f := range s.faults {
+		if f.Method != "" && f.Method != r.Method {
+			continue
+		}
+		if f.Path != "" && f.Path != r.URL.Path {
+			continue
+		}
+		if f.Times > 0 {
+			f.Times--
+			if f.Times == 0 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+		}
+		return f
+	}
+
+	return nil
+}
+
+// handleSearchFoods implements GET /foods
+func (s *Server) handleSearchFoods(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	name := q.Get("name")
+	broadMatch := q.Get("broadMatch") == "true"
+	exactMatch := q.Get("exactMatch") == "true"
+
+	if broadMatch == exactMatch {
+		writeError(w, http.StatusBadRequest, "Exactly one of broadMatch or exactMatch must be true")
+		return
+	}
+
+	limit, err := parseLimit(q.Get("limit"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	results := []sparkyfitness.Food{}
+	for _, stored := range s.foods {
+		if !matchName(stored.food.Name, name, broadMatch) {
+			continue
+		}
+		results = append(results, stored.view())
+		if len(results) == limit {
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, sparkyfitness.SearchFoodsResponse{SearchResults: results})
+}
+
+// handleGetFood implements GET /foods/{id}
+func (s *Server) handleGetFood(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := s.findFood(r.PathValue("id"))
+	if stored == nil {
+		writeError(w, http.StatusNotFound, "Food not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, stored.view())
+}
+
+// handleListFoodVariants implements GET /foods/food-variants?food_id={id}
+func (s *Server) handleListFoodVariants(w http.ResponseWriter, r *http.Request) {
+	foodID := r.URL.Query().Get("food_id")
+	if foodID == "" {
+		writeError(w, http.StatusBadRequest, "food_id is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := s.findFood(foodID)
+	if stored == nil {
+		writeError(w, http.StatusNotFound, "Food not found")
+		return
+	}
+
+	variants := make([]sparkyfitness.FoodVariant, 0, len(stored.variants))
+	for _, v := range stored.variants {
+		variants = append(variants, *v)
+	}
+
+	writeJSON(w, http.StatusOK, variants)
+}
+
+// handleCreateFood implements POST /foods
+func (s *Server) handleCreateFood(w http.ResponseWriter, r *http.Request) {
+	var req sparkyfitness.CreateFoodRequest
+	if err := json.Unmarshal(readBody(r), &req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON body")
+		return
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		writeError(w, http.StatusBadRequest, "Food name is required")
+		return
+	}
+	if req.ServingSize <= 0 || req.ServingUnit == "" {
+		writeError(w, http.StatusBadRequest, "serving_size and serving_unit are required")
+		return
+	}
+
+	s.mu.Lock()
+	food := sparkyfitness.Food{
+		ID:       s.newID(),
+		Name:     req.Name,
+		IsCustom: req.IsCustom,
+		UserID:   "00000000-0000-4000-8000-000000000001",
+	}
+	if req.Brand != "" {
+		brand := req.Brand
+		food.Brand = &brand
+	}
+	variant := variantFromCreate(&req)
+	variant.ID = s.newID()
+	variant.FoodID = food.ID
+	variant.IsDefault = true
+
+	stored := &storedFood{food: food, variants: []*sparkyfitness.FoodVariant{&variant}}
+	s.foods = append(s.foods, stored)
+	view := stored.view()
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusCreated, view)
+}
+
+// handleCreateFoodVariant implements POST /foods/food-variants
+func (s *Server) handleCreateFoodVariant(w http.ResponseWriter, r *http.Request) {
+	var req sparkyfitness.AddFoodVariantRequest
+	if err := json.Unmarshal(readBody(r), &req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON body")
+		return
+	}
+	if req.ServingSize <= 0 || req.ServingUnit == "" {
+		writeError(w, http.StatusBadRequest, "serving_size and serving_unit are required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := s.findFood(req.FoodID)
+	if stored == nil {
+		writeError(w, http.StatusNotFound, "Food not found")
+		return
+	}
+
+	variant := variantFromAdd(&req)
+	variant.ID = s.newID()
+	if variant.IsDefault {
+		for _, v := range stored.variants {
+			v.IsDefault = false
+		}
+	}
+	stored.variants = append(stored.variants, &variant)
+
+	writeJSON(w, http.StatusCreated, sparkyfitness.AddFoodVariantResponse{ID: variant.ID})
+}
+
+// handleUpdateFood implements PUT /foods/{id}
+func (s *Server) handleUpdateFood(w http.ResponseWriter, r *http.Request) {
+	var req sparkyfitness.UpdateFoodRequest
+	if err := json.Unmarshal(readBody(r), &req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON body")
+		return
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		writeError(w, http.StatusBadRequest, "Food name is required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored := s.findFood(r.PathValue("id"))
+	if stored == nil {
+		writeError(w, http.StatusNotFound, "Food not found")
+		return
+	}
+
+	stored.food.Name = req.Name
+	stored.food.Brand = req.Brand
+	stored.food.SharedWithPublic = req.SharedWithPublic
+
+	writeJSON(w, http.StatusOK, stored.view())
+}
+
+// handleGetFoodVariant implements GET /foods/food-variants/{id}
+func (s *Server) handleGetFoodVariant(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, variant := s.findVariant(r.PathValue("id"))
+	if variant == nil {
+		writeError(w, http.StatusNotFound, "Food variant not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, variant)
+}
+
+// handleUpdateFoodVariant implements PUT /foods/food-variants/{id}
+func (s *Server) handleUpdateFoodVariant(w http.ResponseWriter, r *http.Request) {
+	var req sparkyfitness.UpdateFoodVariantRequest
+	if err := json.Unmarshal(readBody(r), &req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON body")
+		return
+	}
+	if req.ServingSize <= 0 || req.ServingUnit == "" {
+		writeError(w, http.StatusBadRequest, "serving_size and serving_unit are required")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, variant := s.findVariant(r.PathValue("id"))
+	if variant == nil {
+		writeError(w, http.StatusNotFound, "Food variant not found")
+		return
+	}
+
+	variant.ServingSize = req.ServingSize
+	variant.ServingUnit = req.ServingUnit
+	variant.Nutrients = req.Nutrients
+	variant.GlycemicIndex = req.GlycemicIndex
+	if req.CustomNutrients != nil {
+		variant.CustomNutrients = req.CustomNutrients
+	}
+	if req.IsDefault {
+		for _, v := range stored.variants {
+			v.IsDefault = v == variant
+		}
+	}
+
+	writeJSON(w, http.StatusOK, variant)
+}
+
+// handleDeleteFood implements DELETE /foods/{id}
+func (s *Server) handleDeleteFood(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := r.PathValue("id")
+	for i, stored := range s.foods {
+		if stored.food.ID == id {
+			s.foods = append(s.foods[:i:i], s.foods[i+1:]...)
+			writeJSON(w, http.StatusOK, map[string]string{"message": "Food deleted successfully"})
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, "Food not found")
+}
+
+// handleDeleteFoodVariant implements DELETE /foods/food-variants/{id}
+func (s *Server) handleDeleteFoodVariant(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, variant := s.findVariant(r.PathValue("id"))
+	if variant == nil {
+		writeError(w, http.StatusNotFound, "Food variant not found")
+		return
+	}
+
+	for i, v := range stored.variants {
+		if v == variant {
+			stored.variants = append(stored.variants[:i:i], stored.variants[i+1:]...)
+			break
+		}
+	}
+
+	writeJSON(w, http.StatusOK, map[string]string{"message": "Food variant deleted successfully"})
+}
+
+// handleCreateFoodEntry implements POST /food-entries
+func (s *Server) handleCreateFoodEntry(w http.ResponseWriter, r *http.Request) {
+	var req sparkyfitness.CreateFoodEntryRequest
+	if err := json.Unmarshal(readBody(r), &req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON body")
+		return
+	}
+	if msg := validateEntry(req.MealType, req.Quantity, req.EntryDate); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, variant := s.findVariant(req.VariantID)
+	if variant == nil || (req.FoodID != "" && stored.food.ID != req.FoodID) {
+		writeError(w, http.StatusNotFound, "Food variant not found")
+		return
+	}
+
+	entry := &sparkyfitness.FoodEntry{
+		ID:        s.newID(),
+		MealType:  req.MealType,
+		Quantity:  req.Quantity,
+		EntryDate: req.EntryDate,
+	}
+	snapshot(entry, stored, variant)
+	s.entries = append(s.entries, entry)
+
+	writeJSON(w, http.StatusCreated, entry)
+}
+
+// handleListFoodEntries implements GET /food-entries/by-date/{date} and
+// GET /food-entries/range/{start}/{end}
+func (s *Server) handleListFoodEntries(w http.ResponseWriter, r *http.Request) {
+	start, end := r.PathValue("start"), r.PathValue("end")
+	if date := r.PathValue("date"); date != "" {
+		start, end = date, date
+	}
+	for _, d := range []string{start, end} {
+		if _, err := time.Parse(time.DateOnly, d); err != nil {
+			writeError(w, http.StatusBadRequest, "Dates must be in YYYY-MM-DD format")
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries := []sparkyfitness.FoodEntry{}
+	for _, e := range s.entries {
+		if e.EntryDate >= start && e.EntryDate <= end {
+			entries = append(entries, *e)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, entries)
+}
+
+// handleGetFoodEntry implements GET /food-entries/{id}
+func (s *Server) handleGetFoodEntry(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, entry := s.findEntry(r.PathValue("id"))
+	if entry == nil {
+		writeError(w, http.StatusNotFound, "Food entry not found")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, entry)
+}
+
+// handleUpdateFoodEntry implements PUT /food-entries/{id}
+func (s *Server) handleUpdateFoodEntry(w http.ResponseWriter, r *http.Request) {
+	var req sparkyfitness.UpdateFoodEntryRequest
+	if err := json.Unmarshal(readBody(r), &req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON body")
+		return
+	}
+	if msg := validateEntry(req.MealType, req.Quantity, req.EntryDate); msg != "" {
+		writeError(w, http.StatusBadRequest, msg)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, entry := s.findEntry(r.PathValue("id"))
+	if entry == nil {
+		writeError(w, http.StatusNotFound, "Food entry not found")
+		return
+	}
+
+	// Re-snapshot only when the variant changes, as the backend does
+	if req.VariantID != entry.VariantID {
+		stored, variant := s.findVariant(req.VariantID)
+		if variant == nil {
+			writeError(w, http.StatusNotFound, "Food variant not found")
+			return
+		}
+		snapshot(entry, stored, variant)
+	}
+	entry.MealType = req.MealType
+	entry.Quantity = req.Quantity
+	entry.EntryDate = req.EntryDate
+
+	writeJSON(w, http.StatusOK, entry)
+}
+
+// handleDeleteFoodEntry implements DELETE /food-entries/{id}
+func (s *Server) handleDeleteFoodEntry(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	i, entry := s.findEntry(r.PathValue("id"))
+	if entry == nil {
+		writeError(w, http.StatusNotFound, "Food entry not found")
+		return
+	}
+	s.entries = append(s.entries[:i:i], s.entries[i+1:]...)
+
+	writeJSON(w, http.StatusOK, map[string]string{"message": "Food entry deleted successfully"})
+}
+
+// handleSearchMeals implements GET /meals?search={search}
+func (s *Server) handleSearchMeals(w http.ResponseWriter, r *http.Request) {
+	search := r.URL.Query().Get("search")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	meals := []sparkyfitness.Meal{}
+	for _, m := range s.meals {
+		if search == "" || matchName(m.Name, search, true) {
+			meals = append(meals, *m)
+		}
+	}
+
+	writeJSON(w, http.StatusOK, meals)
+}
+
+// handleCreateMeal implements POST /meals
+func (s *Server) handleCreateMeal(w http.ResponseWriter, r *http.Request) {
+	var req sparkyfitness.CreateMealRequest
+	if err := json.Unmarshal(readBody(r), &req); err != nil {
+		writeError(w, http.StatusBadRequest, "Invalid JSON body")
+		return
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		writeError(w, http.StatusBadRequest, "Meal name is required")
+		return
+	}
+	if len(req.Foods) == 0 {
+		writeError(w, http.StatusBadRequest, "A meal needs at least one food")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	meal := &sparkyfitness.Meal{
+		ID:          s.newID(),
+		Name:        req.Name,
+		Description: req.Description,
+		IsPublic:    req.IsPublic,
+	}
+	for _, f := range req.Foods {
+		stored, variant := s.findVariant(f.VariantID)
+		if variant == nil {
+			writeError(w, http.StatusNotFound, "Food variant not found")
+			return
+		}
+		if f.Quantity <= 0 {
+			writeError(w, http.StatusBadRequest, "quantity must be greater than 0")
+			return
+		}
+		meal.Foods = append(meal.Foods, sparkyfitness.MealFood{
+			FoodID:    stored.food.ID,
+			VariantID: variant.ID,
+			Quantity:  f.Quantity,
+			Unit:      variant.ServingUnit,
+			FoodName:  stored.food.Name,
+		})
+	}
+	s.meals = append(s.meals, meal)
+
+	writeJSON(w, http.StatusCreated, meal)
+}
+
+// handleGetMeal implements GET /meals/{id}
+func (s *Server) handleGetMeal(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, m := range s.meals {
+		if m.ID == r.PathValue("id") {
+			writeJSON(w, http.StatusOK, m)
+			return
+		}
+	}
+
+	writeError(w, http.StatusNotFound, "Meal not found")
+}
+
+// handleListCustomNutrients implements GET /custom-nutrients
+func (s *Server) handleListCustomNutrients(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, append([]sparkyfitness.CustomNutrient{}, s.custom...))
+}
+
+// findEntry looks up a diary entry and its index by ID; callers must hold s.mu
+func (s *Server) findEntry(id string) (int, *sparkyfitness.FoodEntry) {
+	for i, e := range s.entries {
+		if e.ID == id {
+			return i, e
+		}
+	}
+	return -1, nil
+}
+
+// findVariant looks up a stored variant and its food by variant ID; callers must hold s.mu
+func (s *Server) findVariant(id string) (*storedFood, *sparkyfitness.FoodVariant) {
+	for _, stored := range s.foods {
+		for _, v := range stored.variants {
+			if v.ID == id {
+				return stored, v
+			}
+		}
+	}
+	return nil, nil
+}
+
+// findFood looks up a stored food by ID; callers must hold s.mu
+func (s *Server) findFood(id string) *storedFood {
+	for _, stored := range s.foods {
+		if stored.food.ID == id {
+			return stored
+		}
+	}
+	return nil
+}
+
+// newID returns a deterministic UUID-shaped identifier; callers must hold s.mu
+func (s *Server) newID() string {
+	s.nextID++
+	return fmt.Sprintf("00000000-0000-4000-8000-%012d", s.nextID)
+}
+
+// view returns the food as the backend serializes it, with its default variant
+func (f *storedFood) view() sparkyfitness.Food {
+	food := f.food
+	food.DefaultVariant = nil
+	for _, v := range f.variants {
+		if v.IsDefault {
+			variant := *v
+			food.DefaultVariant = &variant
+			break
+		}
+	}
+	return food
+}
+
+// matchName mimics the backend: broad match is a case-insensitive substring
+// match, exact match is a case-insensitive equality check
+func matchName(foodName, query string, broadMatch bool) bool {
+	if broadMatch {
+		return strings.Contains(strings.ToLower(foodName), strings.ToLower(query))
+	}
+	return strings.EqualFold(foodName, query)
+}
+
+// snapshot copies the food and variant details into an entry, as the backend
+// does when an entry is logged
+func snapshot(entry *sparkyfitness.FoodEntry, stored *storedFood, variant *sparkyfitness.FoodVariant) {
+	entry.FoodID = stored.food.ID
+	entry.VariantID = variant.ID
+	entry.Unit = variant.ServingUnit
+	entry.FoodName = stored.food.Name
+	entry.BrandName = stored.food.Brand
+	entry.ServingSize = variant.ServingSize
+	entry.ServingUnit = variant.ServingUnit
+	entry.Nutrients = variant.Nutrients
+}
+
+// validateEntry checks the diary fields shared by create and update requests
+// and returns an error message, or "" if they are valid
+func validateEntry(mealType string, quantity float64, date string) string {
+	if !slices.Contains(sparkyfitness.MealTypes, mealType) {
+		return "meal_type must be one of breakfast, lunch, dinner, snacks"
+	}
+	if quantity <= 0 {
+		return "quantity must be greater than 0"
+	}
+	if _, err := time.Parse(time.DateOnly, date); err != nil {
+		return "entry_date must be a date in YYYY-MM-DD format"
+	}
+	return ""
+}
+
+// variantFromCreate extracts the variant fields of a create food request
+func variantFromCreate(req *sparkyfitness.CreateFoodRequest) sparkyfitness.FoodVariant {
+	v := sparkyfitness.FoodVariant{
+		ServingSize:     req.ServingSize,
+		ServingUnit:     req.ServingUnit,
+		Nutrients:       req.Nutrients,
+		CustomNutrients: req.CustomNutrients,
+	}
+	if req.GlycemicIndex != "" {
+		gi := req.GlycemicIndex
+		v.GlycemicIndex = &gi
+	}
+	return v
+}
+
+// variantFromAdd extracts the variant fields of an add variant request
+func variantFromAdd(req *sparkyfitness.AddFoodVariantRequest) sparkyfitness.FoodVariant {
+	return sparkyfitness.FoodVariant{
+		FoodID:          req.FoodID,
+		ServingSize:     req.ServingSize,
+		ServingUnit:     req.ServingUnit,
+		Nutrients:       req.Nutrients,
+		IsDefault:       req.IsDefault,
+		GlycemicIndex:   req.GlycemicIndex,
+		CustomNutrients: req.CustomNutrients,
+	}
+}
@@ -4,42 +4,49 @@ package sparkyfitness
 
 // Food represents a food item from the backend API
 type Food struct {
-	ID                 string        `json:"id"`
-	Name               string        `json:"name"`
-	Brand              *string       `json:"brand"`
-	IsCustom           bool          `json:"is_custom"`
-	UserID             string        `json:"user_id"`
-	SharedWithPublic   bool          `json:"shared_with_public"`
-	ProviderExternalID *string       `json:"provider_external_id"`
-	ProviderType       *string       `json:"provider_type"`
-	DefaultVariant     *FoodVariant  `json:"default_variant"`
+	ID                 string       `json:"id"`
+	Name               string       `json:"name"`
+	Brand              *string      `json:"brand"`
+	IsCustom           bool         `json:"is_custom"`
+	UserID             string       `json:"user_id"`
+	SharedWithPublic   bool         `json:"shared_with_public"`
+	ProviderExternalID *string      `json:"provider_external_id"`
+	ProviderType       *string      `json:"provider_type"`
+	DefaultVariant     *FoodVariant `json:"default_variant"`
+}
+
+// Nutrients is the nutrient field set shared by variants and variant requests.
+// It is embedded so the fields serialize flat, as the backend expects.
+type Nutrients struct {
+	Calories           float64 `json:"calories"`
+	Protein            float64 `json:"protein"`
+	Carbs              float64 `json:"carbs"`
+	Fat                float64 `json:"fat"`
+	SaturatedFat       float64 `json:"saturated_fat"`
+	PolyunsaturatedFat float64 `json:"polyunsaturated_fat"`
+	MonounsaturatedFat float64 `json:"monounsaturated_fat"`
+	TransFat           float64 `json:"trans_fat"`
+	Cholesterol        float64 `json:"cholesterol"`
+	Sodium             float64 `json:"sodium"`
+	Potassium          float64 `json:"potassium"`
+	DietaryFiber       float64 `json:"dietary_fiber"`
+	Sugars             float64 `json:"sugars"`
+	VitaminA           float64 `json:"vitamin_a"`
+	VitaminC           float64 `json:"vitamin_c"`
+	Calcium            float64 `json:"calcium"`
+	Iron               float64 `json:"iron"`
 }
 
 // FoodVariant represents a food variant with nutrition information
 type FoodVariant struct {
-	ID                   string                 `json:"id"`
-	ServingSize          float64                `json:"serving_size"`
-	ServingUnit          string                 `json:"serving_unit"`
-	Calories             float64                `json:"calories"`
-	Protein              float64                `json:"protein"`
-	Carbs                float64                `json:"carbs"`
-	Fat                  float64                `json:"fat"`
-	SaturatedFat         float64                `json:"saturated_fat"`
-	PolyunsaturatedFat   float64                `json:"polyunsaturated_fat"`
-	MonounsaturatedFat   float64                `json:"monounsaturated_fat"`
-	TransFat             float64                `json:"trans_fat"`
-	Cholesterol          float64                `json:"cholesterol"`
-	Sodium               float64                `json:"sodium"`
-	Potassium            float64                `json:"potassium"`
-	DietaryFiber         float64                `json:"dietary_fiber"`
-	Sugars               float64                `json:"sugars"`
-	VitaminA             float64                `json:"vitamin_a"`
-	VitaminC             float64                `json:"vitamin_c"`
-	Calcium              float64                `json:"calcium"`
-	Iron                 float64                `json:"iron"`
-	IsDefault            bool                   `json:"is_default"`
-	GlycemicIndex        *string                `json:"glycemic_index"`
-	CustomNutrients      map[string]interface{} `json:"custom_nutrients"`
+	ID          string  `json:"id"`
+	FoodID      string  `json:"food_id,omitempty"`
+	ServingSize float64 `json:"serving_size"`
+	ServingUnit string  `json:"serving_unit"`
+	Nutrients
+	IsDefault       bool                   `json:"is_default"`
+	GlycemicIndex   *string                `json:"glycemic_index"`
+	CustomNutrients map[string]interface{} `json:"custom_nutrients"`
 }
 
 // SearchFoodsResponse represents the response from the search foods endpoint
@@ -50,29 +57,13 @@ type SearchFoodsResponse struct {
 // AddFoodVariantRequest represents the request to add a variant to an existing food
 // Backend endpoint: POST /foods/food-variants
 type AddFoodVariantRequest struct {
-	FoodID               string                 `json:"food_id"`
-	ServingSize          float64                `json:"serving_size"`
-	ServingUnit          string                 `json:"serving_unit"`
-	Calories             float64                `json:"calories"`
-	Protein              float64                `json:"protein"`
-	Carbs                float64                `json:"carbs"`
-	Fat                  float64                `json:"fat"`
-	SaturatedFat         float64                `json:"saturated_fat"`
-	PolyunsaturatedFat   float64                `json:"polyunsaturated_fat"`
-	MonounsaturatedFat   float64                `json:"monounsaturated_fat"`
-	TransFat             float64                `json:"trans_fat"`
-	Cholesterol          float64                `json:"cholesterol"`
-	Sodium               float64                `json:"sodium"`
-	Potassium            float64                `json:"potassium"`
-	DietaryFiber         float64                `json:"dietary_fiber"`
-	Sugars               float64                `json:"sugars"`
-	VitaminA             float64                `json:"vitamin_a"`
-	VitaminC             float64                `json:"vitamin_c"`
-	Calcium              float64                `json:"calcium"`
-	Iron                 float64                `json:"iron"`
-	IsDefault            bool                   `json:"is_default"`
-	GlycemicIndex        *string                `json:"glycemic_index,omitempty"`
-	CustomNutrients      map[string]interface{} `json:"custom_nutrients,omitempty"`
+	FoodID      string  `json:"food_id"`
+	ServingSize float64 `json:"serving_size"`
+	ServingUnit string  `json:"serving_unit"`
+	Nutrients
+	IsDefault       bool                   `json:"is_default"`
+	GlycemicIndex   *string                `json:"glycemic_index,omitempty"`
+	CustomNutrients map[string]interface{} `json:"custom_nutrients,omitempty"`
 }
 
 // AddFoodVariantResponse represents the response from adding a food variant
@@ -80,3 +71,158 @@ type AddFoodVariantRequest struct {
 type AddFoodVariantResponse struct {
 	ID string `json:"id"`
 }
+
+// CreateFoodRequest represents the request to create a food with its default variant
+// Backend endpoint: POST /foods
+type CreateFoodRequest struct {
+	Name        string  `json:"name"`
+	Brand       string  `json:"brand"`
+	IsCustom    bool    `json:"is_custom"`
+	IsQuickFood bool    `json:"is_quick_food"`
+	ServingSize float64 `json:"serving_size"`
+	ServingUnit string  `json:"serving_unit"`
+	Nutrients
+	IsDefault       bool                   `json:"is_default"`
+	GlycemicIndex   string                 `json:"glycemic_index"`
+	CustomNutrients map[string]interface{} `json:"custom_nutrients"`
+}
+
+// CreateFoodResponse represents the response from creating a food
+// Backend returns 201 with the food and its nested default variant
+type CreateFoodResponse struct {
+	ID             string                   `json:"id"`
+	Name           string                   `json:"name"`
+	Brand          string                   `json:"brand"`
+	IsCustom       bool                     `json:"is_custom"`
+	UserID         string                   `json:"user_id"`
+	DefaultVariant *CreateFoodVariantNested `json:"default_variant"`
+}
+
+// CreateFoodVariantNested represents the nested variant in create food response
+type CreateFoodVariantNested struct {
+	ID string `json:"id"`
+}
+
+// UpdateFoodRequest represents the request to update a food's metadata
+// Backend endpoint: PUT /foods/{id}
+type UpdateFoodRequest struct {
+	Name             string  `json:"name"`
+	Brand            *string `json:"brand"`
+	SharedWithPublic bool    `json:"shared_with_public"`
+}
+
+// UpdateFoodVariantRequest represents the request to replace a variant's serving and nutrition
+// Backend endpoint: PUT /foods/food-variants/{id}
+type UpdateFoodVariantRequest struct {
+	FoodID      string  `json:"food_id"`
+	ServingSize float64 `json:"serving_size"`
+	ServingUnit string  `json:"serving_unit"`
+	Nutrients
+	IsDefault       bool                   `json:"is_default"`
+	GlycemicIndex   *string                `json:"glycemic_index,omitempty"`
+	CustomNutrients map[string]interface{} `json:"custom_nutrients,omitempty"`
+}
+
+// Meal types accepted by the food diary
+const (
+	MealTypeBreakfast = "breakfast"
+	MealTypeLunch     = "lunch"
+	MealTypeDinner    = "dinner"
+	MealTypeSnacks    = "snacks"
+)
+
+// MealTypes lists the meal types in diary order
+var MealTypes = []string{MealTypeBreakfast, MealTypeLunch, MealTypeDinner, MealTypeSnacks}
+
+// FoodEntry represents a food diary entry.
+// The backend snapshots the food name and the variant's serving and
+// per-serving nutrients when the entry is logged; Quantity is expressed in
+// Unit, which is the variant's serving unit.
+type FoodEntry struct {
+	ID          string  `json:"id"`
+	FoodID      string  `json:"food_id"`
+	VariantID   string  `json:"variant_id"`
+	MealType    string  `json:"meal_type"`
+	Quantity    float64 `json:"quantity"`
+	Unit        string  `json:"unit"`
+	EntryDate   string  `json:"entry_date"`
+	FoodName    string  `json:"food_name"`
+	BrandName   *string `json:"brand_name"`
+	ServingSize float64 `json:"serving_size"`
+	ServingUnit string  `json:"serving_unit"`
+	Nutrients
+}
+
+// Servings returns how many servings of the variant the entry represents
+func (e *FoodEntry) Servings() float64 {
+	if e.ServingSize <= 0 {
+		return 0
+	}
+	return e.Quantity / e.ServingSize
+}
+
+// LoggedNutrients returns the nutrients actually consumed for the entry
+func (e *FoodEntry) LoggedNutrients() Nutrients {
+	return e.Nutrients.Scale(e.Servings())
+}
+
+// CreateFoodEntryRequest represents the request to log a food to the diary
+// Backend endpoint: POST /food-entries
+type CreateFoodEntryRequest struct {
+	FoodID    string  `json:"food_id"`
+	VariantID string  `json:"variant_id"`
+	MealType  string  `json:"meal_type"`
+	Quantity  float64 `json:"quantity"`
+	Unit      string  `json:"unit"`
+	EntryDate string  `json:"entry_date"`
+}
+
+// UpdateFoodEntryRequest represents the request to replace a diary entry.
+// When the variant changes, the backend re-snapshots the food and nutrients.
+// Backend endpoint: PUT /food-entries/{id}
+type UpdateFoodEntryRequest struct {
+	FoodID    string  `json:"food_id"`
+	VariantID string  `json:"variant_id"`
+	MealType  string  `json:"meal_type"`
+	Quantity  float64 `json:"quantity"`
+	Unit      string  `json:"unit"`
+	EntryDate string  `json:"entry_date"`
+}
+
+// Meal represents a saved meal: a reusable list of food variants and quantities
+type Meal struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Description *string    `json:"description"`
+	IsPublic    bool       `json:"is_public"`
+	Foods       []MealFood `json:"foods"`
+}
+
+// MealFood is one component of a saved meal. Quantity is expressed in Unit,
+// the variant's serving unit, as for diary entries.
+type MealFood struct {
+	FoodID    string  `json:"food_id"`
+	VariantID string  `json:"variant_id"`
+	Quantity  float64 `json:"quantity"`
+	Unit      string  `json:"unit"`
+	FoodName  string  `json:"food_name,omitempty"`
+}
+
+// CreateMealRequest represents the request to save a meal
+// Backend endpoint: POST /meals
+type CreateMealRequest struct {
+	Name        string     `json:"name"`
+	Description *string    `json:"description,omitempty"`
+	IsPublic    bool       `json:"is_public"`
+	Foods       []MealFood `json:"foods"`
+}
+
+// CustomNutrient is a user-defined nutrient tracked in addition to the
+// standard fields. Variants store custom nutrient amounts in
+// CustomNutrients, keyed by Name and expressed in Unit.
+// Backend endpoint: GET /custom-nutrients
+type CustomNutrient struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Unit string `json:"unit"`
+}
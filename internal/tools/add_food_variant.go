@@ -4,41 +4,40 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/chickenzord/sparkyfitness-mcp/internal/nutrition"
 	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
 // AddFoodVariantInput defines the input parameters for the add_food_variant tool
 type AddFoodVariantInput struct {
-	FoodID               string   `json:"food_id" jsonschema:"required,Unique identifier of the existing food (from search_foods results)"`
-	ServingSize          float64  `json:"serving_size" jsonschema:"required,Numeric serving size amount (e.g., 100, 1)"`
-	ServingUnit          string   `json:"serving_unit" jsonschema:"required,Unit of measurement (e.g., g, ml, cup, piece)"`
-	Calories             float64  `json:"calories" jsonschema:"required,Calories per serving"`
-	Protein              float64  `json:"protein" jsonschema:"required,Protein in grams"`
-	Carbs                float64  `json:"carbs" jsonschema:"required,Carbohydrates in grams"`
-	Fat                  float64  `json:"fat" jsonschema:"required,Fat in grams"`
-	SaturatedFat         *float64 `json:"saturated_fat,omitempty" jsonschema:"Saturated fat in grams"`
-	PolyunsaturatedFat   *float64 `json:"polyunsaturated_fat,omitempty" jsonschema:"Polyunsaturated fat in grams"`
-	MonounsaturatedFat   *float64 `json:"monounsaturated_fat,omitempty" jsonschema:"Monounsaturated fat in grams"`
-	TransFat             *float64 `json:"trans_fat,omitempty" jsonschema:"Trans fat in grams"`
-	Cholesterol          *float64 `json:"cholesterol,omitempty" jsonschema:"Cholesterol in milligrams"`
-	Sodium               *float64 `json:"sodium,omitempty" jsonschema:"Sodium in milligrams"`
-	Potassium            *float64 `json:"potassium,omitempty" jsonschema:"Potassium in milligrams"`
-	DietaryFiber         *float64 `json:"dietary_fiber,omitempty" jsonschema:"Dietary fiber in grams"`
-	Sugars               *float64 `json:"sugars,omitempty" jsonschema:"Sugars in grams"`
-	VitaminA             *float64 `json:"vitamin_a,omitempty" jsonschema:"Vitamin A"`
-	VitaminC             *float64 `json:"vitamin_c,omitempty" jsonschema:"Vitamin C"`
-	Calcium              *float64 `json:"calcium,omitempty" jsonschema:"Calcium"`
-	Iron                 *float64 `json:"iron,omitempty" jsonschema:"Iron"`
-	IsDefault            *bool    `json:"is_default,omitempty" jsonschema:"Set this variant as the food's default variant (default: false)"`
-	GlycemicIndex        *string  `json:"glycemic_index,omitempty" jsonschema:"Glycemic index if available"`
+	FoodID        string   `json:"food_id" jsonschema:"required,Unique identifier of the existing food (from search_foods results)"`
+	ServingSize   float64  `json:"serving_size" jsonschema:"required,Numeric serving size amount (e.g., 100, 1)"`
+	ServingUnit   string   `json:"serving_unit" jsonschema:"required,Unit of measurement (e.g., g, ml, cup, piece)"`
+	FromVariantID string   `json:"from_variant_id,omitempty" jsonschema:"Existing variant of the same food to derive the nutrition from, instead of giving the nutrients"`
+	Density       *float64 `json:"density,omitempty" jsonschema:"Density in g/ml, only needed to derive between mass and volume units (e.g., 1.03 for milk)"`
+	Calories      *float64 `json:"calories,omitempty" jsonschema:"Calories (kcal) per serving (required unless energy_kj or from_variant_id is given)"`
+	EnergyKJ      *float64 `json:"energy_kj,omitempty" jsonschema:"Energy in kilojoules per serving, as on EU/Australian labels; converted to kcal and cross-checked against calories if both are given"`
+	Protein       *float64 `json:"protein,omitempty" jsonschema:"Protein in grams (required unless from_variant_id is given)"`
+	Carbs         *float64 `json:"carbs,omitempty" jsonschema:"Carbohydrates in grams (required unless from_variant_id is given)"`
+	Fat           *float64 `json:"fat,omitempty" jsonschema:"Fat in grams (required unless from_variant_id is given)"`
+	OptionalNutrientsInput
+	LabelConversionsInput
+	CustomNutrients []CustomNutrientInput `json:"custom_nutrients,omitempty" jsonschema:"Amounts of the user's custom nutrients (e.g., caffeine), validated against the nutrients configured in SparkyFitness"`
+	IsDefault       *bool                 `json:"is_default,omitempty" jsonschema:"Set this variant as the food's default variant (default: false)"`
+	GlycemicIndex   *string               `json:"glycemic_index,omitempty" jsonschema:"Glycemic index if available"`
+	Strict          bool                  `json:"strict,omitempty" jsonschema:"Refuse to save when the label check has warnings, not only errors (default: false)"`
 }
 
 // AddFoodVariantOutput defines the output structure
 type AddFoodVariantOutput struct {
-	FoodID    string `json:"food_id" jsonschema:"ID of the food this variant was added to"`
-	VariantID string `json:"variant_id" jsonschema:"ID of the newly created variant"`
-	Message   string `json:"message" jsonschema:"Success message"`
+	FoodID      string           `json:"food_id" jsonschema:"ID of the food this variant was added to"`
+	VariantID   string           `json:"variant_id" jsonschema:"ID of the newly created variant"`
+	DerivedFrom string           `json:"derived_from,omitempty" jsonschema:"Variant the nutrition was derived from, if from_variant_id was given"`
+	Nutrients   *NutrientsResult `json:"nutrients,omitempty" jsonschema:"Derived nutrients of the new variant"`
+	Warnings    []string         `json:"warnings,omitempty" jsonschema:"Label check warnings; verify these values with the user"`
+	Conversions []string         `json:"conversions,omitempty" jsonschema:"Unit conversions applied to the input (e.g., kJ to kcal, salt to sodium, %DV to amounts)"`
+	Message     string           `json:"message" jsonschema:"Success message"`
 }
 
 // RegisterAddFoodVariant registers the add_food_variant tool with the MCP server
@@ -49,7 +48,8 @@ func (r *Registry) RegisterAddFoodVariant(server *mcp.Server, client *sparkyfitn
 		Description: "➕ Add a new serving size variant to an EXISTING food in SparkyFitness.\n\n" +
 			"**When to Use:**\n" +
 			"• search_foods found a matching food, AND\n" +
-			"• User confirms they want to add a new serving size to that existing food (not create a separate entry)\n\n" +
+			"• User confirms they want to add a new serving size to that existing food (not create a separate entry), AND\n" +
+			"• get_food shows the food does NOT already have this serving size\n\n" +
 			"**What This Does:**\n" +
 			"Adds another serving size option to an existing food entry. For example:\n" +
 			"• Existing food 'Enoki Mushroom' has a 100g variant\n" +
@@ -59,18 +59,33 @@ func (r *Registry) RegisterAddFoodVariant(server *mcp.Server, client *sparkyfitn
 			"• food_id: UUID from search_foods results (identifies which food to add variant to)\n" +
 			"• serving_size: Numeric amount (e.g., 100, 1.5)\n" +
 			"• serving_unit: Unit of measurement (g, ml, cup, piece, oz, etc.)\n" +
-			"• Core nutrition: calories, protein, carbs, fat (all required)\n" +
-			"• Optional nutrition: fiber, sugar, vitamins, minerals, etc.\n\n" +
+			"• Core nutrition: calories (or energy_kj), protein, carbs, fat (all required)\n" +
+			"• Energy in kJ: pass energy_kj instead of (or together with) calories; never put a kJ value in calories\n" +
+			"• Optional nutrition: fiber, sugar, vitamins, minerals, etc.\n" +
+			"• EU labels: salt in grams (converted to sodium); 'saturates' is saturated_fat, 'of which sugars' is sugars\n" +
+			"• US labels: daily_values for vitamins and minerals given only as %DV (e.g., {\"iron\": 10})\n" +
+			"• custom_nutrients: amounts of the user's own tracked nutrients (name, amount, unit); unknown names are rejected\n" +
+			"• strict: also refuse to save on label check warnings (optional)\n\n" +
+			"**Label Check:**\n" +
+			"The values are checked like in create_food_variant: errors block the save, warnings are returned.\n\n" +
+			"**Deriving From an Existing Variant:**\n" +
+			"Instead of the nutrition, pass from_variant_id (from get_food) and the nutrients are scaled to the new serving, " +
+			"converting between g, kg, oz, lb and between ml, l, cup, tbsp, tsp, fl oz. " +
+			"Converting between mass and volume (e.g., 1 cup from a 100 g variant) also needs density in g/ml.\n\n" +
 			"**Output:**\n" +
 			"• variant_id: UUID of the newly created variant\n" +
+			"• nutrients: the derived nutrition, when from_variant_id was used\n" +
+			"• warnings: label check warnings, if any\n" +
 			"• Success message confirming addition\n\n" +
 			"**Example Workflow:**\n" +
 			"User: 'I have a 150g serving of Enoki Mushroom'\n" +
 			"1. search_foods(name='Enoki Mushroom') → finds food_id='abc-123' with 100g variant\n" +
-			"2. Show user: 'Found existing Enoki Mushroom with 100g variant. Add 150g variant?'\n" +
-			"3. User: 'Yes, add variant'\n" +
-			"4. add_food_variant(food_id='abc-123', serving_size=150, ...nutrition data)\n" +
-			"5. Result: Enoki Mushroom now has TWO variants (100g and 150g)",
+			"2. get_food(food_id='abc-123') → only a 100g variant exists\n" +
+			"3. Show user: 'Found existing Enoki Mushroom with 100g variant. Add 150g variant?'\n" +
+			"4. User: 'Yes, add variant'\n" +
+			"5. add_food_variant(food_id='abc-123', serving_size=150, ...nutrition data)\n" +
+			"6. Result: Enoki Mushroom now has TWO variants (100g and 150g)\n" +
+			"Without the 150g nutrition data: add_food_variant(food_id='abc-123', serving_size=150, serving_unit='g', from_variant_id=<100g variant_id>)",
 	}
 
 	handler := func(ctx context.Context, request *mcp.CallToolRequest, input AddFoodVariantInput) (*mcp.CallToolResult, AddFoodVariantOutput, error) {
@@ -90,52 +105,71 @@ func (r *Registry) RegisterAddFoodVariant(server *mcp.Server, client *sparkyfitn
 			FoodID:      input.FoodID,
 			ServingSize: input.ServingSize,
 			ServingUnit: input.ServingUnit,
-			Calories:    input.Calories,
-			Protein:     input.Protein,
-			Carbs:       input.Carbs,
-			Fat:         input.Fat,
 		}
 
-		// Add optional fields
-		if input.SaturatedFat != nil {
-			req.SaturatedFat = *input.SaturatedFat
-		}
-		if input.PolyunsaturatedFat != nil {
-			req.PolyunsaturatedFat = *input.PolyunsaturatedFat
-		}
-		if input.MonounsaturatedFat != nil {
-			req.MonounsaturatedFat = *input.MonounsaturatedFat
-		}
-		if input.TransFat != nil {
-			req.TransFat = *input.TransFat
-		}
-		if input.Cholesterol != nil {
-			req.Cholesterol = *input.Cholesterol
-		}
-		if input.Sodium != nil {
-			req.Sodium = *input.Sodium
-		}
-		if input.Potassium != nil {
-			req.Potassium = *input.Potassium
-		}
-		if input.DietaryFiber != nil {
-			req.DietaryFiber = *input.DietaryFiber
-		}
-		if input.Sugars != nil {
-			req.Sugars = *input.Sugars
-		}
-		if input.VitaminA != nil {
-			req.VitaminA = *input.VitaminA
-		}
-		if input.VitaminC != nil {
-			req.VitaminC = *input.VitaminC
+		var source *sparkyfitness.FoodVariant
+		var conversions []string
+		if input.FromVariantID != "" {
+			// Derive nutrition by scaling the source variant
+			if input.Calories != nil || input.EnergyKJ != nil || input.Protein != nil || input.Carbs != nil || input.Fat != nil {
+				return nil, AddFoodVariantOutput{}, fmt.Errorf("provide either from_variant_id or the nutrients, not both")
+			}
+			var err error
+			source, err = client.GetFoodVariant(ctx, input.FromVariantID)
+			if err != nil {
+				return nil, AddFoodVariantOutput{}, backendError("get food variant", err)
+			}
+			if source.FoodID != "" && source.FoodID != input.FoodID {
+				return nil, AddFoodVariantOutput{}, fmt.Errorf("variant %s belongs to food %s, not %s", source.ID, source.FoodID, input.FoodID)
+			}
+
+			var density float64
+			if input.Density != nil {
+				density = *input.Density
+			}
+			derived, err := nutrition.ScaleVariant(*source, input.ServingSize, input.ServingUnit, density)
+			if err != nil {
+				return nil, AddFoodVariantOutput{}, fmt.Errorf("cannot derive %g %s from %g %s: %w",
+					input.ServingSize, input.ServingUnit, source.ServingSize, source.ServingUnit, err)
+			}
+			req.Nutrients = derived.Nutrients.Map(round2)
+			req.GlycemicIndex = derived.GlycemicIndex
+			req.CustomNutrients = roundCustomNutrients(derived.CustomNutrients)
+		} else {
+			if input.Protein == nil || input.Carbs == nil || input.Fat == nil {
+				return nil, AddFoodVariantOutput{}, fmt.Errorf("calories, protein, carbs and fat are required unless from_variant_id is given")
+			}
+			calories, applied, err := resolveCalories(input.Calories, input.EnergyKJ)
+			if err != nil {
+				return nil, AddFoodVariantOutput{}, err
+			}
+			conversions = applied
+			req.Nutrients = sparkyfitness.Nutrients{
+				Calories: calories,
+				Protein:  *input.Protein,
+				Carbs:    *input.Carbs,
+				Fat:      *input.Fat,
+			}
 		}
-		if input.Calcium != nil {
-			req.Calcium = *input.Calcium
+
+		// Set optional nutrition fields
+		set := input.OptionalNutrientsInput.applyTo(&req.Nutrients)
+
+		// Convert salt and %DV label values
+		converted, err := input.LabelConversionsInput.applyTo(&req.Nutrients, set)
+		if err != nil {
+			return nil, AddFoodVariantOutput{}, err
 		}
-		if input.Iron != nil {
-			req.Iron = *input.Iron
+		conversions = append(conversions, converted...)
+
+		// Validate custom nutrients against the user's definitions
+		req.CustomNutrients, converted, err = resolveCustomNutrients(ctx, client, input.CustomNutrients, req.CustomNutrients)
+		if err != nil {
+			return nil, AddFoodVariantOutput{}, err
 		}
+		conversions = append(conversions, converted...)
+
+		// Set optional variant fields
 		if input.IsDefault != nil {
 			req.IsDefault = *input.IsDefault
 		}
@@ -143,17 +177,36 @@ func (r *Registry) RegisterAddFoodVariant(server *mcp.Server, client *sparkyfitn
 			req.GlycemicIndex = input.GlycemicIndex
 		}
 
+		// Check the label before writing
+		warnings, err := checkLabel(sparkyfitness.FoodVariant{
+			ServingSize: req.ServingSize,
+			ServingUnit: req.ServingUnit,
+			Nutrients:   req.Nutrients,
+		}, input.Strict)
+		if err != nil {
+			return nil, AddFoodVariantOutput{}, err
+		}
+
 		// Call backend API to add variant
 		resp, err := client.AddFoodVariant(ctx, req)
 		if err != nil {
-			return nil, AddFoodVariantOutput{}, fmt.Errorf("failed to add food variant: %w", err)
+			return nil, AddFoodVariantOutput{}, backendError("add food variant", err)
 		}
 
 		// Prepare output
 		output := AddFoodVariantOutput{
-			FoodID:    input.FoodID,
-			VariantID: resp.ID,
-			Message:   fmt.Sprintf("Successfully added variant to existing food (variant ID: %s)", resp.ID),
+			FoodID:      input.FoodID,
+			VariantID:   resp.ID,
+			Warnings:    warnings,
+			Conversions: conversions,
+			Message:     fmt.Sprintf("Successfully added variant to existing food (variant ID: %s)", resp.ID),
+		}
+		if source != nil {
+			nutrients := convertNutrientsToResult(req.Nutrients)
+			output.DerivedFrom = source.ID
+			output.Nutrients = &nutrients
+			output.Message = fmt.Sprintf("Successfully added %g %s variant derived from %g %s (%.0f kcal, variant ID: %s)",
+				input.ServingSize, input.ServingUnit, source.ServingSize, source.ServingUnit, req.Calories, resp.ID)
 		}
 
 		return nil, output, nil
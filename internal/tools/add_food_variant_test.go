@@ -0,0 +1,239 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+)
+
+func TestAddFoodVariant(t *testing.T) {
+	backend, session := newTestSession(t)
+	food := backend.AddFood(sparkyfitness.Food{Name: "Enoki Mushroom", IsCustom: true},
+		sparkyfitness.FoodVariant{ServingSize: 100, ServingUnit: "g", Nutrients: sparkyfitness.Nutrients{Calories: 37, Protein: 2.7, Carbs: 7.8, Fat: 0.3}},
+	)
+
+	out := callTool[AddFoodVariantOutput](t, session, "add_food_variant", map[string]any{
+		"food_id":      food.ID,
+		"serving_size": 150,
+		"serving_unit": "g",
+		"calories":     55.5,
+		"protein":      4.05,
+		"carbs":        11.7,
+		"fat":          0.45,
+	})
+
+	_, variants, _ := backend.Food(food.ID)
+	if len(variants) != 2 || variants[1].ID != out.VariantID || variants[1].ServingSize != 150 {
+		t.Errorf("variants = %+v, want new 150g variant %s", variants, out.VariantID)
+	}
+	if variants[1].IsDefault {
+		t.Errorf("new variant became default without is_default")
+	}
+}
+
+func TestAddFoodVariantUnknownFood(t *testing.T) {
+	_, session := newTestSession(t)
+
+	msg := callToolError(t, session, "add_food_variant", map[string]any{
+		"food_id":      "does-not-exist",
+		"serving_size": 1,
+		"serving_unit": "cup",
+		"calories":     100,
+		"protein":      1,
+		"carbs":        1,
+		"fat":          1,
+	})
+	if !strings.Contains(msg, "not found") || !strings.Contains(msg, "search_foods") {
+		t.Errorf("error = %q, want actionable not-found message", msg)
+	}
+}
+
+func TestAddFoodVariantDerived(t *testing.T) {
+	backend, session := newTestSession(t)
+	milk := backend.AddFood(sparkyfitness.Food{Name: "Whole Milk"},
+		sparkyfitness.FoodVariant{
+			ServingSize:     1,
+			ServingUnit:     "cup",
+			Nutrients:       sparkyfitness.Nutrients{Calories: 150, Protein: 8, Carbs: 12, Fat: 8, Calcium: 300},
+			CustomNutrients: map[string]interface{}{"Choline": 40.0},
+		},
+	)
+	other := backend.AddFood(sparkyfitness.Food{Name: "Oat Milk"},
+		sparkyfitness.FoodVariant{ServingSize: 1, ServingUnit: "cup", Nutrients: sparkyfitness.Nutrients{Calories: 120}},
+	)
+
+	tests := []struct {
+		name         string
+		args         map[string]any
+		wantCalories float64
+		wantCalcium  float64
+		wantCholine  float64
+		wantErr      string
+	}{
+		{
+			name:         "volume conversion",
+			args:         map[string]any{"serving_size": 250, "serving_unit": "ml"},
+			wantCalories: 158.5,
+			wantCalcium:  317.01,
+			wantCholine:  42.27,
+		},
+		{
+			name:         "mass with density",
+			args:         map[string]any{"serving_size": 100, "serving_unit": "g", "density": 1.03},
+			wantCalories: 61.55,
+			wantCalcium:  123.11,
+			wantCholine:  16.41,
+		},
+		{
+			name:    "mass without density",
+			args:    map[string]any{"serving_size": 100, "serving_unit": "g"},
+			wantErr: "density",
+		},
+		{
+			name:    "nutrients given too",
+			args:    map[string]any{"serving_size": 2, "serving_unit": "cup", "calories": 300},
+			wantErr: "not both",
+		},
+		{
+			name:    "variant of another food",
+			args:    map[string]any{"serving_size": 2, "serving_unit": "cup", "from_variant_id": other.DefaultVariant.ID},
+			wantErr: "belongs to food",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := map[string]any{"food_id": milk.ID, "from_variant_id": milk.DefaultVariant.ID}
+			for k, v := range tt.args {
+				args[k] = v
+			}
+
+			if tt.wantErr != "" {
+				msg := callToolError(t, session, "add_food_variant", args)
+				if !strings.Contains(msg, tt.wantErr) {
+					t.Errorf("error = %q, should contain %q", msg, tt.wantErr)
+				}
+				return
+			}
+
+			out := callTool[AddFoodVariantOutput](t, session, "add_food_variant", args)
+			if out.DerivedFrom != milk.DefaultVariant.ID || out.Nutrients == nil {
+				t.Fatalf("output = %+v, want derived nutrients", out)
+			}
+			if out.Nutrients.Calories != tt.wantCalories || out.Nutrients.Calcium != tt.wantCalcium {
+				t.Errorf("Nutrients = %+v, want %v kcal, %v calcium", out.Nutrients, tt.wantCalories, tt.wantCalcium)
+			}
+
+			_, variants, _ := backend.Food(milk.ID)
+			added := variants[len(variants)-1]
+			if added.ID != out.VariantID || added.Calories != tt.wantCalories {
+				t.Errorf("backend variant = %+v, want %v kcal", added, tt.wantCalories)
+			}
+			if got := added.CustomNutrients["Choline"]; got != tt.wantCholine {
+				t.Errorf("backend variant choline = %v, want %v (rounded)", got, tt.wantCholine)
+			}
+		})
+	}
+}
+
+func TestAddFoodVariantMissingNutrients(t *testing.T) {
+	backend, session := newTestSession(t)
+	food := backend.AddFood(sparkyfitness.Food{Name: "Tofu"},
+		sparkyfitness.FoodVariant{ServingSize: 100, ServingUnit: "g", Nutrients: sparkyfitness.Nutrients{Calories: 76}},
+	)
+
+	msg := callToolError(t, session, "add_food_variant", map[string]any{
+		"food_id":      food.ID,
+		"serving_size": 50,
+		"serving_unit": "g",
+		"calories":     38,
+	})
+	if !strings.Contains(msg, "required unless from_variant_id") {
+		t.Errorf("error = %q, want missing nutrients message", msg)
+	}
+}
+
+func TestAddFoodVariantLabelConversions(t *testing.T) {
+	tests := []struct {
+		name            string
+		args            map[string]any
+		wantSodium      float64
+		wantCalcium     float64
+		wantConversions int
+		wantErr         string
+	}{
+		{
+			name:            "salt",
+			args:            map[string]any{"salt": 1.2},
+			wantSodium:      480,
+			wantConversions: 1,
+		},
+		{
+			name:            "salt matching sodium",
+			args:            map[string]any{"salt": 1.2, "sodium": 470},
+			wantSodium:      470,
+			wantConversions: 1,
+		},
+		{
+			name:    "salt contradicting sodium",
+			args:    map[string]any{"salt": 1.2, "sodium": 1200},
+			wantErr: "salt and sodium do not match",
+		},
+		{
+			name:            "daily values",
+			args:            map[string]any{"daily_values": map[string]any{"calcium": 20, "iron": 10}},
+			wantCalcium:     260,
+			wantConversions: 2,
+		},
+		{
+			name:    "daily value and amount",
+			args:    map[string]any{"calcium": 200, "daily_values": map[string]any{"calcium": 20}},
+			wantErr: "provide only one",
+		},
+		{
+			name:    "unsupported daily value",
+			args:    map[string]any{"daily_values": map[string]any{"sugars": 20}},
+			wantErr: "no daily value for \"sugars\"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend, session := newTestSession(t)
+			food := backend.AddFood(sparkyfitness.Food{Name: "Crispbread"},
+				sparkyfitness.FoodVariant{ServingSize: 100, ServingUnit: "g", Nutrients: sparkyfitness.Nutrients{Calories: 340}},
+			)
+			args := map[string]any{
+				"food_id":      food.ID,
+				"serving_size": 30,
+				"serving_unit": "g",
+				"calories":     102,
+				"protein":      3,
+				"carbs":        19,
+				"fat":          0.6,
+			}
+			for k, v := range tt.args {
+				args[k] = v
+			}
+
+			if tt.wantErr != "" {
+				msg := callToolError(t, session, "add_food_variant", args)
+				if !strings.Contains(msg, tt.wantErr) {
+					t.Errorf("error = %q, should contain %q", msg, tt.wantErr)
+				}
+				return
+			}
+
+			out := callTool[AddFoodVariantOutput](t, session, "add_food_variant", args)
+			if len(out.Conversions) != tt.wantConversions {
+				t.Errorf("Conversions = %v, want %d", out.Conversions, tt.wantConversions)
+			}
+			_, variants, _ := backend.Food(food.ID)
+			added := variants[len(variants)-1]
+			if added.Sodium != tt.wantSodium || added.Calcium != tt.wantCalcium {
+				t.Errorf("variant sodium = %v, calcium = %v, want %v, %v", added.Sodium, added.Calcium, tt.wantSodium, tt.wantCalcium)
+			}
+		})
+	}
+}
@@ -0,0 +1,279 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/nutrition"
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxBatchFoods caps the number of foods in one batch_create_foods call
+const maxBatchFoods = 50
+
+// Batch item statuses
+const (
+	batchCreated          = "created"
+	batchSkippedDuplicate = "skipped_duplicate"
+	batchFailed           = "failed"
+)
+
+// BatchCreateFoodsInput defines the input parameters for the batch_create_foods tool
+type BatchCreateFoodsInput struct {
+	Foods          []CreateFoodInput `json:"foods" jsonschema:"required,Foods to create, each with the same fields as create_food_variant"`
+	SkipDuplicates *bool             `json:"skip_duplicates,omitempty" jsonschema:"Skip foods that very likely exist already (default: true)"`
+}
+
+// BatchFoodResult reports the outcome of one food in the batch
+type BatchFoodResult struct {
+	Index       int                    `json:"index" jsonschema:"Position of the food in the input list"`
+	Name        string                 `json:"name" jsonschema:"Name of the food"`
+	Status      string                 `json:"status" jsonschema:"created, skipped_duplicate or failed"`
+	FoodID      string                 `json:"food_id,omitempty" jsonschema:"ID of the created food"`
+	Variants    []CreatedVariantResult `json:"variants,omitempty" jsonschema:"Created variants, default first"`
+	DuplicateOf *DuplicateCandidate    `json:"duplicate_of,omitempty" jsonschema:"Existing food this one duplicates, when skipped"`
+	Warnings    []string               `json:"warnings,omitempty" jsonschema:"Label check warnings; verify these values with the user"`
+	Error       string                 `json:"error,omitempty" jsonschema:"Reason the food was skipped or failed"`
+}
+
+// BatchCreateFoodsOutput defines the output structure
+type BatchCreateFoodsOutput struct {
+	Items   []BatchFoodResult `json:"items" jsonschema:"Outcome of every food, in input order"`
+	Created int               `json:"created" jsonschema:"Number of foods created"`
+	Skipped int               `json:"skipped" jsonschema:"Number of foods skipped as duplicates"`
+	Failed  int               `json:"failed" jsonschema:"Number of foods that could not be created"`
+	Message string            `json:"message" jsonschema:"Result summary"`
+}
+
+// RegisterBatchCreateFoods registers the batch_create_foods tool with the MCP server
+func (r *Registry) RegisterBatchCreateFoods(server *mcp.Server, client *sparkyfitness.Client) error {
+	tool := &mcp.Tool{
+		Name:  "batch_create_foods",
+		Title: "Create Many Foods at Once",
+		Description: "📦 Create many foods in one call, with duplicate checks, e.g., from photos of a whole shopping haul.\n\n" +
+			"**When to Use:**\n" +
+			"• Several new foods to add at once — use this instead of calling create_food_variant one by one\n\n" +
+			"**How it works:**\n" +
+			fmt.Sprintf("• Up to %d foods, each with the same fields as create_food_variant (including additional_variants)\n", maxBatchFoods) +
+			"• Each food is first checked like find_duplicate_foods; foods that very likely exist already are skipped, " +
+			"as are repeats within the batch once the food was created (skip_duplicates=false creates them anyway)\n" +
+			"• Foods are processed in parallel; progress notifications are sent while it runs\n" +
+			"• One failing food does not stop the others\n\n" +
+			"**Required Input:**\n" +
+			"• foods: list of food definitions\n\n" +
+			"**Output:**\n" +
+			"• items: per-food status (created / skipped_duplicate with the existing food / failed with reason) — report skips and failures to the user\n" +
+			"• Counts of created, skipped and failed foods",
+	}
+
+	handler := func(ctx context.Context, request *mcp.CallToolRequest, input BatchCreateFoodsInput) (*mcp.CallToolResult, BatchCreateFoodsOutput, error) {
+		// Validate required parameters
+		if len(input.Foods) == 0 {
+			return nil, BatchCreateFoodsOutput{}, fmt.Errorf("foods must list at least one food")
+		}
+		if len(input.Foods) > maxBatchFoods {
+			return nil, BatchCreateFoodsOutput{}, fmt.Errorf("foods lists %d foods; at most %d can be created per call, split the list", len(input.Foods), maxBatchFoods)
+		}
+
+		skipDuplicates := true
+		if input.SkipDuplicates != nil {
+			skipDuplicates = *input.SkipDuplicates
+		}
+
+		items := make([]BatchFoodResult, len(input.Foods))
+		for i, food := range input.Foods {
+			items[i] = BatchFoodResult{Index: i, Name: food.Name}
+		}
+
+		// Group repeats within the batch, so that each group is processed in
+		// order and a repeat is only created if the foods before it failed
+		var groups [][]int
+	grouping:
+		for i, food := range input.Foods {
+			if skipDuplicates {
+				for g, group := range groups {
+					if sameBatchFood(food, input.Foods[group[0]]) {
+						groups[g] = append(groups[g], i)
+						continue grouping
+					}
+				}
+			}
+			groups = append(groups, []int{i})
+		}
+
+		progress := newBatchProgress(request, len(items))
+
+		// Call backend API for every group, a few at a time
+		client.Concurrently(len(groups), func(g int) {
+			first := -1
+			for _, i := range groups[g] {
+				switch {
+				case first >= 0:
+					items[i] = repeatedBatchFood(items[i], items[first])
+				case ctx.Err() != nil:
+					items[i].Status = batchFailed
+					items[i].Error = fmt.Sprintf("not created: %v", ctx.Err())
+				default:
+					items[i] = createBatchFood(ctx, client, i, input.Foods[i], skipDuplicates)
+					if items[i].Status != batchFailed {
+						first = i
+					}
+				}
+				progress.done(ctx, items[i].Name)
+			}
+		})
+
+		// Prepare output
+		output := BatchCreateFoodsOutput{Items: items}
+		for _, item := range items {
+			switch item.Status {
+			case batchCreated:
+				output.Created++
+			case batchSkippedDuplicate:
+				output.Skipped++
+			default:
+				output.Failed++
+			}
+		}
+		output.Message = fmt.Sprintf("Created %d of %d food(s); %d skipped as duplicates, %d failed",
+			output.Created, len(items), output.Skipped, output.Failed)
+
+		return nil, output, nil
+	}
+
+	mcp.AddTool(server, tool, handler)
+	return nil
+}
+
+// createBatchFood checks one food for duplicates, unless disabled, and
+// creates it
+func createBatchFood(ctx context.Context, client *sparkyfitness.Client, index int, food CreateFoodInput, skipDuplicates bool) BatchFoodResult {
+	result := BatchFoodResult{Index: index, Name: food.Name}
+
+	if skipDuplicates {
+		check := duplicateInput(food)
+		check.Limit = ptr(1)
+		duplicates, err := findDuplicateFoods(ctx, client, check)
+		if err != nil {
+			result.Status = batchFailed
+			result.Error = fmt.Sprintf("duplicate check failed: %v", err)
+			return result
+		}
+		if duplicates.Recommendation == "add_variant" {
+			result.Status = batchSkippedDuplicate
+			result.DuplicateOf = &duplicates.Candidates[0]
+			result.Error = duplicates.Message
+			return result
+		}
+	}
+
+	created, err := createFood(ctx, client, food)
+	if err != nil {
+		result.Status = batchFailed
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Status = batchCreated
+	result.FoodID = created.FoodID
+	result.Variants = created.Variants
+	result.Warnings = created.Warnings
+	return result
+}
+
+// repeatedBatchFood reports a batch item as a repeat of an earlier item with
+// the same food that was created or found to exist already
+func repeatedBatchFood(item, first BatchFoodResult) BatchFoodResult {
+	item.Status = batchSkippedDuplicate
+	item.DuplicateOf = first.DuplicateOf
+	if first.Status == batchCreated {
+		item.Error = fmt.Sprintf("same food as foods[%d], created as food_id %s", first.Index, first.FoodID)
+	} else {
+		item.Error = fmt.Sprintf("same food as foods[%d], which exists already", first.Index)
+	}
+	return item
+}
+
+// duplicateInput describes a food to create as find_duplicate_foods input
+func duplicateInput(food CreateFoodInput) FindDuplicateFoodsInput {
+	calories := food.Calories
+	if calories == nil && food.EnergyKJ != nil {
+		calories = ptr(nutrition.KJToKcal(*food.EnergyKJ))
+	}
+	return FindDuplicateFoodsInput{
+		Name:        food.Name,
+		Brand:       food.Brand,
+		ServingSize: ptr(food.ServingSize),
+		ServingUnit: food.ServingUnit,
+		Calories:    calories,
+		Protein:     ptr(food.Protein),
+		Carbs:       ptr(food.Carbs),
+		Fat:         ptr(food.Fat),
+	}
+}
+
+// sameBatchFood reports whether two batch items very likely describe the
+// same food, scored like find_duplicate_foods scores an existing food:
+// matching name and brand, and nutrition per 100 g or 100 ml when comparable
+func sameBatchFood(food, earlier CreateFoodInput) bool {
+	label, labelFields, err := labelVariant(duplicateInput(food))
+	if err != nil {
+		label, labelFields = nil, nil
+	}
+	variant, _, err := labelVariant(duplicateInput(earlier))
+	if err != nil || variant == nil {
+		variant = &sparkyfitness.FoodVariant{ServingSize: earlier.ServingSize, ServingUnit: earlier.ServingUnit}
+	}
+
+	brand := ""
+	if food.Brand != nil {
+		brand = strings.TrimSpace(*food.Brand)
+	}
+	candidate := scoreDuplicate(food.Name, brand, label, labelFields,
+		sparkyfitness.Food{Name: earlier.Name, Brand: earlier.Brand, DefaultVariant: variant})
+	return candidate.Level == "high"
+}
+
+// batchProgress sends a progress notification each time a batch item is
+// done, if the client asked for progress with a progress token
+type batchProgress struct {
+	mu      sync.Mutex
+	session *mcp.ServerSession
+	token   any
+	total   int
+	count   int
+}
+
+// newBatchProgress returns a progress reporter for a batch of total items
+func newBatchProgress(request *mcp.CallToolRequest, total int) *batchProgress {
+	p := &batchProgress{total: total}
+	if request != nil && request.Params != nil {
+		p.session = request.Session
+		p.token = request.Params.GetProgressToken()
+	}
+	return p
+}
+
+// done records that one more item is done. Notifications are best effort:
+// a failed notification does not affect the batch.
+func (p *batchProgress) done(ctx context.Context, name string) {
+	// Only the count is guarded, so a slow client does not hold up the
+	// other workers while a notification is sent
+	p.mu.Lock()
+	p.count++
+	count := p.count
+	p.mu.Unlock()
+
+	if p.session == nil || p.token == nil {
+		return
+	}
+	_ = p.session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+		ProgressToken: p.token,
+		Progress:      float64(count),
+		Total:         float64(p.total),
+		Message:       fmt.Sprintf("%d/%d: %s", count, p.total, name),
+	})
+}
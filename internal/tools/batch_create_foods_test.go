@@ -0,0 +1,167 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness/sparkyfitnesstest"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestBatchCreateFoods(t *testing.T) {
+	backend := sparkyfitnesstest.NewServer(t)
+
+	var mu sync.Mutex
+	var notes []*mcp.ProgressNotificationParams
+	session := connectTestSession(t, backend, &mcp.ClientOptions{
+		ProgressNotificationHandler: func(ctx context.Context, req *mcp.ProgressNotificationClientRequest) {
+			mu.Lock()
+			defer mu.Unlock()
+			notes = append(notes, req.Params)
+		},
+	})
+
+	fage := "Fage"
+	backend.AddFood(sparkyfitness.Food{Name: "Greek Yogurt", Brand: &fage},
+		sparkyfitness.FoodVariant{ServingSize: 170, ServingUnit: "g", Nutrients: sparkyfitness.Nutrients{Calories: 100, Protein: 18, Carbs: 6, Fat: 0}},
+	)
+
+	food := func(name string, kcal, protein, carbs, fat float64) map[string]any {
+		return map[string]any{"name": name, "serving_size": 100, "serving_unit": "g", "calories": kcal, "protein": protein, "carbs": carbs, "fat": fat}
+	}
+	yogurt := food("Greek Yogurt", 59, 10.6, 3.5, 0)
+	yogurt["brand"] = "Fage"
+	badLabel := food("Mystery Bar", 400, 10, 20, 5)
+	badLabel["sugars"] = 30
+
+	params := &mcp.CallToolParams{Name: "batch_create_foods", Arguments: map[string]any{"foods": []map[string]any{
+		food("Rolled Oats", 379, 13, 68, 6.5),
+		yogurt,
+		food("Almond Butter", 614, 21, 19, 56),
+		food("rolled oats", 379, 13, 68, 6.5),
+		badLabel,
+	}}}
+	// SetProgressToken only stores the token when Meta is already set
+	params.Meta = mcp.Meta{}
+	params.SetProgressToken("batch-1")
+	result, err := session.CallTool(context.Background(), params)
+	if err != nil || result.IsError {
+		t.Fatalf("CallTool() = %v, %v", toolErrorText(result), err)
+	}
+	raw, _ := json.Marshal(result.StructuredContent)
+	var out BatchCreateFoodsOutput
+	if err := json.Unmarshal(raw, &out); err != nil {
+		t.Fatalf("failed to decode output: %v", err)
+	}
+
+	wantStatus := []string{"created", "skipped_duplicate", "created", "skipped_duplicate", "failed"}
+	for i, item := range out.Items {
+		if item.Index != i || item.Status != wantStatus[i] {
+			t.Errorf("items[%d] = %+v, want status %s", i, item, wantStatus[i])
+		}
+	}
+	if out.Created != 2 || out.Skipped != 2 || out.Failed != 1 {
+		t.Errorf("counts = %d created, %d skipped, %d failed", out.Created, out.Skipped, out.Failed)
+	}
+	if dup := out.Items[1].DuplicateOf; dup == nil || dup.FoodName != "Greek Yogurt" {
+		t.Errorf("items[1].DuplicateOf = %+v", dup)
+	}
+	if !strings.Contains(out.Items[3].Error, "foods[0]") || !strings.Contains(out.Items[4].Error, "sugars") {
+		t.Errorf("errors = %q, %q", out.Items[3].Error, out.Items[4].Error)
+	}
+	if out.Items[0].FoodID == "" || len(out.Items[0].Variants) != 1 {
+		t.Errorf("items[0] = %+v, want food and variant IDs", out.Items[0])
+	}
+	if got := len(backend.Foods()); got != 3 {
+		t.Errorf("foods = %d, want 3", got)
+	}
+
+	// Notifications are sent while the call runs and may still be in
+	// flight when it returns
+	deadline := time.Now().Add(time.Second)
+	for {
+		mu.Lock()
+		n := len(notes)
+		mu.Unlock()
+		if n == 5 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if len(notes) != 5 {
+		t.Fatalf("progress notifications = %d, want 5", len(notes))
+	}
+	for _, n := range notes {
+		if n.ProgressToken != "batch-1" || n.Total != 5 {
+			t.Errorf("notification = %+v", n)
+		}
+	}
+}
+
+func TestBatchCreateFoodsRepeatAfterFailure(t *testing.T) {
+	backend, session := newTestSession(t)
+
+	bar := func(sugars float64) map[string]any {
+		return map[string]any{"name": "Mystery Bar", "serving_size": 40, "serving_unit": "g", "calories": 160, "protein": 4, "carbs": 20, "fat": 7, "sugars": sugars}
+	}
+
+	// The first bar fails the label check, so the repeat with the corrected
+	// sugars is created instead and the third is its duplicate
+	out := callTool[BatchCreateFoodsOutput](t, session, "batch_create_foods", map[string]any{"foods": []map[string]any{bar(30), bar(12), bar(12)}})
+
+	wantStatus := []string{"failed", "created", "skipped_duplicate"}
+	for i, item := range out.Items {
+		if item.Status != wantStatus[i] {
+			t.Errorf("items[%d] = %+v, want status %s", i, item, wantStatus[i])
+		}
+	}
+	if !strings.Contains(out.Items[2].Error, "foods[1]") || !strings.Contains(out.Items[2].Error, out.Items[1].FoodID) {
+		t.Errorf("items[2].Error = %q, want a reference to foods[1]", out.Items[2].Error)
+	}
+	if got := len(backend.Foods()); got != 1 {
+		t.Errorf("foods = %d, want 1", got)
+	}
+}
+
+func TestBatchCreateFoodsRepeatsByNutrition(t *testing.T) {
+	backend, session := newTestSession(t)
+
+	// A powder sold under the same name is a different food, while the
+	// same butter per 32 g serving is a repeat of the per 100 g label
+	foods := []map[string]any{
+		{"name": "Peanut Butter", "serving_size": 100, "serving_unit": "g", "calories": 588, "protein": 25, "carbs": 20, "fat": 50},
+		{"name": "Peanut Butter", "serving_size": 100, "serving_unit": "g", "calories": 380, "protein": 50, "carbs": 30, "fat": 12},
+		{"name": "Peanut Butter", "serving_size": 32, "serving_unit": "g", "calories": 188, "protein": 8, "carbs": 6.4, "fat": 16},
+	}
+	out := callTool[BatchCreateFoodsOutput](t, session, "batch_create_foods", map[string]any{"foods": foods})
+
+	wantStatus := []string{"created", "created", "skipped_duplicate"}
+	for i, item := range out.Items {
+		if item.Status != wantStatus[i] {
+			t.Errorf("items[%d] = %+v, want status %s", i, item, wantStatus[i])
+		}
+	}
+	if !strings.Contains(out.Items[2].Error, "foods[0]") {
+		t.Errorf("items[2].Error = %q, want a reference to foods[0]", out.Items[2].Error)
+	}
+	if got := len(backend.Foods()); got != 2 {
+		t.Errorf("foods = %d, want 2", got)
+	}
+}
+
+func TestBatchCreateFoodsValidation(t *testing.T) {
+	_, session := newTestSession(t)
+
+	msg := callToolError(t, session, "batch_create_foods", map[string]any{"foods": []map[string]any{}})
+
+	if !strings.Contains(msg, "at least one food") {
+		t.Errorf("error = %q", msg)
+	}
+}
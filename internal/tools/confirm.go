@@ -0,0 +1,55 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// confirmSchema is the elicitation form shown to the user: a single
+// confirmation checkbox
+var confirmSchema = map[string]any{
+	"type": "object",
+	"properties": map[string]any{
+		"confirm": map[string]any{
+			"type":        "boolean",
+			"title":       "Confirm",
+			"description": "Check to confirm this action",
+		},
+	},
+}
+
+// supportsElicitation reports whether the client connected to session can
+// answer elicitation requests
+func supportsElicitation(session *mcp.ServerSession) bool {
+	if session == nil {
+		return false
+	}
+	params := session.InitializeParams()
+	return params != nil && params.Capabilities != nil && params.Capabilities.Elicitation != nil
+}
+
+// confirmAction asks the user to confirm a destructive action via MCP
+// elicitation and reports whether they did. Clients without elicitation
+// support are not asked; for them the tool's destructive annotation is the
+// safeguard and the action proceeds.
+func confirmAction(ctx context.Context, request *mcp.CallToolRequest, message string) (bool, error) {
+	if request == nil || !supportsElicitation(request.Session) {
+		return true, nil
+	}
+
+	result, err := request.Session.Elicit(ctx, &mcp.ElicitParams{
+		Message:         message,
+		RequestedSchema: confirmSchema,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to ask the user for confirmation: %w", err)
+	}
+
+	if result.Action != "accept" {
+		return false, nil
+	}
+	confirmed, _ := result.Content["confirm"].(bool)
+	return confirmed, nil
+}
@@ -0,0 +1,149 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// CopyFoodEntriesInput defines the input parameters for the copy_food_entries tool
+type CopyFoodEntriesInput struct {
+	SourceDate     string `json:"source_date" jsonschema:"required,Date to copy from in YYYY-MM-DD format"`
+	SourceMealType string `json:"source_meal_type,omitempty" jsonschema:"Meal to copy: breakfast, lunch, dinner or snacks (omit to copy the whole day)"`
+	TargetDate     string `json:"target_date,omitempty" jsonschema:"Date to copy to in YYYY-MM-DD format (default: today)"`
+	TargetMealType string `json:"target_meal_type,omitempty" jsonschema:"Meal to log the copies to (omit to keep each entry's meal)"`
+}
+
+// CopyFoodEntryItem reports the outcome of copying one entry
+type CopyFoodEntryItem struct {
+	SourceEntryID string  `json:"source_entry_id" jsonschema:"ID of the copied entry"`
+	FoodName      string  `json:"food_name" jsonschema:"Name of the food"`
+	MealType      string  `json:"meal_type" jsonschema:"Meal the copy was logged to"`
+	Quantity      float64 `json:"quantity" jsonschema:"Amount copied"`
+	Unit          string  `json:"unit" jsonschema:"Unit of the quantity"`
+	Status        string  `json:"status" jsonschema:"copied or failed"`
+	NewEntryID    string  `json:"new_entry_id,omitempty" jsonschema:"ID of the new entry when copied"`
+	Error         string  `json:"error,omitempty" jsonschema:"Reason the copy failed"`
+}
+
+// CopyFoodEntriesOutput defines the output structure
+type CopyFoodEntriesOutput struct {
+	SourceDate string              `json:"source_date" jsonschema:"Date copied from"`
+	TargetDate string              `json:"target_date" jsonschema:"Date copied to"`
+	Items      []CopyFoodEntryItem `json:"items" jsonschema:"Outcome of every entry, in diary order"`
+	Copied     int                 `json:"copied" jsonschema:"Number of entries copied"`
+	Failed     int                 `json:"failed" jsonschema:"Number of entries that could not be copied"`
+	Totals     NutrientsResult     `json:"totals" jsonschema:"Nutrients added to the target date by the copied entries"`
+	Message    string              `json:"message" jsonschema:"Result summary"`
+}
+
+// RegisterCopyFoodEntries registers the copy_food_entries tool with the MCP server
+func (r *Registry) RegisterCopyFoodEntries(server *mcp.Server, client *sparkyfitness.Client) error {
+	tool := &mcp.Tool{
+		Name:  "copy_food_entries",
+		Title: "Copy Meal or Day in Food Diary",
+		Description: "📋 Copy a meal or a whole day of diary entries to another date in one call.\n\n" +
+			"**When to Use:**\n" +
+			"• 'I had the same breakfast as yesterday'\n" +
+			"• 'Log the same as Monday for today'\n" +
+			"• Use this instead of calling log_food_entry item by item\n\n" +
+			"**Required Input:**\n" +
+			"• source_date: YYYY-MM-DD to copy from\n" +
+			"• source_meal_type: one meal to copy (omit for the whole day)\n" +
+			"• target_date: YYYY-MM-DD to copy to (default: today)\n" +
+			"• target_meal_type: log copies to this meal instead (e.g., yesterday's lunch as today's dinner)\n\n" +
+			"**Output:**\n" +
+			"• items: per-entry status (copied/failed with reason) — report any failures to the user\n" +
+			"• totals: nutrients added to the target date",
+	}
+
+	handler := func(ctx context.Context, request *mcp.CallToolRequest, input CopyFoodEntriesInput) (*mcp.CallToolResult, CopyFoodEntriesOutput, error) {
+		// Validate required parameters
+		if input.SourceDate == "" {
+			return nil, CopyFoodEntriesOutput{}, fmt.Errorf("source_date parameter is required")
+		}
+		sourceDate, err := parseDiaryDate(input.SourceDate, r.timezone())
+		if err != nil {
+			return nil, CopyFoodEntriesOutput{}, fmt.Errorf("source_date: %w", err)
+		}
+		targetDate, err := parseDiaryDate(input.TargetDate, r.timezone())
+		if err != nil {
+			return nil, CopyFoodEntriesOutput{}, fmt.Errorf("target_date: %w", err)
+		}
+
+		req := &sparkyfitness.CopyFoodEntriesRequest{
+			SourceDate: sourceDate,
+			TargetDate: targetDate,
+		}
+		if input.SourceMealType != "" {
+			if req.SourceMealType, err = normalizeMealType(input.SourceMealType); err != nil {
+				return nil, CopyFoodEntriesOutput{}, err
+			}
+		}
+		if input.TargetMealType != "" {
+			if req.TargetMealType, err = normalizeMealType(input.TargetMealType); err != nil {
+				return nil, CopyFoodEntriesOutput{}, err
+			}
+		}
+		if sourceDate == targetDate && (req.TargetMealType == "" || req.TargetMealType == req.SourceMealType) {
+			return nil, CopyFoodEntriesOutput{}, fmt.Errorf("source and target are the same; choose another target_date or target_meal_type")
+		}
+
+		// Call backend API to copy entries
+		results, err := client.CopyFoodEntries(ctx, req)
+		if err != nil {
+			return nil, CopyFoodEntriesOutput{}, backendError("copy food entries", err)
+		}
+
+		// Prepare output
+		output := CopyFoodEntriesOutput{
+			SourceDate: sourceDate,
+			TargetDate: targetDate,
+			Items:      make([]CopyFoodEntryItem, 0, len(results)),
+		}
+		var totals sparkyfitness.Nutrients
+		for _, result := range results {
+			item := CopyFoodEntryItem{
+				SourceEntryID: result.Source.ID,
+				FoodName:      result.Source.FoodName,
+				MealType:      result.Source.MealType,
+				Quantity:      result.Source.Quantity,
+				Unit:          result.Source.Unit,
+			}
+			if req.TargetMealType != "" {
+				item.MealType = req.TargetMealType
+			}
+
+			if result.Err != nil {
+				item.Status = "failed"
+				item.Error = backendError("copy food entry", result.Err).Error()
+				output.Failed++
+			} else {
+				item.Status = "copied"
+				item.NewEntryID = result.Entry.ID
+				totals = totals.Add(result.Entry.LoggedNutrients())
+				output.Copied++
+			}
+			output.Items = append(output.Items, item)
+		}
+		output.Totals = convertNutrientsToResult(totals)
+
+		switch {
+		case len(results) == 0:
+			output.Message = fmt.Sprintf("No entries found to copy on %s", sourceDate)
+		case output.Failed == 0:
+			output.Message = fmt.Sprintf("Copied %d entries from %s to %s (%.0f kcal)",
+				output.Copied, sourceDate, targetDate, totals.Calories)
+		default:
+			output.Message = fmt.Sprintf("Copied %d of %d entries from %s to %s; %d failed (see items)",
+				output.Copied, len(results), sourceDate, targetDate, output.Failed)
+		}
+
+		return nil, output, nil
+	}
+
+	mcp.AddTool(server, tool, handler)
+	return nil
+}
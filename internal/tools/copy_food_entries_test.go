@@ -0,0 +1,126 @@
+package tools
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness/sparkyfitnesstest"
+)
+
+func TestCopyFoodEntries(t *testing.T) {
+	backend, session := newTestSession(t)
+	seedDiary(backend)
+
+	out := callTool[CopyFoodEntriesOutput](t, session, "copy_food_entries", map[string]any{
+		"source_date":      "2025-01-15",
+		"source_meal_type": "lunch",
+		"target_date":      "2025-01-20",
+		"target_meal_type": "dinner",
+	})
+
+	if out.Copied != 2 || out.Failed != 0 || len(out.Items) != 2 {
+		t.Fatalf("output = %+v", out)
+	}
+	if out.Totals.Calories != 273 {
+		t.Errorf("Totals.Calories = %v, want 273", out.Totals.Calories)
+	}
+
+	diary := callTool[GetFoodDiaryOutput](t, session, "get_food_diary", map[string]any{"date": "2025-01-20"})
+	if meals := diary.Days[0].Meals; len(meals) != 1 || meals[0].MealType != "dinner" || len(meals[0].Entries) != 2 {
+		t.Errorf("target diary = %+v", diary.Days[0])
+	}
+}
+
+func TestCopyFoodEntriesPartialFailure(t *testing.T) {
+	backend, session := newTestSession(t)
+	seedDiary(backend)
+	backend.InjectFault(sparkyfitnesstest.Fault{Method: http.MethodPost, Path: "/food-entries", Status: http.StatusInternalServerError, Body: `{"error":"db down"}`, Times: 1})
+
+	out := callTool[CopyFoodEntriesOutput](t, session, "copy_food_entries", map[string]any{
+		"source_date": "2025-01-15",
+		"target_date": "2025-01-20",
+	})
+
+	if out.Copied != 2 || out.Failed != 1 {
+		t.Fatalf("output = %+v", out)
+	}
+	// Entries are copied concurrently, so any one of them may hit the fault
+	for _, item := range out.Items {
+		switch item.Status {
+		case "failed":
+			if !strings.Contains(item.Error, "internal error") {
+				t.Errorf("failed item = %+v", item)
+			}
+		case "copied":
+			if item.NewEntryID == "" {
+				t.Errorf("copied item = %+v", item)
+			}
+		default:
+			t.Errorf("unexpected status in %+v", item)
+		}
+	}
+}
+
+func TestCopyFoodEntriesTotalsUseNewSnapshot(t *testing.T) {
+	backend, session := newTestSession(t)
+	_, egg := seedDiary(backend)
+
+	// The copies snapshot the corrected egg, not the logged one
+	callTool[UpdateFoodVariantOutput](t, session, "update_food_variant", map[string]any{
+		"variant_id": egg.DefaultVariant.ID,
+		"calories":   80,
+	})
+
+	out := callTool[CopyFoodEntriesOutput](t, session, "copy_food_entries", map[string]any{
+		"source_date":      "2025-01-15",
+		"source_meal_type": "lunch",
+		"target_date":      "2025-01-20",
+	})
+
+	// 150 g rice (195 kcal) + 1 egg (80 kcal)
+	if out.Copied != 2 || out.Totals.Calories != 275 {
+		t.Errorf("output = %+v, want totals of the new entries (275 kcal)", out)
+	}
+}
+
+func TestCopyFoodEntriesErrors(t *testing.T) {
+	_, session := newTestSession(t)
+
+	tests := []struct {
+		name         string
+		args         map[string]any
+		wantContains string
+	}{
+		{
+			name:         "same source and target",
+			args:         map[string]any{"source_date": "2025-01-15", "target_date": "2025-01-15"},
+			wantContains: "source and target are the same",
+		},
+		{
+			name:         "invalid meal type",
+			args:         map[string]any{"source_date": "2025-01-15", "target_meal_type": "brunch"},
+			wantContains: "meal_type must be one of",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := callToolError(t, session, "copy_food_entries", tt.args)
+			if !strings.Contains(msg, tt.wantContains) {
+				t.Errorf("error = %q, should contain %q", msg, tt.wantContains)
+			}
+		})
+	}
+}
+
+func TestCopyFoodEntriesBackendError(t *testing.T) {
+	backend, session := newTestSession(t)
+	backend.InjectFault(sparkyfitnesstest.Fault{Method: http.MethodGet, Path: "/food-entries/by-date/2025-01-15", Status: http.StatusInternalServerError, Body: `{"error":"db down"}`})
+
+	msg := callToolError(t, session, "copy_food_entries", map[string]any{"source_date": "2025-01-15", "target_date": "2025-01-16"})
+
+	if !strings.Contains(msg, "failed to copy food entries") {
+		t.Errorf("error = %q", msg)
+	}
+}
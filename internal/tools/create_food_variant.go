@@ -1,95 +1,76 @@
 package tools
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
+	"slices"
+	"time"
 
+	"github.com/chickenzord/sparkyfitness-mcp/internal/nutrition"
 	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
+// rollbackTimeout bounds deleting a partially created food
+const rollbackTimeout = 10 * time.Second
+
+// unknownGlycemicIndex is the backend's value for "no glycemic index". The
+// create food endpoint requires the field, and the web UI sends this value
+// when none is chosen.
+const unknownGlycemicIndex = "None"
+
 // CreateFoodInput defines the input parameters for the create_food_variant tool
 type CreateFoodInput struct {
-	Name                 string   `json:"name" jsonschema:"required,Food name"`
-	Brand                *string  `json:"brand,omitempty" jsonschema:"Brand name (optional)"`
-	ServingSize          float64  `json:"serving_size" jsonschema:"required,Numeric serving size amount (e.g., 100, 1)"`
-	ServingUnit          string   `json:"serving_unit" jsonschema:"required,Unit of measurement (e.g., g, ml, cup, piece)"`
-	Calories             float64  `json:"calories" jsonschema:"required,Calories per serving"`
-	Protein              float64  `json:"protein" jsonschema:"required,Protein in grams"`
-	Carbs                float64  `json:"carbs" jsonschema:"required,Carbohydrates in grams"`
-	Fat                  float64  `json:"fat" jsonschema:"required,Fat in grams"`
-	SaturatedFat         *float64 `json:"saturated_fat,omitempty" jsonschema:"Saturated fat in grams"`
-	PolyunsaturatedFat   *float64 `json:"polyunsaturated_fat,omitempty" jsonschema:"Polyunsaturated fat in grams"`
-	MonounsaturatedFat   *float64 `json:"monounsaturated_fat,omitempty" jsonschema:"Monounsaturated fat in grams"`
-	TransFat             *float64 `json:"trans_fat,omitempty" jsonschema:"Trans fat in grams"`
-	Cholesterol          *float64 `json:"cholesterol,omitempty" jsonschema:"Cholesterol in milligrams"`
-	Sodium               *float64 `json:"sodium,omitempty" jsonschema:"Sodium in milligrams"`
-	Potassium            *float64 `json:"potassium,omitempty" jsonschema:"Potassium in milligrams"`
-	DietaryFiber         *float64 `json:"dietary_fiber,omitempty" jsonschema:"Dietary fiber in grams"`
-	Sugars               *float64 `json:"sugars,omitempty" jsonschema:"Sugars in grams"`
-	VitaminA             *float64 `json:"vitamin_a,omitempty" jsonschema:"Vitamin A"`
-	VitaminC             *float64 `json:"vitamin_c,omitempty" jsonschema:"Vitamin C"`
-	Calcium              *float64 `json:"calcium,omitempty" jsonschema:"Calcium"`
-	Iron                 *float64 `json:"iron,omitempty" jsonschema:"Iron"`
-	IsQuickFood          *bool    `json:"is_quick_food,omitempty" jsonschema:"Mark as quick food (default: false)"`
-	IsDefault            *bool    `json:"is_default,omitempty" jsonschema:"Set this variant as default (default: true for first variant)"`
-	GlycemicIndex        *string  `json:"glycemic_index,omitempty" jsonschema:"Glycemic index if available"`
-}
-
-// CreateFoodOutput defines the output structure
-type CreateFoodOutput struct {
-	FoodID    string `json:"food_id" jsonschema:"ID of the created food"`
-	VariantID string `json:"variant_id" jsonschema:"ID of the created variant"`
-	Message   string `json:"message" jsonschema:"Success message"`
+	Name        string   `json:"name" jsonschema:"required,Food name"`
+	Brand       *string  `json:"brand,omitempty" jsonschema:"Brand name (optional)"`
+	ServingSize float64  `json:"serving_size" jsonschema:"required,Numeric serving size amount (e.g., 100, 1)"`
+	ServingUnit string   `json:"serving_unit" jsonschema:"required,Unit of measurement (e.g., g, ml, cup, piece)"`
+	Calories    *float64 `json:"calories,omitempty" jsonschema:"Calories (kcal) per serving (required unless energy_kj is given)"`
+	EnergyKJ    *float64 `json:"energy_kj,omitempty" jsonschema:"Energy in kilojoules per serving, as on EU/Australian labels; converted to kcal and cross-checked against calories if both are given"`
+	Protein     float64  `json:"protein" jsonschema:"required,Protein in grams"`
+	Carbs       float64  `json:"carbs" jsonschema:"required,Carbohydrates in grams"`
+	Fat         float64  `json:"fat" jsonschema:"required,Fat in grams"`
+	OptionalNutrientsInput
+	LabelConversionsInput
+	CustomNutrients    []CustomNutrientInput    `json:"custom_nutrients,omitempty" jsonschema:"Amounts of the user's custom nutrients (e.g., caffeine), validated against the nutrients configured in SparkyFitness"`
+	IsQuickFood        *bool                    `json:"is_quick_food,omitempty" jsonschema:"Mark as quick food (default: false)"`
+	IsDefault          *bool                    `json:"is_default,omitempty" jsonschema:"Set this variant as default (default: true for first variant)"`
+	GlycemicIndex      *string                  `json:"glycemic_index,omitempty" jsonschema:"Glycemic index if available"`
+	Strict             bool                     `json:"strict,omitempty" jsonschema:"Refuse to save when the label check has warnings, not only errors (default: false)"`
+	AdditionalVariants []AdditionalVariantInput `json:"additional_variants,omitempty" jsonschema:"More serving sizes from the same label (e.g., per serving next to per 100 g), added after the default variant"`
 }
 
-// CreateFoodRequest represents the backend API request structure for POST /foods
-type CreateFoodRequest struct {
-	Name                 string                 `json:"name"`
-	Brand                string                 `json:"brand"`
-	IsCustom             bool                   `json:"is_custom"`
-	IsQuickFood          bool                   `json:"is_quick_food"`
-	ServingSize          float64                `json:"serving_size"`
-	ServingUnit          string                 `json:"serving_unit"`
-	Calories             float64                `json:"calories"`
-	Protein              float64                `json:"protein"`
-	Carbs                float64                `json:"carbs"`
-	Fat                  float64                `json:"fat"`
-	SaturatedFat         float64                `json:"saturated_fat"`
-	PolyunsaturatedFat   float64                `json:"polyunsaturated_fat"`
-	MonounsaturatedFat   float64                `json:"monounsaturated_fat"`
-	TransFat             float64                `json:"trans_fat"`
-	Cholesterol          float64                `json:"cholesterol"`
-	Sodium               float64                `json:"sodium"`
-	Potassium            float64                `json:"potassium"`
-	DietaryFiber         float64                `json:"dietary_fiber"`
-	Sugars               float64                `json:"sugars"`
-	VitaminA             float64                `json:"vitamin_a"`
-	VitaminC             float64                `json:"vitamin_c"`
-	Calcium              float64                `json:"calcium"`
-	Iron                 float64                `json:"iron"`
-	IsDefault            bool                   `json:"is_default"`
-	GlycemicIndex        string                 `json:"glycemic_index"`
-	CustomNutrients      map[string]interface{} `json:"custom_nutrients"`
+// AdditionalVariantInput defines a serving size variant created together with the food
+type AdditionalVariantInput struct {
+	ServingSize float64  `json:"serving_size" jsonschema:"required,Numeric serving size amount (e.g., 30)"`
+	ServingUnit string   `json:"serving_unit" jsonschema:"required,Unit of measurement (e.g., g, ml, cup, piece)"`
+	Calories    *float64 `json:"calories,omitempty" jsonschema:"Calories (kcal) per serving (required unless energy_kj is given)"`
+	EnergyKJ    *float64 `json:"energy_kj,omitempty" jsonschema:"Energy in kilojoules per serving; converted to kcal and cross-checked against calories if both are given"`
+	Protein     float64  `json:"protein" jsonschema:"required,Protein in grams"`
+	Carbs       float64  `json:"carbs" jsonschema:"required,Carbohydrates in grams"`
+	Fat         float64  `json:"fat" jsonschema:"required,Fat in grams"`
+	OptionalNutrientsInput
+	LabelConversionsInput
+	CustomNutrients []CustomNutrientInput `json:"custom_nutrients,omitempty" jsonschema:"Amounts of the user's custom nutrients, validated against the nutrients configured in SparkyFitness"`
+	GlycemicIndex   *string               `json:"glycemic_index,omitempty" jsonschema:"Glycemic index if available"`
 }
 
-// CreateFoodResponse represents the backend API response for POST /foods
-type CreateFoodResponse struct {
-	ID             string                  `json:"id"`
-	Name           string                  `json:"name"`
-	Brand          string                  `json:"brand"`
-	IsCustom       bool                    `json:"is_custom"`
-	UserID         string                  `json:"user_id"`
-	DefaultVariant *CreateFoodVariantNested `json:"default_variant"`
+// CreatedVariantResult identifies a variant created with the food
+type CreatedVariantResult struct {
+	VariantID   string  `json:"variant_id" jsonschema:"ID of the variant"`
+	ServingSize float64 `json:"serving_size" jsonschema:"Serving size amount"`
+	ServingUnit string  `json:"serving_unit" jsonschema:"Unit of measurement for serving"`
+	IsDefault   bool    `json:"is_default" jsonschema:"Whether this is the food's default variant"`
 }
 
-// CreateFoodVariantNested represents the nested variant in create food response
-type CreateFoodVariantNested struct {
-	ID string `json:"id"`
+// CreateFoodOutput defines the output structure
+type CreateFoodOutput struct {
+	FoodID      string                 `json:"food_id" jsonschema:"ID of the created food"`
+	VariantID   string                 `json:"variant_id" jsonschema:"ID of the created default variant"`
+	Variants    []CreatedVariantResult `json:"variants" jsonschema:"Every created variant, default first"`
+	Warnings    []string               `json:"warnings,omitempty" jsonschema:"Label check warnings; verify these values with the user"`
+	Conversions []string               `json:"conversions,omitempty" jsonschema:"Unit conversions applied to the input (e.g., kJ to kcal, salt to sodium, %DV to amounts)"`
+	Message     string                 `json:"message" jsonschema:"Success message"`
 }
 
 // RegisterCreateFoodVariant registers the create_food_variant tool with the MCP server
@@ -109,11 +90,24 @@ func (r *Registry) RegisterCreateFoodVariant(server *mcp.Server, client *sparkyf
 			"• brand: Brand name (optional, e.g., 'Nature's Best')\n" +
 			"• serving_size: Numeric amount (e.g., 100, 1)\n" +
 			"• serving_unit: Unit of measurement (g, ml, cup, piece, oz, etc.)\n" +
-			"• Core nutrition: calories, protein, carbs, fat (all required)\n" +
-			"• Optional nutrition: fiber, sugar, vitamins, minerals, etc.\n\n" +
+			"• Core nutrition: calories (or energy_kj), protein, carbs, fat (all required)\n" +
+			"• Energy in kJ: pass energy_kj instead of (or together with) calories; never put a kJ value in calories\n" +
+			"• Optional nutrition: fiber, sugar, vitamins, minerals, etc.\n" +
+			"• EU labels: salt in grams (converted to sodium); 'saturates' is saturated_fat, 'of which sugars' is sugars\n" +
+			"• US labels: daily_values for vitamins and minerals given only as %DV (e.g., {\"iron\": 10})\n" +
+			"• custom_nutrients: amounts of the user's own tracked nutrients (name, amount, unit); unknown names are rejected\n" +
+			"• strict: also refuse to save on label check warnings (optional)\n" +
+			"• additional_variants: more servings from the same label, e.g., 'per serving (30 g)' next to 'per 100 g' " +
+			"(same nutrition fields; no add_food_variant call needed)\n\n" +
+			"**Label Check:**\n" +
+			"Before saving, the values are checked for negative amounts, calories that do not match 4/4/9 kcal per g " +
+			"protein/carbs/fat (e.g., kJ entered as kcal), fat or carb components exceeding their totals and implausible amounts. " +
+			"Errors block the save; warnings are returned so you can re-check the label.\n\n" +
 			"**Output:**\n" +
 			"• food_id: UUID of the newly created food\n" +
 			"• variant_id: UUID of the default variant\n" +
+			"• variants: every created variant with its variant_id, default first\n" +
+			"• warnings: label check warnings, if any\n" +
 			"• Success message\n\n" +
 			"**Example Workflow:**\n" +
 			"User: 'Add nutrition for Organic Quinoa by Nature's Best'\n" +
@@ -127,158 +121,247 @@ func (r *Registry) RegisterCreateFoodVariant(server *mcp.Server, client *sparkyf
 	}
 
 	handler := func(ctx context.Context, request *mcp.CallToolRequest, input CreateFoodInput) (*mcp.CallToolResult, CreateFoodOutput, error) {
-		// Validate required parameters
-		if input.Name == "" {
-			return nil, CreateFoodOutput{}, fmt.Errorf("name parameter is required")
-		}
-		if input.ServingSize <= 0 {
-			return nil, CreateFoodOutput{}, fmt.Errorf("serving_size must be greater than 0")
-		}
-		if input.ServingUnit == "" {
-			return nil, CreateFoodOutput{}, fmt.Errorf("serving_unit parameter is required")
+		output, err := createFood(ctx, client, input)
+		if err != nil {
+			return nil, CreateFoodOutput{}, err
 		}
+		return nil, output, nil
+	}
 
-		// Build request for backend API
-		req := &CreateFoodRequest{
-			Name:            input.Name,
-			Brand:           "",
-			IsCustom:        true, // MCP-created foods are always custom
-			IsQuickFood:     false,
-			ServingSize:     input.ServingSize,
-			ServingUnit:     input.ServingUnit,
-			Calories:        input.Calories,
-			Protein:         input.Protein,
-			Carbs:           input.Carbs,
-			Fat:             input.Fat,
-			IsDefault:       true, // First variant is always default
-			GlycemicIndex:   "None",
-			CustomNutrients: make(map[string]interface{}),
-		}
+	mcp.AddTool(server, tool, handler)
+	return nil
+}
 
-		// Set optional brand
-		if input.Brand != nil {
-			req.Brand = *input.Brand
-		}
+// createFood validates input and creates the food with its default and
+// additional variants. It backs create_food_variant and batch_create_foods.
+func createFood(ctx context.Context, client *sparkyfitness.Client, input CreateFoodInput) (CreateFoodOutput, error) {
+	// Validate required parameters
+	if input.Name == "" {
+		return CreateFoodOutput{}, fmt.Errorf("name parameter is required")
+	}
+	if input.ServingSize <= 0 {
+		return CreateFoodOutput{}, fmt.Errorf("serving_size must be greater than 0")
+	}
+	if input.ServingUnit == "" {
+		return CreateFoodOutput{}, fmt.Errorf("serving_unit parameter is required")
+	}
+	calories, conversions, err := resolveCalories(input.Calories, input.EnergyKJ)
+	if err != nil {
+		return CreateFoodOutput{}, err
+	}
 
-		// Set optional nutrition fields
-		if input.SaturatedFat != nil {
-			req.SaturatedFat = *input.SaturatedFat
-		}
-		if input.PolyunsaturatedFat != nil {
-			req.PolyunsaturatedFat = *input.PolyunsaturatedFat
-		}
-		if input.MonounsaturatedFat != nil {
-			req.MonounsaturatedFat = *input.MonounsaturatedFat
-		}
-		if input.TransFat != nil {
-			req.TransFat = *input.TransFat
-		}
-		if input.Cholesterol != nil {
-			req.Cholesterol = *input.Cholesterol
-		}
-		if input.Sodium != nil {
-			req.Sodium = *input.Sodium
-		}
-		if input.Potassium != nil {
-			req.Potassium = *input.Potassium
-		}
-		if input.DietaryFiber != nil {
-			req.DietaryFiber = *input.DietaryFiber
-		}
-		if input.Sugars != nil {
-			req.Sugars = *input.Sugars
-		}
-		if input.VitaminA != nil {
-			req.VitaminA = *input.VitaminA
-		}
-		if input.VitaminC != nil {
-			req.VitaminC = *input.VitaminC
-		}
-		if input.Calcium != nil {
-			req.Calcium = *input.Calcium
-		}
-		if input.Iron != nil {
-			req.Iron = *input.Iron
-		}
-		if input.IsQuickFood != nil {
-			req.IsQuickFood = *input.IsQuickFood
-		}
-		if input.GlycemicIndex != nil {
-			req.GlycemicIndex = *input.GlycemicIndex
-		}
+	// Build request for backend API
+	req := &sparkyfitness.CreateFoodRequest{
+		Name:        input.Name,
+		Brand:       "",
+		IsCustom:    true, // MCP-created foods are always custom
+		IsQuickFood: false,
+		ServingSize: input.ServingSize,
+		ServingUnit: input.ServingUnit,
+		Nutrients: sparkyfitness.Nutrients{
+			Calories: calories,
+			Protein:  input.Protein,
+			Carbs:    input.Carbs,
+			Fat:      input.Fat,
+		},
+		IsDefault:       true, // First variant is always default
+		GlycemicIndex:   unknownGlycemicIndex,
+		CustomNutrients: make(map[string]interface{}),
+	}
 
-		// Call backend API to create food + variant
-		resp, err := createFood(ctx, client, req)
+	// Set optional brand
+	if input.Brand != nil {
+		req.Brand = *input.Brand
+	}
+
+	// Set optional nutrition fields
+	set := input.OptionalNutrientsInput.applyTo(&req.Nutrients)
+
+	// Convert salt and %DV label values
+	converted, err := input.LabelConversionsInput.applyTo(&req.Nutrients, set)
+	if err != nil {
+		return CreateFoodOutput{}, err
+	}
+	conversions = append(conversions, converted...)
+
+	// Validate custom nutrients against the user's definitions
+	req.CustomNutrients, converted, err = resolveCustomNutrients(ctx, client, input.CustomNutrients, req.CustomNutrients)
+	if err != nil {
+		return CreateFoodOutput{}, err
+	}
+	conversions = append(conversions, converted...)
+
+	// Set optional food and variant fields
+	if input.IsQuickFood != nil {
+		req.IsQuickFood = *input.IsQuickFood
+	}
+	if input.GlycemicIndex != nil {
+		req.GlycemicIndex = *input.GlycemicIndex
+	}
+
+	// Check the label before writing
+	warnings, err := checkLabel(sparkyfitness.FoodVariant{
+		ServingSize: req.ServingSize,
+		ServingUnit: req.ServingUnit,
+		Nutrients:   req.Nutrients,
+	}, input.Strict)
+	if err != nil {
+		return CreateFoodOutput{}, err
+	}
+
+	// Validate the additional variants before writing anything
+	servings := []sparkyfitness.FoodVariant{{ServingSize: req.ServingSize, ServingUnit: req.ServingUnit}}
+	additional := make([]*sparkyfitness.AddFoodVariantRequest, 0, len(input.AdditionalVariants))
+	for i, v := range input.AdditionalVariants {
+		prefix := fmt.Sprintf("additional_variants[%d]", i)
+		variantReq, variantWarnings, variantConversions, err := v.resolve(ctx, client, input.Strict)
 		if err != nil {
-			return nil, CreateFoodOutput{}, fmt.Errorf("failed to create food: %w", err)
+			return CreateFoodOutput{}, fmt.Errorf("%s: %w", prefix, err)
 		}
+		if slices.ContainsFunc(servings, func(s sparkyfitness.FoodVariant) bool {
+			return s.ServingSize == v.ServingSize && nutrition.SameUnit(s.ServingUnit, v.ServingUnit)
+		}) {
+			return CreateFoodOutput{}, fmt.Errorf("%s: the %g %s serving is given more than once", prefix, v.ServingSize, v.ServingUnit)
+		}
+		servings = append(servings, sparkyfitness.FoodVariant{ServingSize: v.ServingSize, ServingUnit: v.ServingUnit})
+		additional = append(additional, variantReq)
+		warnings = append(warnings, prefixAll(prefix, variantWarnings)...)
+		conversions = append(conversions, prefixAll(prefix, variantConversions)...)
+	}
+
+	// Call backend API to create food + variant
+	resp, err := client.CreateFood(ctx, req)
+	if err != nil {
+		return CreateFoodOutput{}, backendError("create food", err)
+	}
 
-		// Prepare output
-		foodName := resp.Name
-		if resp.Brand != "" {
-			foodName = fmt.Sprintf("%s (%s)", resp.Name, resp.Brand)
+	// The food exists already, so delete it again rather than leave a food
+	// behind that the caller was told failed
+	if resp.DefaultVariant == nil {
+		err := fmt.Errorf("failed to create food: backend response did not include the default variant")
+		if rollbackErr := rollbackFood(ctx, client, resp.ID); rollbackErr != nil {
+			return CreateFoodOutput{}, fmt.Errorf("%w; removing the partially created food also failed (%v), "+
+				"so food_id %s is left behind: check it with get_food and delete it with delete_food if needed",
+				err, rollbackErr, resp.ID)
 		}
+		return CreateFoodOutput{}, fmt.Errorf("%w; the new food was deleted again, so nothing was created", err)
+	}
+
+	variants := []CreatedVariantResult{{
+		VariantID:   resp.DefaultVariant.ID,
+		ServingSize: req.ServingSize,
+		ServingUnit: req.ServingUnit,
+		IsDefault:   true,
+	}}
 
-		output := CreateFoodOutput{
-			FoodID:    resp.ID,
-			VariantID: resp.DefaultVariant.ID,
-			Message:   fmt.Sprintf("Successfully created new food '%s' with default variant", foodName),
+	// Call backend API to add the additional variants, deleting the new
+	// food again if one fails so no half-created food is left behind
+	for i, variantReq := range additional {
+		variantReq.FoodID = resp.ID
+		variantResp, err := client.AddFoodVariant(ctx, variantReq)
+		if err != nil {
+			err = backendError(fmt.Sprintf("add additional_variants[%d]", i), err)
+			if rollbackErr := rollbackFood(ctx, client, resp.ID); rollbackErr != nil {
+				return CreateFoodOutput{}, fmt.Errorf("%w; removing the partially created food also failed (%v), "+
+					"so food_id %s is left with %d variant(s): delete it with delete_food or add the missing variants with add_food_variant",
+					err, rollbackErr, resp.ID, len(variants))
+			}
+			return CreateFoodOutput{}, fmt.Errorf("%w; the new food was deleted again, so nothing was created", err)
 		}
+		variants = append(variants, CreatedVariantResult{
+			VariantID:   variantResp.ID,
+			ServingSize: variantReq.ServingSize,
+			ServingUnit: variantReq.ServingUnit,
+		})
+	}
 
-		return nil, output, nil
+	// Prepare output
+	foodName := resp.Name
+	if resp.Brand != "" {
+		foodName = fmt.Sprintf("%s (%s)", resp.Name, resp.Brand)
 	}
 
-	mcp.AddTool(server, tool, handler)
-	return nil
+	output := CreateFoodOutput{
+		FoodID:      resp.ID,
+		VariantID:   resp.DefaultVariant.ID,
+		Variants:    variants,
+		Warnings:    warnings,
+		Conversions: conversions,
+		Message:     fmt.Sprintf("Successfully created new food '%s' with default variant", foodName),
+	}
+	if len(variants) > 1 {
+		output.Message = fmt.Sprintf("Successfully created new food '%s' with %d variants", foodName, len(variants))
+	}
+
+	return output, nil
 }
 
-// createFood calls the backend API to create a food with variant
-// Backend endpoint: POST /foods
-func createFood(ctx context.Context, client *sparkyfitness.Client, req *CreateFoodRequest) (*CreateFoodResponse, error) {
-	// Marshal request body
-	body, err := json.Marshal(req)
+// rollbackFood deletes a partially created food. It runs even when ctx is
+// done, since a cancelled or timed out request is a common reason for the
+// creation to fail, and is bounded by rollbackTimeout instead.
+func rollbackFood(ctx context.Context, client *sparkyfitness.Client, foodID string) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), rollbackTimeout)
+	defer cancel()
+	return client.DeleteFood(ctx, foodID)
+}
+
+// resolve validates an additional variant and builds its backend request,
+// applying the same conversions and label check as the default variant. The
+// request's FoodID is left for the caller to set once the food exists.
+func (v *AdditionalVariantInput) resolve(ctx context.Context, client *sparkyfitness.Client, strict bool) (*sparkyfitness.AddFoodVariantRequest, []string, []string, error) {
+	if v.ServingSize <= 0 {
+		return nil, nil, nil, fmt.Errorf("serving_size must be greater than 0")
+	}
+	if v.ServingUnit == "" {
+		return nil, nil, nil, fmt.Errorf("serving_unit is required")
+	}
+	calories, conversions, err := resolveCalories(v.Calories, v.EnergyKJ)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+		return nil, nil, nil, err
 	}
 
-	// Build request URL - using reflection to get baseURL from client
-	// For now, we'll need to add a helper method to the client
-	baseURL := client.BaseURL()
-	reqURL := fmt.Sprintf("%s/foods", baseURL)
+	req := &sparkyfitness.AddFoodVariantRequest{
+		ServingSize: v.ServingSize,
+		ServingUnit: v.ServingUnit,
+		Nutrients: sparkyfitness.Nutrients{
+			Calories: calories,
+			Protein:  v.Protein,
+			Carbs:    v.Carbs,
+			Fat:      v.Fat,
+		},
+		GlycemicIndex: v.GlycemicIndex,
+	}
 
-	// Create HTTP request
-	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, reqURL, bytes.NewReader(body))
+	set := v.OptionalNutrientsInput.applyTo(&req.Nutrients)
+	converted, err := v.LabelConversionsInput.applyTo(&req.Nutrients, set)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, nil, nil, err
 	}
+	conversions = append(conversions, converted...)
 
-	// Set content type
-	httpReq.Header.Set("Content-Type", "application/json")
-
-	// Execute request (auth interceptor will add Bearer token)
-	httpClient := client.HTTPClient()
-	resp, err := httpClient.Do(httpReq)
+	req.CustomNutrients, converted, err = resolveCustomNutrients(ctx, client, v.CustomNutrients, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return nil, nil, nil, err
 	}
-	defer resp.Body.Close()
+	conversions = append(conversions, converted...)
 
-	// Read response body
-	respBody, err := io.ReadAll(resp.Body)
+	warnings, err := checkLabel(sparkyfitness.FoodVariant{
+		ServingSize: req.ServingSize,
+		ServingUnit: req.ServingUnit,
+		Nutrients:   req.Nutrients,
+	}, strict)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, nil, nil, err
 	}
 
-	// Check status code (backend returns 201 Created)
-	if resp.StatusCode != http.StatusCreated {
-		return nil, fmt.Errorf("unexpected status code: %d, body: %s", resp.StatusCode, string(respBody))
-	}
+	return req, warnings, conversions, nil
+}
 
-	// Parse response
-	var createResp CreateFoodResponse
-	if err := json.Unmarshal(respBody, &createResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+// prefixAll prefixes every message with the input it belongs to
+func prefixAll(prefix string, messages []string) []string {
+	prefixed := make([]string, 0, len(messages))
+	for _, m := range messages {
+		prefixed = append(prefixed, prefix+": "+m)
 	}
-
-	return &createResp, nil
+	return prefixed
 }
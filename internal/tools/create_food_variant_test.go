@@ -0,0 +1,368 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness/sparkyfitnesstest"
+)
+
+func TestCreateFoodVariant(t *testing.T) {
+	backend, session := newTestSession(t)
+
+	out := callTool[CreateFoodOutput](t, session, "create_food_variant", map[string]any{
+		"name":          "Organic Quinoa",
+		"brand":         "Nature's Best",
+		"serving_size":  100,
+		"serving_unit":  "g",
+		"calories":      368,
+		"protein":       14.1,
+		"carbs":         64.2,
+		"fat":           6.1,
+		"dietary_fiber": 7,
+	})
+
+	if out.FoodID == "" || out.VariantID == "" {
+		t.Fatalf("output = %+v, want food and variant IDs", out)
+	}
+
+	food, variants, ok := backend.Food(out.FoodID)
+	if !ok {
+		t.Fatalf("food %s not created in backend", out.FoodID)
+	}
+	if food.Name != "Organic Quinoa" || food.Brand == nil || *food.Brand != "Nature's Best" || !food.IsCustom {
+		t.Errorf("food = %+v", food)
+	}
+	if len(variants) != 1 || variants[0].ID != out.VariantID || variants[0].DietaryFiber != 7 || !variants[0].IsDefault {
+		t.Errorf("variants = %+v", variants)
+	}
+}
+
+func TestCreateFoodVariantValidation(t *testing.T) {
+	_, session := newTestSession(t)
+
+	msg := callToolError(t, session, "create_food_variant", map[string]any{
+		"name":         "Organic Quinoa",
+		"serving_size": 0,
+		"serving_unit": "g",
+		"calories":     368,
+		"protein":      14.1,
+		"carbs":        64.2,
+		"fat":          6.1,
+	})
+	if !strings.Contains(msg, "serving_size must be greater than 0") {
+		t.Errorf("error = %q", msg)
+	}
+}
+
+func TestCreateFoodVariantLabelCheck(t *testing.T) {
+	quinoa := map[string]any{
+		"name":         "Organic Quinoa",
+		"serving_size": 100,
+		"serving_unit": "g",
+		"calories":     368,
+		"protein":      14.1,
+		"carbs":        64.2,
+		"fat":          6.1,
+	}
+
+	tests := []struct {
+		name         string
+		args         map[string]any
+		wantErr      string
+		wantWarnings int
+	}{
+		{
+			name:         "kJ as kcal warns",
+			args:         map[string]any{"calories": 1540},
+			wantWarnings: 1,
+		},
+		{
+			name:    "kJ as kcal in strict mode",
+			args:    map[string]any{"calories": 1540, "strict": true},
+			wantErr: "looks like kJ",
+		},
+		{
+			name:    "fat components exceed fat",
+			args:    map[string]any{"saturated_fat": 5, "polyunsaturated_fat": 3},
+			wantErr: "more than the 6.1 g total fat",
+		},
+		{
+			name:    "negative value",
+			args:    map[string]any{"sodium": -5},
+			wantErr: "sodium: must not be negative",
+		},
+		{
+			name:    "macros exceed serving weight",
+			args:    map[string]any{"serving_size": 30},
+			wantErr: "more than the 30 g serving",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend, session := newTestSession(t)
+			args := map[string]any{}
+			for k, v := range quinoa {
+				args[k] = v
+			}
+			for k, v := range tt.args {
+				args[k] = v
+			}
+
+			if tt.wantErr != "" {
+				msg := callToolError(t, session, "create_food_variant", args)
+				if !strings.Contains(msg, tt.wantErr) {
+					t.Errorf("error = %q, should contain %q", msg, tt.wantErr)
+				}
+				if foods := backend.Foods(); len(foods) != 0 {
+					t.Errorf("backend foods = %+v, want nothing saved", foods)
+				}
+				return
+			}
+
+			out := callTool[CreateFoodOutput](t, session, "create_food_variant", args)
+			if len(out.Warnings) != tt.wantWarnings {
+				t.Errorf("Warnings = %v, want %d", out.Warnings, tt.wantWarnings)
+			}
+		})
+	}
+}
+
+func TestCreateFoodVariantEnergyKJ(t *testing.T) {
+	base := map[string]any{
+		"name":         "Muesli",
+		"serving_size": 100,
+		"serving_unit": "g",
+		"protein":      10,
+		"carbs":        60,
+		"fat":          7,
+	}
+
+	tests := []struct {
+		name            string
+		args            map[string]any
+		wantCalories    float64
+		wantConversions int
+		wantErr         string
+	}{
+		{
+			name:            "kJ only",
+			args:            map[string]any{"energy_kj": 1540},
+			wantCalories:    368.07,
+			wantConversions: 1,
+		},
+		{
+			name:            "kcal and kJ agree",
+			args:            map[string]any{"calories": 368, "energy_kj": 1540},
+			wantCalories:    368,
+			wantConversions: 1,
+		},
+		{
+			name:    "kcal and kJ swapped",
+			args:    map[string]any{"calories": 1540, "energy_kj": 368},
+			wantErr: "swapped",
+		},
+		{
+			name:    "no energy",
+			args:    map[string]any{},
+			wantErr: "calories or energy_kj is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend, session := newTestSession(t)
+			args := map[string]any{}
+			for k, v := range base {
+				args[k] = v
+			}
+			for k, v := range tt.args {
+				args[k] = v
+			}
+
+			if tt.wantErr != "" {
+				msg := callToolError(t, session, "create_food_variant", args)
+				if !strings.Contains(msg, tt.wantErr) {
+					t.Errorf("error = %q, should contain %q", msg, tt.wantErr)
+				}
+				return
+			}
+
+			out := callTool[CreateFoodOutput](t, session, "create_food_variant", args)
+			if len(out.Conversions) != tt.wantConversions {
+				t.Errorf("Conversions = %v, want %d", out.Conversions, tt.wantConversions)
+			}
+			_, variants, _ := backend.Food(out.FoodID)
+			if len(variants) != 1 || variants[0].Calories != tt.wantCalories {
+				t.Errorf("variants = %+v, want %v kcal", variants, tt.wantCalories)
+			}
+		})
+	}
+}
+
+func TestCreateFoodVariantCustomNutrients(t *testing.T) {
+	backend, session := newTestSession(t)
+	backend.AddCustomNutrient("Caffeine", "mg")
+	backend.AddCustomNutrient("Taurine", "mg")
+
+	out := callTool[CreateFoodOutput](t, session, "create_food_variant", map[string]any{
+		"name":         "Energy Drink",
+		"serving_size": 250,
+		"serving_unit": "ml",
+		"calories":     110,
+		"protein":      0,
+		"carbs":        27,
+		"fat":          0,
+		"custom_nutrients": []map[string]any{
+			{"name": "caffeine", "amount": 80},
+			{"name": "Taurine", "amount": 1, "unit": "g"},
+		},
+	})
+	if len(out.Conversions) != 1 || !strings.Contains(out.Conversions[0], "1000 mg") {
+		t.Errorf("Conversions = %v, want taurine g to mg", out.Conversions)
+	}
+
+	_, variants, _ := backend.Food(out.FoodID)
+	if got := variants[0].CustomNutrients; got["Caffeine"] != 80.0 || got["Taurine"] != 1000.0 {
+		t.Errorf("backend custom nutrients = %v", got)
+	}
+
+	// Both read tools return the amounts with their units
+	food := callTool[GetFoodOutput](t, session, "get_food", map[string]any{"food_id": out.FoodID})
+	want := []CustomNutrientResult{{Name: "Caffeine", Amount: 80, Unit: "mg"}, {Name: "Taurine", Amount: 1000, Unit: "mg"}}
+	if got := food.Variants[0].CustomNutrients; len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("get_food custom nutrients = %+v, want %+v", got, want)
+	}
+	search := callTool[SearchFoodsOutput](t, session, "search_foods", map[string]any{"name": "Energy"})
+	if len(search.Foods) != 1 || len(search.Foods[0].CustomNutrients) != 2 || search.Foods[0].CustomNutrients[0] != want[0] {
+		t.Errorf("search_foods results = %+v", search.Foods)
+	}
+
+	// Unknown nutrients are rejected with the configured names
+	msg := callToolError(t, session, "create_food_variant", map[string]any{
+		"name":             "Coffee",
+		"serving_size":     1,
+		"serving_unit":     "cup",
+		"calories":         2,
+		"protein":          0,
+		"carbs":            0,
+		"fat":              0,
+		"custom_nutrients": []map[string]any{{"name": "Theobromine", "amount": 5}},
+	})
+	if !strings.Contains(msg, "not one of the user's custom nutrients") || !strings.Contains(msg, "Caffeine, Taurine") {
+		t.Errorf("error = %q", msg)
+	}
+}
+
+func TestCreateFoodVariantAdditionalVariants(t *testing.T) {
+	args := func(additional ...map[string]any) map[string]any {
+		return map[string]any{
+			"name": "Muesli", "serving_size": 100, "serving_unit": "g",
+			"calories": 370, "protein": 10, "carbs": 60, "fat": 7,
+			"additional_variants": additional,
+		}
+	}
+	perServing := map[string]any{"serving_size": 30, "serving_unit": "g", "energy_kj": 465, "protein": 3, "carbs": 18, "fat": 2.1}
+
+	t.Run("creates every variant", func(t *testing.T) {
+		backend, session := newTestSession(t)
+
+		out := callTool[CreateFoodOutput](t, session, "create_food_variant", args(perServing))
+
+		if len(out.Variants) != 2 || out.Variants[0].VariantID != out.VariantID || !out.Variants[0].IsDefault ||
+			out.Variants[1].ServingSize != 30 || out.Variants[1].IsDefault {
+			t.Fatalf("Variants = %+v", out.Variants)
+		}
+		_, variants, _ := backend.Food(out.FoodID)
+		if len(variants) != 2 || variants[1].ID != out.Variants[1].VariantID || variants[1].Calories != 111.14 {
+			t.Errorf("backend variants = %+v", variants)
+		}
+		if len(out.Conversions) != 1 || !strings.HasPrefix(out.Conversions[0], "additional_variants[0]: ") {
+			t.Errorf("Conversions = %q", out.Conversions)
+		}
+	})
+
+	t.Run("invalid variant creates nothing", func(t *testing.T) {
+		backend, session := newTestSession(t)
+
+		bad := map[string]any{"serving_size": 30, "serving_unit": "g", "calories": 111, "protein": 3, "carbs": 18, "fat": 2.1, "sugars": 25}
+		msg := callToolError(t, session, "create_food_variant", args(bad))
+
+		if !strings.Contains(msg, "additional_variants[0]") || !strings.Contains(msg, "sugars") {
+			t.Errorf("error = %q", msg)
+		}
+		if got := len(backend.Foods()); got != 0 {
+			t.Errorf("foods = %d, want 0", got)
+		}
+	})
+
+	t.Run("repeated serving", func(t *testing.T) {
+		_, session := newTestSession(t)
+
+		repeated := map[string]any{"serving_size": 100, "serving_unit": "grams", "calories": 370, "protein": 10, "carbs": 60, "fat": 7}
+		msg := callToolError(t, session, "create_food_variant", args(repeated))
+
+		if !strings.Contains(msg, "given more than once") {
+			t.Errorf("error = %q", msg)
+		}
+	})
+
+	t.Run("backend failure rolls back", func(t *testing.T) {
+		backend, session := newTestSession(t)
+		backend.InjectFault(sparkyfitnesstest.Fault{Method: http.MethodPost, Path: "/foods/food-variants", Status: http.StatusInternalServerError, Body: `{"error":"db down"}`, Times: 1})
+
+		msg := callToolError(t, session, "create_food_variant", args(perServing))
+
+		if !strings.Contains(msg, "the new food was deleted again") {
+			t.Errorf("error = %q", msg)
+		}
+		if got := len(backend.Foods()); got != 0 {
+			t.Errorf("foods = %d, want the new food deleted", got)
+		}
+	})
+
+	t.Run("response without default variant rolls back", func(t *testing.T) {
+		backend, session := newTestSession(t)
+
+		// The food is created, but the response leaves out its variant
+		created := backend.AddFood(sparkyfitness.Food{Name: "Muesli"}, sparkyfitness.FoodVariant{ServingSize: 100, ServingUnit: "g"})
+		backend.InjectFault(sparkyfitnesstest.Fault{Method: http.MethodPost, Path: "/foods", Status: http.StatusCreated,
+			Body: fmt.Sprintf(`{"id":%q,"name":"Muesli"}`, created.ID), Times: 1})
+
+		msg := callToolError(t, session, "create_food_variant", args(perServing))
+
+		if !strings.Contains(msg, "did not include the default variant") || !strings.Contains(msg, "the new food was deleted again") {
+			t.Errorf("error = %q", msg)
+		}
+		if got := len(backend.Foods()); got != 0 {
+			t.Errorf("foods = %d, want the new food deleted", got)
+		}
+	})
+
+	t.Run("cancelled request rolls back", func(t *testing.T) {
+		backend := sparkyfitnesstest.NewServer(t)
+		backend.InjectFault(sparkyfitnesstest.Fault{Method: http.MethodPost, Path: "/foods/food-variants", Latency: 5 * time.Second, Times: 1})
+
+		// The request times out while the additional variant is being added
+		ctx, cancel := context.WithTimeout(t.Context(), 500*time.Millisecond)
+		defer cancel()
+		_, err := createFood(ctx, backend.NewClient(t), CreateFoodInput{
+			Name: "Muesli", ServingSize: 100, ServingUnit: "g",
+			Calories: ptr(370.0), Protein: 10, Carbs: 60, Fat: 7,
+			AdditionalVariants: []AdditionalVariantInput{{ServingSize: 30, ServingUnit: "g", Calories: ptr(111.0), Protein: 3, Carbs: 18, Fat: 2.1}},
+		})
+
+		if err == nil || !strings.Contains(err.Error(), "timed out") || !strings.Contains(err.Error(), "the new food was deleted again") {
+			t.Errorf("createFood() error = %v", err)
+		}
+		if got := len(backend.Foods()); got != 0 {
+			t.Errorf("foods = %d, want the new food deleted", got)
+		}
+	})
+}
@@ -0,0 +1,121 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// MealItemInput defines one food of a meal to create
+type MealItemInput struct {
+	FoodID    string   `json:"food_id,omitempty" jsonschema:"Food to include (from search_foods); its default variant is used unless variant_id is given"`
+	VariantID string   `json:"variant_id,omitempty" jsonschema:"Specific serving variant to include (from get_food)"`
+	Query     string   `json:"query,omitempty" jsonschema:"Food name to search for when food_id and variant_id are unknown"`
+	Quantity  *float64 `json:"quantity,omitempty" jsonschema:"Amount in the variant's serving unit (e.g., 150 for 150 g)"`
+	Servings  *float64 `json:"servings,omitempty" jsonschema:"Number of servings, alternative to quantity (default: 1)"`
+}
+
+// CreateMealInput defines the input parameters for the create_meal tool
+type CreateMealInput struct {
+	Name        string          `json:"name" jsonschema:"required,Name of the meal (e.g., 'Usual Breakfast')"`
+	Description *string         `json:"description,omitempty" jsonschema:"Optional description of the meal"`
+	Items       []MealItemInput `json:"items" jsonschema:"required,Foods in the meal with their amounts"`
+}
+
+// CreateMealOutput defines the output structure
+type CreateMealOutput struct {
+	Meal     MealResult `json:"meal" jsonschema:"The saved meal with its nutrition"`
+	Warnings []string   `json:"warnings,omitempty" jsonschema:"Problems computing the meal's nutrition; the meal is saved regardless"`
+	Message  string     `json:"message" jsonschema:"Success message"`
+}
+
+// RegisterCreateMeal registers the create_meal tool with the MCP server
+func (r *Registry) RegisterCreateMeal(server *mcp.Server, client *sparkyfitness.Client) error {
+	tool := &mcp.Tool{
+		Name:  "create_meal",
+		Title: "Save a Meal",
+		Description: "🥣 Save a reusable meal made of several foods and amounts.\n\n" +
+			"**When to Use:**\n" +
+			"• The user regularly eats the same combination (e.g., 'my usual breakfast')\n" +
+			"• Afterwards, log_meal logs the whole meal in one call\n\n" +
+			"**Required Input:**\n" +
+			"• name: meal name\n" +
+			"• items: each with variant_id, food_id or query, plus quantity or servings (default: 1 serving)\n\n" +
+			"**Output:**\n" +
+			"• meal_id and every item with its nutrients\n" +
+			"• totals: nutrition of the whole meal\n\n" +
+			"**Example:**\n" +
+			"User: 'Save my usual breakfast: 2 eggs and a slice of toast'\n" +
+			"→ create_meal(name='Usual Breakfast', items=[{query='egg', servings=2}, {query='toast'}])",
+	}
+
+	handler := func(ctx context.Context, request *mcp.CallToolRequest, input CreateMealInput) (*mcp.CallToolResult, CreateMealOutput, error) {
+		// Validate required parameters
+		if strings.TrimSpace(input.Name) == "" {
+			return nil, CreateMealOutput{}, fmt.Errorf("name parameter is required")
+		}
+		if len(input.Items) == 0 {
+			return nil, CreateMealOutput{}, fmt.Errorf("items must contain at least one food")
+		}
+
+		// Resolve every item to a variant and quantity, keeping the fetched
+		// variants for the summary
+		summarizer := newMealSummarizer(client)
+		req := &sparkyfitness.CreateMealRequest{
+			Name:        input.Name,
+			Description: input.Description,
+			Foods:       make([]sparkyfitness.MealFood, 0, len(input.Items)),
+		}
+		for i, item := range input.Items {
+			food, variant, err := resolveVariant(ctx, client, item.FoodID, item.VariantID, item.Query)
+			if err != nil {
+				return nil, CreateMealOutput{}, fmt.Errorf("items[%d]: %w", i, err)
+			}
+			quantity, err := resolveQuantity(item.Quantity, item.Servings, variant)
+			if err != nil {
+				return nil, CreateMealOutput{}, fmt.Errorf("items[%d]: %w", i, err)
+			}
+			summarizer.remember(food, variant)
+			req.Foods = append(req.Foods, sparkyfitness.MealFood{
+				FoodID:    food.ID,
+				VariantID: variant.ID,
+				Quantity:  quantity,
+				Unit:      variant.ServingUnit,
+				FoodName:  food.Name,
+			})
+		}
+
+		// Call backend API to save the meal
+		meal, err := client.CreateMeal(ctx, req)
+		if err != nil {
+			return nil, CreateMealOutput{}, backendError("create meal", err)
+		}
+
+		// Prepare output; the meal is saved, so a failing summary must not fail
+		// the call or the agent would retry and save a duplicate
+		result, err := summarizer.summarize(ctx, meal)
+		if err != nil {
+			output := CreateMealOutput{
+				Meal:     mealWithoutNutrition(meal),
+				Warnings: []string{fmt.Sprintf("nutrition could not be computed: %v", err)},
+				Message: fmt.Sprintf("Saved meal '%s' with %d foods; nutrition unavailable (see warnings)",
+					meal.Name, len(meal.Foods)),
+			}
+			return nil, output, nil
+		}
+
+		output := CreateMealOutput{
+			Meal: result,
+			Message: fmt.Sprintf("Saved meal '%s' with %d foods (%.0f kcal)",
+				meal.Name, len(result.Items), result.Totals.Calories),
+		}
+
+		return nil, output, nil
+	}
+
+	mcp.AddTool(server, tool, handler)
+	return nil
+}
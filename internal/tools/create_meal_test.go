@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+)
+
+func TestCreateMeal(t *testing.T) {
+	backend, session := newTestSession(t)
+	_, egg := seedDiary(backend)
+	toast := backend.AddFood(sparkyfitness.Food{Name: "Toast"},
+		sparkyfitness.FoodVariant{ServingSize: 1, ServingUnit: "slice", Nutrients: sparkyfitness.Nutrients{Calories: 80, Protein: 3, Carbs: 14, Fat: 1}},
+	)
+
+	out := callTool[CreateMealOutput](t, session, "create_meal", map[string]any{
+		"name": "Usual Breakfast",
+		"items": []map[string]any{
+			{"food_id": egg.ID, "servings": 2},
+			{"variant_id": toast.DefaultVariant.ID, "quantity": 1},
+		},
+	})
+
+	if out.Meal.MealID == "" || out.Meal.Name != "Usual Breakfast" || len(out.Meal.Items) != 2 {
+		t.Fatalf("output = %+v", out)
+	}
+	if item := out.Meal.Items[0]; item.FoodName != "Boiled Egg" || item.Quantity != 2 || item.Nutrients.Calories != 156 {
+		t.Errorf("items[0] = %+v", item)
+	}
+	if out.Meal.Totals.Calories != 236 || out.Meal.Totals.Protein != 15.6 {
+		t.Errorf("Totals = %+v, want 236 kcal, 15.6 g protein", out.Meal.Totals)
+	}
+	if meals := backend.Meals(); len(meals) != 1 || len(meals[0].Foods) != 2 {
+		t.Errorf("backend meals = %+v", meals)
+	}
+
+	// The summary reuses the variants resolved before saving
+	requests := backend.Requests()
+	if last := requests[len(requests)-1]; last.Method != http.MethodPost || last.Path != "/meals" {
+		t.Errorf("last request = %s %s, want POST /meals", last.Method, last.Path)
+	}
+}
+
+func TestCreateMealErrors(t *testing.T) {
+	_, session := newTestSession(t)
+
+	tests := []struct {
+		name         string
+		args         map[string]any
+		wantContains string
+	}{
+		{
+			name:         "no items",
+			args:         map[string]any{"name": "Empty", "items": []map[string]any{}},
+			wantContains: "at least one food",
+		},
+		{
+			name:         "unknown food",
+			args:         map[string]any{"name": "Bad", "items": []map[string]any{{"food_id": "missing"}}},
+			wantContains: "items[0]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := callToolError(t, session, "create_meal", tt.args)
+			if !strings.Contains(msg, tt.wantContains) {
+				t.Errorf("error = %q, should contain %q", msg, tt.wantContains)
+			}
+		})
+	}
+}
@@ -0,0 +1,333 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/nutrition"
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RecipeIngredientInput defines one ingredient of a recipe: either an
+// existing food (variant_id, food_id or query) or inline nutrition (name)
+type RecipeIngredientInput struct {
+	FoodID      string   `json:"food_id,omitempty" jsonschema:"Existing food to use (from search_foods); its default variant is used unless variant_id is given"`
+	VariantID   string   `json:"variant_id,omitempty" jsonschema:"Specific serving variant of an existing food (from get_food)"`
+	Query       string   `json:"query,omitempty" jsonschema:"Food name to search for when food_id and variant_id are unknown"`
+	Quantity    *float64 `json:"quantity,omitempty" jsonschema:"Amount of the existing food in the variant's serving unit (e.g., 150 for 150 g)"`
+	Servings    *float64 `json:"servings,omitempty" jsonschema:"Number of servings of the existing food, alternative to quantity (default: 1)"`
+	Name        string   `json:"name,omitempty" jsonschema:"Ingredient name for inline nutrition (instead of an existing food)"`
+	WeightGrams *float64 `json:"weight_grams,omitempty" jsonschema:"Weight of the inline ingredient in grams"`
+	Calories    float64  `json:"calories,omitempty" jsonschema:"Inline ingredient calories for the amount used"`
+	Protein     float64  `json:"protein,omitempty" jsonschema:"Inline ingredient protein in grams"`
+	Carbs       float64  `json:"carbs,omitempty" jsonschema:"Inline ingredient carbohydrates in grams"`
+	Fat         float64  `json:"fat,omitempty" jsonschema:"Inline ingredient fat in grams"`
+	OptionalNutrientsInput
+	CustomNutrients []CustomNutrientInput `json:"custom_nutrients,omitempty" jsonschema:"Inline ingredient amounts of the user's custom nutrients for the amount used"`
+}
+
+// CreateRecipeFoodInput defines the input parameters for the create_recipe_food tool
+type CreateRecipeFoodInput struct {
+	Name              string                  `json:"name" jsonschema:"required,Name of the dish (e.g., 'Chicken Curry')"`
+	Brand             *string                 `json:"brand,omitempty" jsonschema:"Brand name (optional, e.g., 'Homemade')"`
+	Ingredients       []RecipeIngredientInput `json:"ingredients" jsonschema:"required,Ingredients with the amounts used in the whole recipe"`
+	Servings          float64                 `json:"servings" jsonschema:"required,Number of servings the recipe makes"`
+	CookedWeightGrams *float64                `json:"cooked_weight_grams,omitempty" jsonschema:"Total weight of the finished dish in grams, used for the per-100 g variant"`
+}
+
+// RecipeIngredientResult reports an ingredient with its nutrient contribution
+type RecipeIngredientResult struct {
+	Name            string                 `json:"name" jsonschema:"Ingredient name"`
+	FoodID          string                 `json:"food_id,omitempty" jsonschema:"ID of the existing food, if any"`
+	VariantID       string                 `json:"variant_id,omitempty" jsonschema:"ID of the variant used, if any"`
+	Quantity        float64                `json:"quantity,omitempty" jsonschema:"Amount used in the variant's serving unit"`
+	Unit            string                 `json:"unit,omitempty" jsonschema:"Unit of the quantity"`
+	WeightGrams     *float64               `json:"weight_grams,omitempty" jsonschema:"Weight used in grams, if known"`
+	Nutrients       NutrientsResult        `json:"nutrients" jsonschema:"Nutrients this ingredient contributes to the recipe"`
+	CustomNutrients []CustomNutrientResult `json:"custom_nutrients,omitempty" jsonschema:"Custom nutrients this ingredient contributes to the recipe"`
+	Conversions     []string               `json:"conversions,omitempty" jsonschema:"Unit conversions applied to the ingredient's custom nutrients"`
+}
+
+// CreateRecipeFoodOutput defines the output structure
+type CreateRecipeFoodOutput struct {
+	FoodID           string                   `json:"food_id" jsonschema:"ID of the created food"`
+	FoodName         string                   `json:"food_name" jsonschema:"Name of the created food"`
+	Servings         float64                  `json:"servings" jsonschema:"Number of servings the recipe makes"`
+	TotalWeightGrams *float64                 `json:"total_weight_grams,omitempty" jsonschema:"Weight of the whole recipe used for the per-100 g variant"`
+	Ingredients      []RecipeIngredientResult `json:"ingredients" jsonschema:"Ingredients with their nutrient contributions"`
+	Totals           NutrientsResult          `json:"totals" jsonschema:"Nutrients of the whole recipe"`
+	Variants         []VariantResult          `json:"variants" jsonschema:"Created variants: 1 serving (default) and 100 g when the weight is known"`
+	Warnings         []string                 `json:"warnings,omitempty" jsonschema:"Label check warnings for the per-serving values; verify these with the user"`
+	Notes            []string                 `json:"notes,omitempty" jsonschema:"Remarks about the computation (e.g., why no per-100 g variant was created)"`
+	Message          string                   `json:"message" jsonschema:"Success message"`
+}
+
+// RegisterCreateRecipeFood registers the create_recipe_food tool with the MCP server
+func (r *Registry) RegisterCreateRecipeFood(server *mcp.Server, client *sparkyfitness.Client) error {
+	tool := &mcp.Tool{
+		Name:  "create_recipe_food",
+		Title: "Create Food from Recipe",
+		Description: "🍲 Create a NEW food for a home-cooked dish, computing its nutrition from the ingredients.\n\n" +
+			"**When to Use:**\n" +
+			"• The user cooked something and wants to log portions of it later\n" +
+			"• Use this instead of hand-computing totals for create_food_variant\n\n" +
+			"**Ingredients:**\n" +
+			"• Existing food: variant_id, food_id or query, plus quantity or servings (default: 1 serving)\n" +
+			"• Inline nutrition: name with calories, protein, carbs, fat (and optional and custom nutrients) for the amount used, plus weight_grams if known\n" +
+			"• Custom nutrients of every ingredient are added up too\n\n" +
+			"**Required Input:**\n" +
+			"• name: dish name\n" +
+			"• ingredients: amounts used in the whole recipe\n" +
+			"• servings: how many servings the recipe makes\n" +
+			"• cooked_weight_grams: weight of the finished dish (optional, recommended since cooking changes the weight)\n\n" +
+			"**Output:**\n" +
+			"• food_id of the new food\n" +
+			"• variants: '1 serving' (default) and '100 g' when the total weight is known\n" +
+			"• totals and each ingredient's contribution\n\n" +
+			"**Example:**\n" +
+			"User: 'I made 4 servings of curry with 500g chicken and 300g rice, 1.2 kg cooked'\n" +
+			"→ create_recipe_food(name='Chicken Curry', servings=4, cooked_weight_grams=1200, " +
+			"ingredients=[{query='chicken breast', quantity=500}, {query='rice', quantity=300}])",
+	}
+
+	handler := func(ctx context.Context, request *mcp.CallToolRequest, input CreateRecipeFoodInput) (*mcp.CallToolResult, CreateRecipeFoodOutput, error) {
+		// Validate required parameters
+		if strings.TrimSpace(input.Name) == "" {
+			return nil, CreateRecipeFoodOutput{}, fmt.Errorf("name parameter is required")
+		}
+		if len(input.Ingredients) == 0 {
+			return nil, CreateRecipeFoodOutput{}, fmt.Errorf("ingredients must contain at least one ingredient")
+		}
+		if input.Servings <= 0 {
+			return nil, CreateRecipeFoodOutput{}, fmt.Errorf("servings must be greater than 0")
+		}
+		if input.CookedWeightGrams != nil && *input.CookedWeightGrams <= 0 {
+			return nil, CreateRecipeFoodOutput{}, fmt.Errorf("cooked_weight_grams must be greater than 0")
+		}
+
+		// Resolve every ingredient and sum up the recipe
+		output := CreateRecipeFoodOutput{
+			Servings:    input.Servings,
+			Ingredients: make([]RecipeIngredientResult, 0, len(input.Ingredients)),
+		}
+		var totals sparkyfitness.Nutrients
+		var totalCustom map[string]interface{}
+		var rawWeight float64
+		var unweighed []string
+		for i, ingredient := range input.Ingredients {
+			result, nutrients, custom, err := resolveIngredient(ctx, client, ingredient)
+			if err != nil {
+				return nil, CreateRecipeFoodOutput{}, fmt.Errorf("ingredients[%d]: %w", i, err)
+			}
+			totals = totals.Add(nutrients)
+			totalCustom = addCustomNutrients(totalCustom, custom, 1)
+			if result.WeightGrams != nil {
+				rawWeight += *result.WeightGrams
+			} else {
+				unweighed = append(unweighed, result.Name)
+			}
+			output.Ingredients = append(output.Ingredients, result)
+		}
+		output.Totals = convertNutrientsToResult(totals)
+
+		// The per-100 g variant needs the weight of the whole dish
+		switch {
+		case input.CookedWeightGrams != nil:
+			output.TotalWeightGrams = input.CookedWeightGrams
+		case len(unweighed) == 0:
+			output.TotalWeightGrams = &rawWeight
+			output.Notes = append(output.Notes, "Per-100 g values use the raw ingredient weight; "+
+				"pass cooked_weight_grams if cooking changed the weight")
+		default:
+			output.Notes = append(output.Notes, fmt.Sprintf("No 100 g variant created: the weight of %s is unknown; "+
+				"pass cooked_weight_grams to add one", strings.Join(unweighed, ", ")))
+		}
+
+		// Check the per-serving label before writing
+		perServing := totals.Scale(1 / input.Servings).Map(round2)
+		perServingCustom := roundCustomNutrients(addCustomNutrients(nil, totalCustom, 1/input.Servings))
+		if perServingCustom == nil {
+			perServingCustom = make(map[string]interface{})
+		}
+		warnings, err := checkLabel(sparkyfitness.FoodVariant{
+			ServingSize: 1,
+			ServingUnit: "serving",
+			Nutrients:   perServing,
+		}, false)
+		if err != nil {
+			return nil, CreateRecipeFoodOutput{}, err
+		}
+		output.Warnings = warnings
+
+		// Call backend API to create the food with the per-serving default variant
+		req := &sparkyfitness.CreateFoodRequest{
+			Name:            input.Name,
+			Brand:           "",
+			IsCustom:        true, // MCP-created foods are always custom
+			IsQuickFood:     false,
+			ServingSize:     1,
+			ServingUnit:     "serving",
+			Nutrients:       perServing,
+			IsDefault:       true,
+			GlycemicIndex:   unknownGlycemicIndex,
+			CustomNutrients: perServingCustom,
+		}
+		if input.Brand != nil {
+			req.Brand = *input.Brand
+		}
+
+		resp, err := client.CreateFood(ctx, req)
+		if err != nil {
+			return nil, CreateRecipeFoodOutput{}, backendError("create food", err)
+		}
+		if resp.DefaultVariant == nil {
+			return nil, CreateRecipeFoodOutput{}, fmt.Errorf("failed to create food: backend response did not include the default variant (food ID: %s)", resp.ID)
+		}
+		output.FoodID = resp.ID
+		output.FoodName = resp.Name
+		output.Variants = append(output.Variants, convertVariantToResult(sparkyfitness.FoodVariant{
+			ID:              resp.DefaultVariant.ID,
+			FoodID:          resp.ID,
+			ServingSize:     1,
+			ServingUnit:     "serving",
+			Nutrients:       perServing,
+			IsDefault:       true,
+			CustomNutrients: perServingCustom,
+		}))
+
+		// Call backend API to add the per-100 g variant
+		if output.TotalWeightGrams != nil {
+			per100g := totals.Scale(100 / *output.TotalWeightGrams).Map(round2)
+			per100gCustom := roundCustomNutrients(addCustomNutrients(nil, totalCustom, 100 / *output.TotalWeightGrams))
+			added, err := client.AddFoodVariant(ctx, &sparkyfitness.AddFoodVariantRequest{
+				FoodID:          resp.ID,
+				ServingSize:     100,
+				ServingUnit:     "g",
+				Nutrients:       per100g,
+				CustomNutrients: per100gCustom,
+			})
+			if err != nil {
+				// Delete the new food again so no half-created food is left behind
+				err = backendError("add the 100 g variant", err)
+				if rollbackErr := rollbackFood(ctx, client, resp.ID); rollbackErr != nil {
+					return nil, CreateRecipeFoodOutput{}, fmt.Errorf("%w; removing the partially created food also failed (%v), "+
+						"so food_id %s is left without its 100 g variant: delete it with delete_food or add the variant with add_food_variant",
+						err, rollbackErr, resp.ID)
+				}
+				return nil, CreateRecipeFoodOutput{}, fmt.Errorf("%w; the new food was deleted again, so nothing was created", err)
+			}
+			output.Variants = append(output.Variants, convertVariantToResult(sparkyfitness.FoodVariant{
+				ID:              added.ID,
+				FoodID:          resp.ID,
+				ServingSize:     100,
+				ServingUnit:     "g",
+				Nutrients:       per100g,
+				CustomNutrients: per100gCustom,
+			}))
+		}
+
+		// Add the units of custom nutrients
+		if units := customNutrientUnits(ctx, client, totalCustom); units != nil {
+			for i := range output.Ingredients {
+				setCustomNutrientUnits(output.Ingredients[i].CustomNutrients, units)
+			}
+			for i := range output.Variants {
+				setCustomNutrientUnits(output.Variants[i].CustomNutrients, units)
+			}
+		}
+
+		// Prepare output
+		output.Message = fmt.Sprintf("Successfully created '%s' from %d ingredients: %g servings of %.0f kcal each",
+			resp.Name, len(output.Ingredients), input.Servings, perServing.Calories)
+
+		return nil, output, nil
+	}
+
+	mcp.AddTool(server, tool, handler)
+	return nil
+}
+
+// resolveIngredient computes a recipe ingredient's nutrients and custom
+// nutrients, either from an existing food variant or from inline nutrition
+func resolveIngredient(ctx context.Context, client *sparkyfitness.Client, input RecipeIngredientInput) (RecipeIngredientResult, sparkyfitness.Nutrients, map[string]interface{}, error) {
+	existing := input.FoodID != "" || input.VariantID != "" || input.Query != ""
+
+	switch {
+	case existing && input.Name != "":
+		return RecipeIngredientResult{}, sparkyfitness.Nutrients{}, nil, fmt.Errorf("provide either an existing food (variant_id, food_id or query) or inline nutrition (name), not both")
+	case input.Name != "":
+		if input.Quantity != nil || input.Servings != nil {
+			return RecipeIngredientResult{}, sparkyfitness.Nutrients{}, nil, fmt.Errorf("quantity and servings only apply to existing foods; give inline nutrition for the amount used")
+		}
+		if input.WeightGrams != nil && *input.WeightGrams <= 0 {
+			return RecipeIngredientResult{}, sparkyfitness.Nutrients{}, nil, fmt.Errorf("weight_grams must be greater than 0")
+		}
+		nutrients := sparkyfitness.Nutrients{
+			Calories: input.Calories,
+			Protein:  input.Protein,
+			Carbs:    input.Carbs,
+			Fat:      input.Fat,
+		}
+		input.OptionalNutrientsInput.applyTo(&nutrients)
+
+		// Reject negative and impossible inline values; the recipe as a whole
+		// gets the full label check
+		label := sparkyfitness.FoodVariant{Nutrients: nutrients}
+		if input.WeightGrams != nil {
+			label.ServingSize, label.ServingUnit = *input.WeightGrams, "g"
+		}
+		if _, err := checkLabel(label, false); err != nil {
+			return RecipeIngredientResult{}, sparkyfitness.Nutrients{}, nil, err
+		}
+
+		custom, conversions, err := resolveCustomNutrients(ctx, client, input.CustomNutrients, nil)
+		if err != nil {
+			return RecipeIngredientResult{}, sparkyfitness.Nutrients{}, nil, err
+		}
+
+		return RecipeIngredientResult{
+			Name:            input.Name,
+			WeightGrams:     input.WeightGrams,
+			Nutrients:       convertNutrientsToResult(nutrients),
+			CustomNutrients: convertCustomNutrientsToResult(custom),
+			Conversions:     conversions,
+		}, nutrients, custom, nil
+	case !existing:
+		return RecipeIngredientResult{}, sparkyfitness.Nutrients{}, nil, fmt.Errorf("provide variant_id, food_id or query for an existing food, or name with inline nutrition")
+	}
+
+	if len(input.CustomNutrients) > 0 {
+		return RecipeIngredientResult{}, sparkyfitness.Nutrients{}, nil, fmt.Errorf("custom_nutrients only apply to inline nutrition; existing foods contribute their own")
+	}
+
+	food, variant, err := resolveVariant(ctx, client, input.FoodID, input.VariantID, input.Query)
+	if err != nil {
+		return RecipeIngredientResult{}, sparkyfitness.Nutrients{}, nil, err
+	}
+	quantity, err := resolveQuantity(input.Quantity, input.Servings, variant)
+	if err != nil {
+		return RecipeIngredientResult{}, sparkyfitness.Nutrients{}, nil, err
+	}
+
+	nutrients := variantNutrients(variant, quantity)
+	var custom map[string]interface{}
+	if variant.ServingSize > 0 {
+		custom = addCustomNutrients(nil, variant.CustomNutrients, quantity/variant.ServingSize)
+	}
+	result := RecipeIngredientResult{
+		Name:            food.Name,
+		FoodID:          food.ID,
+		VariantID:       variant.ID,
+		Quantity:        quantity,
+		Unit:            variant.ServingUnit,
+		Nutrients:       convertNutrientsToResult(nutrients),
+		CustomNutrients: convertCustomNutrientsToResult(roundCustomNutrients(custom)),
+	}
+	if grams, err := nutrition.Convert(quantity, variant.ServingUnit, "g", 0); err == nil {
+		result.WeightGrams = &grams
+	}
+
+	return result, nutrients, custom, nil
+}
@@ -0,0 +1,201 @@
+package tools
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness/sparkyfitnesstest"
+)
+
+func TestCreateRecipeFood(t *testing.T) {
+	tests := []struct {
+		name             string
+		cookedWeight     any
+		withEgg          bool
+		wantVariants     int
+		wantPer100gKcal  float64
+		wantNoteContains string
+	}{
+		{
+			name:            "cooked weight",
+			cookedWeight:    600,
+			withEgg:         true,
+			wantVariants:    2,
+			wantPer100gKcal: 106,
+		},
+		{
+			name:             "raw weight of weighed ingredients",
+			wantVariants:     2,
+			wantPer100gKcal:  154.84,
+			wantNoteContains: "raw ingredient weight",
+		},
+		{
+			name:             "unknown weight",
+			withEgg:          true,
+			wantVariants:     1,
+			wantNoteContains: "Boiled Egg",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend, session := newTestSession(t)
+			rice, egg := seedDiary(backend)
+
+			ingredients := []map[string]any{
+				{"food_id": rice.ID, "quantity": 300},
+				{"name": "Olive Oil", "weight_grams": 10, "calories": 90, "fat": 10},
+			}
+			if tt.withEgg {
+				ingredients = append(ingredients, map[string]any{"variant_id": egg.DefaultVariant.ID, "servings": 2})
+			}
+			args := map[string]any{"name": "Fried Rice", "servings": 2, "ingredients": ingredients}
+			if tt.cookedWeight != nil {
+				args["cooked_weight_grams"] = tt.cookedWeight
+			}
+
+			out := callTool[CreateRecipeFoodOutput](t, session, "create_recipe_food", args)
+
+			wantTotal := 480.0
+			if tt.withEgg {
+				wantTotal = 636
+			}
+			if out.Totals.Calories != wantTotal {
+				t.Errorf("Totals.Calories = %v, want %v", out.Totals.Calories, wantTotal)
+			}
+			if len(out.Variants) != tt.wantVariants {
+				t.Fatalf("len(Variants) = %d, want %d", len(out.Variants), tt.wantVariants)
+			}
+			if v := out.Variants[0]; !v.IsDefault || v.ServingUnit != "serving" || v.Calories != wantTotal/2 {
+				t.Errorf("Variants[0] = %+v, want default 1 serving of %v kcal", v, wantTotal/2)
+			}
+			if tt.wantVariants == 2 {
+				if v := out.Variants[1]; v.ServingSize != 100 || v.ServingUnit != "g" || v.Calories != tt.wantPer100gKcal {
+					t.Errorf("Variants[1] = %+v, want 100 g of %v kcal", v, tt.wantPer100gKcal)
+				}
+			}
+			if tt.wantNoteContains != "" && !strings.Contains(strings.Join(out.Notes, " "), tt.wantNoteContains) {
+				t.Errorf("Notes = %v, should contain %q", out.Notes, tt.wantNoteContains)
+			}
+
+			food, variants, ok := backend.Food(out.FoodID)
+			if !ok || food.Name != "Fried Rice" || len(variants) != tt.wantVariants {
+				t.Errorf("backend food = %+v with %d variants", food, len(variants))
+			}
+		})
+	}
+}
+
+func TestCreateRecipeFoodCustomNutrients(t *testing.T) {
+	backend, session := newTestSession(t)
+	backend.AddCustomNutrient("Caffeine", "mg")
+	coffee := backend.AddFood(sparkyfitness.Food{Name: "Coffee"},
+		sparkyfitness.FoodVariant{ServingSize: 1, ServingUnit: "cup", Nutrients: sparkyfitness.Nutrients{Calories: 2},
+			CustomNutrients: map[string]interface{}{"Caffeine": 95.0}},
+	)
+
+	out := callTool[CreateRecipeFoodOutput](t, session, "create_recipe_food", map[string]any{
+		"name":     "Iced Coffee",
+		"servings": 2,
+		"ingredients": []map[string]any{
+			{"variant_id": coffee.DefaultVariant.ID, "servings": 2},
+			{"name": "Espresso Shot", "calories": 1, "custom_nutrients": []map[string]any{{"name": "caffeine", "amount": 0.063, "unit": "g"}}},
+		},
+	})
+
+	// 2 cups (190 mg) + 63 mg, over 2 servings
+	want := []CustomNutrientResult{{Name: "Caffeine", Amount: 126.5, Unit: "mg"}}
+	if got := out.Variants[0].CustomNutrients; !reflect.DeepEqual(got, want) {
+		t.Errorf("Variants[0].CustomNutrients = %+v, want %+v", got, want)
+	}
+	if got := out.Ingredients[1]; len(got.CustomNutrients) != 1 || got.CustomNutrients[0].Amount != 63 || len(got.Conversions) != 1 {
+		t.Errorf("Ingredients[1] = %+v, want 63 mg caffeine converted from g", got)
+	}
+	_, variants, _ := backend.Food(out.FoodID)
+	if got := variants[0].CustomNutrients["Caffeine"]; got != 126.5 {
+		t.Errorf("stored caffeine = %v, want 126.5", got)
+	}
+}
+
+func TestCreateRecipeFoodLabelWarnings(t *testing.T) {
+	_, session := newTestSession(t)
+
+	out := callTool[CreateRecipeFoodOutput](t, session, "create_recipe_food", map[string]any{
+		"name":        "Mystery Stew",
+		"servings":    1,
+		"ingredients": []map[string]any{{"name": "Stock", "calories": 1000, "protein": 1}},
+	})
+
+	if !strings.Contains(strings.Join(out.Warnings, " "), "calories") {
+		t.Errorf("Warnings = %v, want the per-serving calories flagged", out.Warnings)
+	}
+}
+
+func TestCreateRecipeFoodRollback(t *testing.T) {
+	backend, session := newTestSession(t)
+	rice, _ := seedDiary(backend)
+	backend.InjectFault(sparkyfitnesstest.Fault{Method: http.MethodPost, Path: "/foods/food-variants", Status: http.StatusBadRequest, Body: `{"error":"invalid serving"}`})
+
+	msg := callToolError(t, session, "create_recipe_food", map[string]any{
+		"name":        "Plain Rice Bowl",
+		"servings":    2,
+		"ingredients": []map[string]any{{"food_id": rice.ID, "quantity": 300}},
+	})
+
+	if !strings.Contains(msg, "100 g variant") || !strings.Contains(msg, "deleted again") {
+		t.Errorf("error = %q, want the food rolled back", msg)
+	}
+	for _, food := range backend.Foods() {
+		if food.Name == "Plain Rice Bowl" {
+			t.Errorf("recipe food %s left behind", food.ID)
+		}
+	}
+}
+
+func TestCreateRecipeFoodErrors(t *testing.T) {
+	_, session := newTestSession(t)
+
+	tests := []struct {
+		name         string
+		args         map[string]any
+		wantContains string
+	}{
+		{
+			name:         "no servings",
+			args:         map[string]any{"name": "Soup", "servings": 0, "ingredients": []map[string]any{{"name": "Water"}}},
+			wantContains: "servings must be greater than 0",
+		},
+		{
+			name:         "both modes",
+			args:         map[string]any{"name": "Soup", "servings": 1, "ingredients": []map[string]any{{"name": "Water", "food_id": "x"}}},
+			wantContains: "not both",
+		},
+		{
+			name:         "negative inline nutrient",
+			args:         map[string]any{"name": "Soup", "servings": 1, "ingredients": []map[string]any{{"name": "Water", "protein": -5}, {"name": "Beans", "protein": 10}}},
+			wantContains: "protein: must not be negative",
+		},
+		{
+			name:         "custom nutrients on existing food",
+			args:         map[string]any{"name": "Soup", "servings": 1, "ingredients": []map[string]any{{"food_id": "x", "custom_nutrients": []map[string]any{{"name": "Caffeine", "amount": 1}}}}},
+			wantContains: "custom_nutrients only apply to inline nutrition",
+		},
+		{
+			name:         "neither mode",
+			args:         map[string]any{"name": "Soup", "servings": 1, "ingredients": []map[string]any{{"quantity": 100}}},
+			wantContains: "ingredients[0]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := callToolError(t, session, "create_recipe_food", tt.args)
+			if !strings.Contains(msg, tt.wantContains) {
+				t.Errorf("error = %q, should contain %q", msg, tt.wantContains)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/nutrition"
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+)
+
+// CustomNutrientInput defines the amount of one of the user's custom nutrients
+type CustomNutrientInput struct {
+	Name   string  `json:"name" jsonschema:"required,Name of a custom nutrient configured in SparkyFitness (e.g., 'Caffeine')"`
+	Amount float64 `json:"amount" jsonschema:"required,Amount per serving"`
+	Unit   string  `json:"unit,omitempty" jsonschema:"Unit of the amount (default: the nutrient's configured unit); mass units are converted"`
+}
+
+// CustomNutrientResult reports the amount of a custom nutrient
+type CustomNutrientResult struct {
+	Name   string  `json:"name" jsonschema:"Name of the custom nutrient"`
+	Amount float64 `json:"amount" jsonschema:"Amount per serving"`
+	Unit   string  `json:"unit,omitempty" jsonschema:"Unit of the amount"`
+}
+
+// resolveCustomNutrients validates custom nutrient inputs against the user's
+// custom nutrient definitions and returns base with the amounts set, keyed by
+// the configured names and converted to the configured units. base is not
+// modified. It also returns a description of each unit conversion.
+func resolveCustomNutrients(ctx context.Context, client *sparkyfitness.Client, inputs []CustomNutrientInput, base map[string]interface{}) (map[string]interface{}, []string, error) {
+	if len(inputs) == 0 {
+		return base, nil, nil
+	}
+
+	definitions, err := client.ListCustomNutrients(ctx)
+	if err != nil {
+		return nil, nil, backendError("list custom nutrients", err)
+	}
+
+	result := maps.Clone(base)
+	if result == nil {
+		result = map[string]interface{}{}
+	}
+
+	var conversions []string
+	seen := map[string]bool{}
+	for i, input := range inputs {
+		def, ok := findCustomNutrient(definitions, input.Name)
+		if !ok {
+			return nil, nil, fmt.Errorf("custom_nutrients[%d]: '%s' is not one of the user's custom nutrients (%s); "+
+				"ask the user to add it in SparkyFitness first", i, input.Name, customNutrientNames(definitions))
+		}
+		if seen[def.Name] {
+			return nil, nil, fmt.Errorf("custom_nutrients[%d]: '%s' is given more than once", i, def.Name)
+		}
+		seen[def.Name] = true
+
+		if input.Amount < 0 {
+			return nil, nil, fmt.Errorf("custom_nutrients[%d]: amount must not be negative", i)
+		}
+
+		amount := input.Amount
+		if input.Unit != "" && def.Unit != "" && !nutrition.SameUnit(input.Unit, def.Unit) {
+			amount, err = nutrition.Convert(input.Amount, input.Unit, def.Unit, 0)
+			if err != nil {
+				return nil, nil, fmt.Errorf("custom_nutrients[%d]: '%s' is tracked in %s: %w", i, def.Name, def.Unit, err)
+			}
+			amount = round2(amount)
+			conversions = append(conversions, fmt.Sprintf("%s: converted %g %s to %g %s",
+				def.Name, input.Amount, input.Unit, amount, def.Unit))
+		}
+
+		result[def.Name] = amount
+	}
+
+	return result, conversions, nil
+}
+
+// findCustomNutrient looks up a custom nutrient definition by name,
+// ignoring case and surrounding whitespace
+func findCustomNutrient(definitions []sparkyfitness.CustomNutrient, name string) (sparkyfitness.CustomNutrient, bool) {
+	for _, def := range definitions {
+		if strings.EqualFold(strings.TrimSpace(def.Name), strings.TrimSpace(name)) {
+			return def, true
+		}
+	}
+	return sparkyfitness.CustomNutrient{}, false
+}
+
+// customNutrientNames lists the defined custom nutrients for error messages
+func customNutrientNames(definitions []sparkyfitness.CustomNutrient) string {
+	if len(definitions) == 0 {
+		return "none are configured"
+	}
+	names := make([]string, 0, len(definitions))
+	for _, def := range definitions {
+		names = append(names, def.Name)
+	}
+	return "configured: " + strings.Join(names, ", ")
+}
+
+// roundCustomNutrients returns custom with the numeric amounts rounded to
+// two decimals, like the standard nutrients of derived variants
+func roundCustomNutrients(custom map[string]interface{}) map[string]interface{} {
+	if custom == nil {
+		return nil
+	}
+	rounded := make(map[string]interface{}, len(custom))
+	for name, value := range custom {
+		if amount, ok := value.(float64); ok {
+			value = round2(amount)
+		}
+		rounded[name] = value
+	}
+	return rounded
+}
+
+// addCustomNutrients adds the numeric amounts of src, multiplied by factor,
+// to dst by name and returns dst, allocating it when needed. Amounts are
+// stored in each nutrient's configured unit, so equal names share a unit.
+// Non-numeric values are skipped.
+func addCustomNutrients(dst, src map[string]interface{}, factor float64) map[string]interface{} {
+	for name, value := range src {
+		amount, ok := value.(float64)
+		if !ok {
+			continue
+		}
+		if dst == nil {
+			dst = map[string]interface{}{}
+		}
+		current, _ := dst[name].(float64)
+		dst[name] = current + amount*factor
+	}
+	return dst
+}
+
+// convertCustomNutrientsToResult converts a variant's custom nutrient
+// amounts to the tool result format, sorted by name. Units are not stored
+// with the amounts; see setCustomNutrientUnits. Non-numeric values are skipped.
+func convertCustomNutrientsToResult(custom map[string]interface{}) []CustomNutrientResult {
+	var results []CustomNutrientResult
+	for _, name := range slices.Sorted(maps.Keys(custom)) {
+		amount, ok := custom[name].(float64)
+		if !ok {
+			continue
+		}
+		results = append(results, CustomNutrientResult{Name: name, Amount: amount})
+	}
+	return results
+}
+
+// customNutrientUnits returns the configured unit of each custom nutrient,
+// keyed by name, for results containing the given custom nutrient amounts.
+// The lookup is best effort: when no amounts are present nothing is fetched,
+// and a failed fetch only leaves the units out.
+func customNutrientUnits(ctx context.Context, client *sparkyfitness.Client, custom ...map[string]interface{}) map[string]string {
+	if !slices.ContainsFunc(custom, func(m map[string]interface{}) bool { return len(m) > 0 }) {
+		return nil
+	}
+
+	definitions, err := client.ListCustomNutrients(ctx)
+	if err != nil {
+		return nil
+	}
+	units := make(map[string]string, len(definitions))
+	for _, def := range definitions {
+		units[def.Name] = def.Unit
+	}
+	return units
+}
+
+// setCustomNutrientUnits fills in the units of custom nutrient results
+func setCustomNutrientUnits(results []CustomNutrientResult, units map[string]string) {
+	for i := range results {
+		results[i].Unit = units[results[i].Name]
+	}
+}
@@ -0,0 +1,99 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// DeleteFoodInput defines the input parameters for the delete_food tool
+type DeleteFoodInput struct {
+	FoodID string `json:"food_id" jsonschema:"required,Unique identifier of the food to delete (from search_foods results)"`
+}
+
+// DeleteFoodOutput defines the output structure
+type DeleteFoodOutput struct {
+	FoodID          string `json:"food_id" jsonschema:"ID of the food"`
+	FoodName        string `json:"food_name" jsonschema:"Name of the food"`
+	Deleted         bool   `json:"deleted" jsonschema:"Whether the food was deleted (false if the user did not confirm)"`
+	DeletedVariants int    `json:"deleted_variants" jsonschema:"Number of variants deleted with the food"`
+	Message         string `json:"message" jsonschema:"Result message"`
+}
+
+// RegisterDeleteFood registers the delete_food tool with the MCP server
+func (r *Registry) RegisterDeleteFood(server *mcp.Server, client *sparkyfitness.Client) error {
+	tool := &mcp.Tool{
+		Name:  "delete_food",
+		Title: "Delete Food",
+		Description: "🗑️ Permanently delete a food and ALL of its variants.\n\n" +
+			"**When to Use:**\n" +
+			"• A food was created by mistake or is a duplicate the user wants removed\n" +
+			"• To remove a single serving size instead, use delete_food_variant\n\n" +
+			"**Confirmation:**\n" +
+			"If the client supports it, the user is asked to confirm before anything is deleted. " +
+			"Always confirm with the user in chat first otherwise.\n\n" +
+			"**Required Input:**\n" +
+			"• food_id: UUID from search_foods results\n\n" +
+			"**Output:**\n" +
+			"• deleted: true if the food was deleted, false if the user declined\n" +
+			"• deleted_variants: how many variants were removed with it",
+		Annotations: &mcp.ToolAnnotations{
+			DestructiveHint: ptr(true),
+		},
+	}
+
+	handler := func(ctx context.Context, request *mcp.CallToolRequest, input DeleteFoodInput) (*mcp.CallToolResult, DeleteFoodOutput, error) {
+		// Validate required parameters
+		if input.FoodID == "" {
+			return nil, DeleteFoodOutput{}, fmt.Errorf("food_id parameter is required")
+		}
+
+		// Fetch food and variants to describe what will be deleted
+		food, err := client.GetFood(ctx, input.FoodID)
+		if err != nil {
+			return nil, DeleteFoodOutput{}, backendError("get food", err)
+		}
+		variants, err := client.ListFoodVariants(ctx, input.FoodID)
+		if err != nil {
+			return nil, DeleteFoodOutput{}, backendError("list food variants", err)
+		}
+
+		output := DeleteFoodOutput{
+			FoodID:   food.ID,
+			FoodName: food.Name,
+		}
+
+		// Ask the user before deleting
+		confirmed, err := confirmAction(ctx, request, fmt.Sprintf(
+			"Delete food '%s' and its %d variant(s)? This cannot be undone.", food.Name, len(variants)))
+		if err != nil {
+			return nil, DeleteFoodOutput{}, err
+		}
+		if !confirmed {
+			output.Message = fmt.Sprintf("Food '%s' was not deleted: the user did not confirm", food.Name)
+			return nil, output, nil
+		}
+
+		// Call backend API to delete food
+		if err := client.DeleteFood(ctx, input.FoodID); err != nil {
+			return nil, DeleteFoodOutput{}, backendError("delete food", err)
+		}
+
+		// Prepare output
+		output.Deleted = true
+		output.DeletedVariants = len(variants)
+		output.Message = fmt.Sprintf("Successfully deleted food '%s' and %d variant(s)", food.Name, len(variants))
+
+		return nil, output, nil
+	}
+
+	mcp.AddTool(server, tool, handler)
+	return nil
+}
+
+// ptr returns a pointer to v, for optional fields such as tool annotation hints
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,73 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// DeleteFoodEntryInput defines the input parameters for the delete_food_entry tool
+type DeleteFoodEntryInput struct {
+	EntryID string `json:"entry_id" jsonschema:"required,Unique identifier of the diary entry (from get_food_diary or log_food_entry)"`
+}
+
+// DeleteFoodEntryOutput defines the output structure
+type DeleteFoodEntryOutput struct {
+	Entry   DiaryEntryResult `json:"entry" jsonschema:"The deleted diary entry"`
+	Delta   NutrientsResult  `json:"delta" jsonschema:"Change in logged nutrients (the entry's nutrients, negated)"`
+	Message string           `json:"message" jsonschema:"Success message"`
+}
+
+// RegisterDeleteFoodEntry registers the delete_food_entry tool with the MCP server
+func (r *Registry) RegisterDeleteFoodEntry(server *mcp.Server, client *sparkyfitness.Client) error {
+	tool := &mcp.Tool{
+		Name:  "delete_food_entry",
+		Title: "Delete Food Diary Entry",
+		Description: "🗑️ Remove a logged entry from the food diary.\n\n" +
+			"**When to Use:**\n" +
+			"• 'I didn't actually eat that' or an entry was logged twice\n" +
+			"• To change an entry's amount or meal, use update_food_entry instead\n\n" +
+			"**Required Input:**\n" +
+			"• entry_id: from get_food_diary or log_food_entry\n\n" +
+			"**Output:**\n" +
+			"• entry: what was deleted\n" +
+			"• delta: the nutrients removed from the day's totals (negative values)",
+		Annotations: &mcp.ToolAnnotations{
+			DestructiveHint: ptr(true),
+		},
+	}
+
+	handler := func(ctx context.Context, request *mcp.CallToolRequest, input DeleteFoodEntryInput) (*mcp.CallToolResult, DeleteFoodEntryOutput, error) {
+		// Validate required parameters
+		if input.EntryID == "" {
+			return nil, DeleteFoodEntryOutput{}, fmt.Errorf("entry_id parameter is required")
+		}
+
+		// Fetch entry to report what is removed
+		entry, err := client.GetFoodEntry(ctx, input.EntryID)
+		if err != nil {
+			return nil, DeleteFoodEntryOutput{}, backendError("get food entry", err)
+		}
+
+		// Call backend API to delete entry
+		if err := client.DeleteFoodEntry(ctx, input.EntryID); err != nil {
+			return nil, DeleteFoodEntryOutput{}, backendError("delete food entry", err)
+		}
+
+		// Prepare output
+		delta := entry.LoggedNutrients().Scale(-1)
+		output := DeleteFoodEntryOutput{
+			Entry: convertEntryToResult(*entry),
+			Delta: convertNutrientsToResult(delta),
+			Message: fmt.Sprintf("Deleted %g %s of %s from %s on %s (%.0f kcal)",
+				entry.Quantity, entry.Unit, entry.FoodName, entry.MealType, entry.EntryDate, delta.Calories),
+		}
+
+		return nil, output, nil
+	}
+
+	mcp.AddTool(server, tool, handler)
+	return nil
+}
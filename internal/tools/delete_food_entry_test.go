@@ -0,0 +1,33 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+)
+
+func TestDeleteFoodEntry(t *testing.T) {
+	backend, session := newTestSession(t)
+	rice, _ := seedDiary(backend)
+	entry := backend.AddFoodEntry(sparkyfitness.FoodEntry{VariantID: rice.DefaultVariant.ID, MealType: "snacks", Quantity: 50, EntryDate: "2025-01-15"})
+
+	out := callTool[DeleteFoodEntryOutput](t, session, "delete_food_entry", map[string]any{"entry_id": entry.ID})
+
+	if out.Entry.EntryID != entry.ID || out.Entry.FoodName != "Rice" {
+		t.Errorf("entry = %+v", out.Entry)
+	}
+	if out.Delta.Calories != -65 {
+		t.Errorf("Delta.Calories = %v, want -65", out.Delta.Calories)
+	}
+	for _, e := range backend.Entries() {
+		if e.ID == entry.ID {
+			t.Error("entry still exists after delete_food_entry")
+		}
+	}
+
+	msg := callToolError(t, session, "delete_food_entry", map[string]any{"entry_id": entry.ID})
+	if !strings.Contains(msg, "not found") {
+		t.Errorf("error = %q, should contain %q", msg, "not found")
+	}
+}
@@ -0,0 +1,116 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// elicitingClient returns client options that answer every elicitation
+// request with action and, when accepting, the given confirm value
+func elicitingClient(action string, confirm bool, asked *int) *mcp.ClientOptions {
+	return &mcp.ClientOptions{
+		ElicitationHandler: func(ctx context.Context, req *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+			*asked++
+			result := &mcp.ElicitResult{Action: action}
+			if action == "accept" {
+				result.Content = map[string]any{"confirm": confirm}
+			}
+			return result, nil
+		},
+	}
+}
+
+func TestDeleteFood(t *testing.T) {
+	tests := []struct {
+		name        string
+		opts        func(asked *int) *mcp.ClientOptions
+		wantDeleted bool
+		wantAsked   int
+	}{
+		{
+			name:        "client without elicitation",
+			opts:        func(*int) *mcp.ClientOptions { return nil },
+			wantDeleted: true,
+			wantAsked:   0,
+		},
+		{
+			name:        "user confirms",
+			opts:        func(asked *int) *mcp.ClientOptions { return elicitingClient("accept", true, asked) },
+			wantDeleted: true,
+			wantAsked:   1,
+		},
+		{
+			name:        "user unchecks confirm",
+			opts:        func(asked *int) *mcp.ClientOptions { return elicitingClient("accept", false, asked) },
+			wantDeleted: false,
+			wantAsked:   1,
+		},
+		{
+			name:        "user declines",
+			opts:        func(asked *int) *mcp.ClientOptions { return elicitingClient("decline", false, asked) },
+			wantDeleted: false,
+			wantAsked:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend, _ := newTestSession(t)
+			food := backend.AddFood(sparkyfitness.Food{Name: "Banana"},
+				sparkyfitness.FoodVariant{ServingSize: 100, ServingUnit: "g"},
+				sparkyfitness.FoodVariant{ServingSize: 1, ServingUnit: "piece"},
+			)
+
+			asked := 0
+			session := connectTestSession(t, backend, tt.opts(&asked))
+			out := callTool[DeleteFoodOutput](t, session, "delete_food", map[string]any{"food_id": food.ID})
+
+			if out.Deleted != tt.wantDeleted {
+				t.Errorf("Deleted = %v, want %v (message: %s)", out.Deleted, tt.wantDeleted, out.Message)
+			}
+			if asked != tt.wantAsked {
+				t.Errorf("elicitation requests = %d, want %d", asked, tt.wantAsked)
+			}
+			if _, _, exists := backend.Food(food.ID); exists == tt.wantDeleted {
+				t.Errorf("food exists = %v after delete_food, want %v", exists, !tt.wantDeleted)
+			}
+			if tt.wantDeleted && out.DeletedVariants != 2 {
+				t.Errorf("DeletedVariants = %d, want 2", out.DeletedVariants)
+			}
+		})
+	}
+}
+
+func TestDeleteFoodAnnotations(t *testing.T) {
+	_, session := newTestSession(t)
+
+	result, err := session.ListTools(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("ListTools() unexpected error: %v", err)
+	}
+
+	destructive := map[string]bool{}
+	for _, tool := range result.Tools {
+		if tool.Annotations != nil && tool.Annotations.DestructiveHint != nil {
+			destructive[tool.Name] = *tool.Annotations.DestructiveHint
+		}
+	}
+	for _, name := range []string{"delete_food", "delete_food_variant"} {
+		if !destructive[name] {
+			t.Errorf("%s should be annotated as destructive", name)
+		}
+	}
+}
+
+func TestDeleteFoodNotFound(t *testing.T) {
+	_, session := newTestSession(t)
+
+	msg := callToolError(t, session, "delete_food", map[string]any{"food_id": "missing"})
+	if !strings.Contains(msg, "not found") {
+		t.Errorf("error = %q, should contain %q", msg, "not found")
+	}
+}
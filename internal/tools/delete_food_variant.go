@@ -0,0 +1,165 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// DeleteFoodVariantInput defines the input parameters for the delete_food_variant tool
+type DeleteFoodVariantInput struct {
+	VariantID string `json:"variant_id" jsonschema:"required,Unique identifier of the variant to delete (from search_foods or get_food results)"`
+	Force     bool   `json:"force,omitempty" jsonschema:"Allow deleting the default or only variant of a food (default: false)"`
+}
+
+// DeleteFoodVariantOutput defines the output structure
+type DeleteFoodVariantOutput struct {
+	FoodID              string `json:"food_id" jsonschema:"ID of the food the variant belonged to"`
+	VariantID           string `json:"variant_id" jsonschema:"ID of the variant"`
+	Deleted             bool   `json:"deleted" jsonschema:"Whether the variant was deleted (false if the user did not confirm)"`
+	NewDefaultVariantID string `json:"new_default_variant_id,omitempty" jsonschema:"Variant promoted to default when the deleted variant was the default"`
+	RemainingVariants   int    `json:"remaining_variants" jsonschema:"Number of variants the food has after the deletion"`
+	Message             string `json:"message" jsonschema:"Result message"`
+}
+
+// RegisterDeleteFoodVariant registers the delete_food_variant tool with the MCP server
+func (r *Registry) RegisterDeleteFoodVariant(server *mcp.Server, client *sparkyfitness.Client) error {
+	tool := &mcp.Tool{
+		Name:  "delete_food_variant",
+		Title: "Delete Food Variant",
+		Description: "🗑️ Permanently delete a single serving size variant of a food.\n\n" +
+			"**When to Use:**\n" +
+			"• A variant was added by mistake (wrong serving size, duplicate serving)\n" +
+			"• To remove the whole food, use delete_food instead\n\n" +
+			"**Safety Rules:**\n" +
+			"• The food's default variant and its only variant are NOT deleted unless force=true\n" +
+			"• When the default variant is force-deleted, the next variant becomes the default\n" +
+			"• If the client supports it, the user is asked to confirm before anything is deleted\n\n" +
+			"**Required Input:**\n" +
+			"• variant_id: UUID from search_foods (variant_id) or get_food results\n" +
+			"• force: only after the user explicitly agreed to remove the default/only variant\n\n" +
+			"**Output:**\n" +
+			"• deleted: true if the variant was deleted, false if the user declined\n" +
+			"• new_default_variant_id: the promoted variant, if the default was deleted",
+		Annotations: &mcp.ToolAnnotations{
+			DestructiveHint: ptr(true),
+		},
+	}
+
+	handler := func(ctx context.Context, request *mcp.CallToolRequest, input DeleteFoodVariantInput) (*mcp.CallToolResult, DeleteFoodVariantOutput, error) {
+		// Validate required parameters
+		if input.VariantID == "" {
+			return nil, DeleteFoodVariantOutput{}, fmt.Errorf("variant_id parameter is required")
+		}
+
+		// Fetch variant and its siblings to enforce the safety rules
+		variant, err := client.GetFoodVariant(ctx, input.VariantID)
+		if err != nil {
+			return nil, DeleteFoodVariantOutput{}, backendError("get food variant", err)
+		}
+		variants, err := client.ListFoodVariants(ctx, variant.FoodID)
+		if err != nil {
+			return nil, DeleteFoodVariantOutput{}, backendError("list food variants", err)
+		}
+
+		var replacement *sparkyfitness.FoodVariant
+		for i := range variants {
+			if variants[i].ID != variant.ID {
+				replacement = &variants[i]
+				break
+			}
+		}
+
+		if !input.Force {
+			if replacement == nil {
+				return nil, DeleteFoodVariantOutput{}, fmt.Errorf(
+					"variant %s is the only variant of its food; use delete_food to remove the food, "+
+						"or set force=true if the user explicitly wants a food without variants", variant.ID)
+			}
+			if variant.IsDefault {
+				return nil, DeleteFoodVariantOutput{}, fmt.Errorf(
+					"variant %s is the food's default variant; set another default first, "+
+						"or set force=true to delete it and promote the next variant", variant.ID)
+			}
+		}
+
+		output := DeleteFoodVariantOutput{
+			FoodID:            variant.FoodID,
+			VariantID:         variant.ID,
+			RemainingVariants: len(variants),
+		}
+
+		// Ask the user before deleting
+		confirmed, err := confirmAction(ctx, request, fmt.Sprintf(
+			"Delete the %g %s variant (%g kcal)? This cannot be undone.",
+			variant.ServingSize, variant.ServingUnit, variant.Calories))
+		if err != nil {
+			return nil, DeleteFoodVariantOutput{}, err
+		}
+		if !confirmed {
+			output.Message = fmt.Sprintf("Variant %g %s was not deleted: the user did not confirm",
+				variant.ServingSize, variant.ServingUnit)
+			return nil, output, nil
+		}
+
+		// Promote another variant before removing the default
+		if variant.IsDefault && replacement != nil {
+			req := variantUpdateRequest(replacement)
+			req.FoodID = variant.FoodID
+			req.IsDefault = true
+			_, err := client.UpdateFoodVariant(ctx, replacement.ID, req)
+			if err != nil {
+				return nil, DeleteFoodVariantOutput{}, backendError("promote new default variant", err)
+			}
+			output.NewDefaultVariantID = replacement.ID
+		}
+
+		// Call backend API to delete variant, undoing the promotion if it fails
+		// so the food is not left with two defaults
+		if err := client.DeleteFoodVariant(ctx, variant.ID); err != nil {
+			err = backendError("delete food variant", err)
+			if output.NewDefaultVariantID != "" {
+				if rollbackErr := rollbackDefaultVariant(ctx, client, variant, replacement); rollbackErr != nil {
+					return nil, DeleteFoodVariantOutput{}, fmt.Errorf("%w; restoring the previous default also failed (%v), "+
+						"so both %s and %s may be marked default: fix it with set_default_variant",
+						err, rollbackErr, variant.ID, replacement.ID)
+				}
+			}
+			return nil, DeleteFoodVariantOutput{}, err
+		}
+
+		// Prepare output
+		output.Deleted = true
+		output.RemainingVariants = len(variants) - 1
+		output.Message = fmt.Sprintf("Successfully deleted %g %s variant", variant.ServingSize, variant.ServingUnit)
+		if output.NewDefaultVariantID != "" {
+			output.Message += fmt.Sprintf("; %g %s is now the default", replacement.ServingSize, replacement.ServingUnit)
+		}
+
+		return nil, output, nil
+	}
+
+	mcp.AddTool(server, tool, handler)
+	return nil
+}
+
+// rollbackDefaultVariant makes variant the default again and restores the
+// previous default flag of replacement after a failed default deletion. Like
+// rollbackFood it runs even when ctx is done, bounded by rollbackTimeout.
+func rollbackDefaultVariant(ctx context.Context, client *sparkyfitness.Client, variant, replacement *sparkyfitness.FoodVariant) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), rollbackTimeout)
+	defer cancel()
+
+	req := variantUpdateRequest(variant)
+	req.IsDefault = true
+	if _, err := client.UpdateFoodVariant(ctx, variant.ID, req); err != nil {
+		return err
+	}
+
+	req = variantUpdateRequest(replacement)
+	req.FoodID = variant.FoodID
+	_, err := client.UpdateFoodVariant(ctx, replacement.ID, req)
+	return err
+}
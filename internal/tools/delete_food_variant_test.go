@@ -0,0 +1,124 @@
+package tools
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness/sparkyfitnesstest"
+)
+
+func TestDeleteFoodVariant(t *testing.T) {
+	backend, session := newTestSession(t)
+	food := backend.AddFood(sparkyfitness.Food{Name: "Banana"},
+		sparkyfitness.FoodVariant{ServingSize: 100, ServingUnit: "g"},
+		sparkyfitness.FoodVariant{ServingSize: 1, ServingUnit: "piece"},
+	)
+	_, variants, _ := backend.Food(food.ID)
+
+	out := callTool[DeleteFoodVariantOutput](t, session, "delete_food_variant", map[string]any{
+		"variant_id": variants[1].ID,
+	})
+
+	if !out.Deleted || out.RemainingVariants != 1 || out.NewDefaultVariantID != "" {
+		t.Errorf("output = %+v", out)
+	}
+	if _, remaining, _ := backend.Food(food.ID); len(remaining) != 1 || remaining[0].ID != variants[0].ID {
+		t.Errorf("remaining variants = %+v", remaining)
+	}
+}
+
+func TestDeleteFoodVariantForceDefault(t *testing.T) {
+	backend, session := newTestSession(t)
+	food := backend.AddFood(sparkyfitness.Food{Name: "Banana"},
+		sparkyfitness.FoodVariant{ServingSize: 100, ServingUnit: "g"},
+		sparkyfitness.FoodVariant{ServingSize: 1, ServingUnit: "piece"},
+	)
+	_, variants, _ := backend.Food(food.ID)
+
+	out := callTool[DeleteFoodVariantOutput](t, session, "delete_food_variant", map[string]any{
+		"variant_id": food.DefaultVariant.ID,
+		"force":      true,
+	})
+
+	if !out.Deleted || out.NewDefaultVariantID != variants[1].ID {
+		t.Errorf("output = %+v, want %s promoted", out, variants[1].ID)
+	}
+	stored, _, _ := backend.Food(food.ID)
+	if stored.DefaultVariant == nil || stored.DefaultVariant.ID != variants[1].ID {
+		t.Errorf("default variant = %+v, want %s", stored.DefaultVariant, variants[1].ID)
+	}
+}
+
+func TestDeleteFoodVariantForceDefaultDeleteFails(t *testing.T) {
+	backend, session := newTestSession(t)
+	food := backend.AddFood(sparkyfitness.Food{Name: "Banana"},
+		sparkyfitness.FoodVariant{ServingSize: 100, ServingUnit: "g"},
+		sparkyfitness.FoodVariant{ServingSize: 1, ServingUnit: "piece"},
+	)
+	backend.InjectFault(sparkyfitnesstest.Fault{Method: http.MethodDelete, Status: http.StatusInternalServerError, Body: `{"error":"db down"}`})
+
+	msg := callToolError(t, session, "delete_food_variant", map[string]any{
+		"variant_id": food.DefaultVariant.ID,
+		"force":      true,
+	})
+
+	if !strings.Contains(msg, "delete food variant") {
+		t.Errorf("error = %q", msg)
+	}
+	_, variants, _ := backend.Food(food.ID)
+	if len(variants) != 2 || !variants[0].IsDefault || variants[1].IsDefault {
+		t.Errorf("variants = %+v, want the original default restored", variants)
+	}
+}
+
+func TestDeleteFoodVariantRefusals(t *testing.T) {
+	backend, session := newTestSession(t)
+	pair := backend.AddFood(sparkyfitness.Food{Name: "Banana"},
+		sparkyfitness.FoodVariant{ServingSize: 100, ServingUnit: "g"},
+		sparkyfitness.FoodVariant{ServingSize: 1, ServingUnit: "piece"},
+	)
+	single := backend.AddFood(sparkyfitness.Food{Name: "Apple"},
+		sparkyfitness.FoodVariant{ServingSize: 100, ServingUnit: "g"},
+	)
+
+	tests := []struct {
+		name         string
+		args         map[string]any
+		wantContains string
+	}{
+		{
+			name:         "default variant",
+			args:         map[string]any{"variant_id": pair.DefaultVariant.ID},
+			wantContains: "default variant",
+		},
+		{
+			name:         "only variant",
+			args:         map[string]any{"variant_id": single.DefaultVariant.ID},
+			wantContains: "only variant",
+		},
+		{
+			name:         "unknown variant",
+			args:         map[string]any{"variant_id": "missing"},
+			wantContains: "not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := callToolError(t, session, "delete_food_variant", tt.args)
+			if !strings.Contains(msg, tt.wantContains) {
+				t.Errorf("error = %q, should contain %q", msg, tt.wantContains)
+			}
+		})
+	}
+
+	// Nothing was deleted
+	if _, variants, _ := backend.Food(pair.ID); len(variants) != 2 {
+		t.Errorf("variants = %d, want 2", len(variants))
+	}
+	if _, variants, _ := backend.Food(single.ID); len(variants) != 1 {
+		t.Errorf("variants = %d, want 1", len(variants))
+	}
+}
@@ -0,0 +1,179 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+)
+
+// normalizeMealType maps a user-supplied meal name onto a backend meal type
+func normalizeMealType(mealType string) (string, error) {
+	switch strings.ToLower(strings.TrimSpace(mealType)) {
+	case "breakfast":
+		return sparkyfitness.MealTypeBreakfast, nil
+	case "lunch":
+		return sparkyfitness.MealTypeLunch, nil
+	case "dinner":
+		return sparkyfitness.MealTypeDinner, nil
+	case "snack", "snacks":
+		return sparkyfitness.MealTypeSnacks, nil
+	default:
+		return "", fmt.Errorf("meal_type must be one of breakfast, lunch, dinner, snacks (got %q)", mealType)
+	}
+}
+
+// parseDiaryDate validates a YYYY-MM-DD date, defaulting to today in loc
+func parseDiaryDate(date string, loc *time.Location) (string, error) {
+	if date == "" {
+		return time.Now().In(loc).Format(time.DateOnly), nil
+	}
+	if _, err := time.Parse(time.DateOnly, date); err != nil {
+		return "", fmt.Errorf("date must be in YYYY-MM-DD format (got %q)", date)
+	}
+	return date, nil
+}
+
+// resolveVariant finds the food variant to log from a variant ID, a food ID
+// (default variant) or a search query, in that order of precedence
+func resolveVariant(ctx context.Context, client *sparkyfitness.Client, foodID, variantID, query string) (*sparkyfitness.Food, *sparkyfitness.FoodVariant, error) {
+	switch {
+	case variantID != "":
+		variant, err := client.GetFoodVariant(ctx, variantID)
+		if err != nil {
+			return nil, nil, backendError("get food variant", err)
+		}
+		if foodID != "" && variant.FoodID != "" && variant.FoodID != foodID {
+			return nil, nil, fmt.Errorf("variant %s does not belong to food %s", variantID, foodID)
+		}
+		if variant.FoodID == "" {
+			if foodID == "" {
+				return nil, nil, fmt.Errorf("variant %s has no food_id; pass food_id as well", variantID)
+			}
+			variant.FoodID = foodID
+		}
+		food, err := client.GetFood(ctx, variant.FoodID)
+		if err != nil {
+			return nil, nil, backendError("get food", err)
+		}
+		return food, variant, nil
+
+	case foodID != "":
+		food, err := client.GetFood(ctx, foodID)
+		if err != nil {
+			return nil, nil, backendError("get food", err)
+		}
+		if food.DefaultVariant == nil {
+			return nil, nil, fmt.Errorf("food '%s' has no default variant; pass variant_id from get_food", food.Name)
+		}
+		return food, food.DefaultVariant, nil
+
+	case query != "":
+		foods, err := client.SearchFoods(ctx, query, true, 5)
+		if err != nil {
+			return nil, nil, backendError("search foods", err)
+		}
+		food, err := pickSearchResult(query, foods)
+		if err != nil {
+			return nil, nil, err
+		}
+		return food, food.DefaultVariant, nil
+
+	default:
+		return nil, nil, fmt.Errorf("one of variant_id, food_id or query is required")
+	}
+}
+
+// pickSearchResult selects the food a query unambiguously refers to: the only
+// result, or the only result whose name matches the query exactly
+func pickSearchResult(query string, foods []sparkyfitness.Food) (*sparkyfitness.Food, error) {
+	var candidates []sparkyfitness.Food
+	for _, food := range foods {
+		if food.DefaultVariant != nil {
+			candidates = append(candidates, food)
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no food matches %q; search with a different name or create it with create_food_variant", query)
+	}
+	if len(candidates) == 1 {
+		return &candidates[0], nil
+	}
+
+	var exact []sparkyfitness.Food
+	for _, food := range candidates {
+		if strings.EqualFold(strings.TrimSpace(food.Name), strings.TrimSpace(query)) {
+			exact = append(exact, food)
+		}
+	}
+	if len(exact) == 1 {
+		return &exact[0], nil
+	}
+
+	names := make([]string, 0, len(candidates))
+	for _, food := range candidates {
+		name := food.Name
+		if food.Brand != nil && *food.Brand != "" {
+			name += " (" + *food.Brand + ")"
+		}
+		names = append(names, fmt.Sprintf("%s [food_id=%s]", name, food.ID))
+	}
+	return nil, fmt.Errorf("%q matches several foods: %s; ask the user which one and pass its food_id",
+		query, strings.Join(names, ", "))
+}
+
+// DiaryEntryResult represents a single food diary entry with the nutrients logged
+type DiaryEntryResult struct {
+	EntryID   string          `json:"entry_id" jsonschema:"Unique identifier of the diary entry"`
+	FoodID    string          `json:"food_id" jsonschema:"ID of the logged food"`
+	FoodName  string          `json:"food_name" jsonschema:"Name of the logged food"`
+	Brand     *string         `json:"brand,omitempty" jsonschema:"Brand of the logged food"`
+	VariantID string          `json:"variant_id" jsonschema:"ID of the logged variant"`
+	MealType  string          `json:"meal_type" jsonschema:"Meal of the entry"`
+	Date      string          `json:"date" jsonschema:"Diary date of the entry"`
+	Quantity  float64         `json:"quantity" jsonschema:"Amount logged"`
+	Unit      string          `json:"unit" jsonschema:"Unit of the quantity"`
+	Servings  float64         `json:"servings" jsonschema:"Number of variant servings logged"`
+	Nutrients NutrientsResult `json:"nutrients" jsonschema:"Nutrients logged for this entry"`
+}
+
+// convertEntryToResult converts a backend diary entry to the tool result format
+func convertEntryToResult(entry sparkyfitness.FoodEntry) DiaryEntryResult {
+	return DiaryEntryResult{
+		EntryID:   entry.ID,
+		FoodID:    entry.FoodID,
+		FoodName:  entry.FoodName,
+		Brand:     entry.BrandName,
+		VariantID: entry.VariantID,
+		MealType:  entry.MealType,
+		Date:      entry.EntryDate,
+		Quantity:  entry.Quantity,
+		Unit:      entry.Unit,
+		Servings:  round2(entry.Servings()),
+		Nutrients: convertNutrientsToResult(entry.LoggedNutrients()),
+	}
+}
+
+// resolveQuantity returns the amount to log in the variant's serving unit
+// from either an explicit quantity or a number of servings (default: one serving)
+func resolveQuantity(quantity, servings *float64, variant *sparkyfitness.FoodVariant) (float64, error) {
+	switch {
+	case quantity != nil && servings != nil:
+		return 0, fmt.Errorf("provide either quantity or servings, not both")
+	case quantity != nil:
+		if *quantity <= 0 {
+			return 0, fmt.Errorf("quantity must be greater than 0")
+		}
+		return *quantity, nil
+	case servings != nil:
+		if *servings <= 0 {
+			return 0, fmt.Errorf("servings must be greater than 0")
+		}
+		return *servings * variant.ServingSize, nil
+	default:
+		return variant.ServingSize, nil
+	}
+}
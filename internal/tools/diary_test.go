@@ -0,0 +1,32 @@
+package tools
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDiaryDateTimezone(t *testing.T) {
+	// 26 hours apart, so "today" always falls on different dates
+	west := time.FixedZone("UTC-12", -12*60*60)
+	east := time.FixedZone("UTC+14", 14*60*60)
+
+	westDate, err := parseDiaryDate("", west)
+	if err != nil {
+		t.Fatalf("parseDiaryDate() unexpected error: %v", err)
+	}
+	eastDate, err := parseDiaryDate("", east)
+	if err != nil {
+		t.Fatalf("parseDiaryDate() unexpected error: %v", err)
+	}
+
+	if westDate == eastDate {
+		t.Errorf("today = %s in both UTC-12 and UTC+14, want the timezone's own date", westDate)
+	}
+	if want := time.Now().In(east).Format(time.DateOnly); eastDate != want {
+		t.Errorf("today in UTC+14 = %s, want %s", eastDate, want)
+	}
+
+	if date, err := parseDiaryDate("2025-01-15", east); err != nil || date != "2025-01-15" {
+		t.Errorf("parseDiaryDate(2025-01-15) = %q, %v; want the date unchanged", date, err)
+	}
+}
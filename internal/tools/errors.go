@@ -0,0 +1,69 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+)
+
+// backendError converts a SparkyFitness client error into a tool error with an
+// actionable explanation for the agent. action describes what the tool was
+// doing (e.g., "search foods") and is used as the message prefix.
+func backendError(action string, err error) error {
+	apiErr, ok := sparkyfitness.AsAPIError(err)
+	if !ok {
+		switch {
+		case errors.Is(err, context.Canceled):
+			return fmt.Errorf("failed to %s: the request was cancelled before SparkyFitness answered: %w", action, err)
+		case errors.Is(err, context.DeadlineExceeded):
+			return fmt.Errorf("failed to %s: timed out waiting for the SparkyFitness backend. "+
+				"It may be slow or overloaded; a write may still have been applied, so check before retrying: %w",
+				action, err)
+		case errors.Is(err, sparkyfitness.ErrInvalidResponse):
+			return fmt.Errorf("failed to %s: unexpected response from SparkyFitness. "+
+				"The backend version may not match this server; report it to the user: %w",
+				action, err)
+		default:
+			// Transport failures: DNS, refused connections, TLS
+			return fmt.Errorf("failed to %s: could not reach the SparkyFitness backend: %w", action, err)
+		}
+	}
+
+	detail := apiErr.Message
+	if detail == "" {
+		detail = fmt.Sprintf("HTTP %d", apiErr.StatusCode)
+	}
+
+	switch apiErr.Kind {
+	case sparkyfitness.ErrorKindAuth:
+		return fmt.Errorf("failed to %s: SparkyFitness rejected the API key (HTTP %d). "+
+			"The server's SPARKYFITNESS_API_KEY is missing, invalid or lacks permission; "+
+			"ask the user to check the MCP server configuration. Retrying will not help",
+			action, apiErr.StatusCode)
+	case sparkyfitness.ErrorKindNotFound:
+		return fmt.Errorf("failed to %s: the referenced item was not found (%s). "+
+			"Verify the IDs used, e.g. by calling search_foods again, and do not reuse stale IDs",
+			action, detail)
+	case sparkyfitness.ErrorKindConflict:
+		return fmt.Errorf("failed to %s: the change conflicts with existing data (%s). "+
+			"Search for the existing entry and update or reuse it instead of creating a duplicate",
+			action, detail)
+	case sparkyfitness.ErrorKindValidation:
+		return fmt.Errorf("failed to %s: the backend rejected the input (%s). "+
+			"Fix the listed fields and try again",
+			action, detail)
+	case sparkyfitness.ErrorKindRateLimited:
+		return fmt.Errorf("failed to %s: the SparkyFitness backend is rate limiting requests. "+
+			"Wait a moment before retrying and avoid issuing many calls at once",
+			action)
+	case sparkyfitness.ErrorKindServer:
+		return fmt.Errorf("failed to %s: the SparkyFitness backend encountered an internal error (HTTP %d: %s). "+
+			"This is not caused by the input; retry later or report it to the user",
+			action, apiErr.StatusCode, detail)
+	default:
+		return fmt.Errorf("failed to %s: unexpected response from SparkyFitness (HTTP %d: %s)",
+			action, apiErr.StatusCode, detail)
+	}
+}
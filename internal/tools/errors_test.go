@@ -0,0 +1,65 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+)
+
+func TestBackendError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "cancelled",
+			err:  fmt.Errorf("failed to execute request: %w", context.Canceled),
+			want: "was cancelled",
+		},
+		{
+			name: "timed out",
+			err:  fmt.Errorf("failed to execute request: %w", context.DeadlineExceeded),
+			want: "timed out",
+		},
+		{
+			name: "malformed response",
+			err:  fmt.Errorf("%w: failed to parse response: unexpected EOF", sparkyfitness.ErrInvalidResponse),
+			want: "unexpected response from SparkyFitness",
+		},
+		{
+			name: "unreachable",
+			err:  errors.New("failed to execute request: connection refused"),
+			want: "could not reach",
+		},
+		{
+			name: "not found",
+			err:  sparkyfitness.NewAPIError(http.MethodGet, "/foods/1", http.StatusNotFound, []byte(`{"error":"Food not found"}`)),
+			want: "was not found (Food not found)",
+		},
+		{
+			name: "unauthorized",
+			err:  sparkyfitness.NewAPIError(http.MethodGet, "/foods", http.StatusUnauthorized, nil),
+			want: "rejected the API key",
+		},
+		{
+			name: "server error",
+			err:  sparkyfitness.NewAPIError(http.MethodGet, "/foods", http.StatusInternalServerError, nil),
+			want: "internal error (HTTP 500",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := backendError("search foods", tt.err)
+			if !strings.HasPrefix(err.Error(), "failed to search foods: ") || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("backendError() = %q, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
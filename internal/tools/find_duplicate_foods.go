@@ -0,0 +1,378 @@
+package tools
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/nutrition"
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// FindDuplicateFoodsInput defines the input parameters for the find_duplicate_foods tool
+type FindDuplicateFoodsInput struct {
+	Name        string   `json:"name" jsonschema:"required,Name of the food about to be created"`
+	Brand       *string  `json:"brand,omitempty" jsonschema:"Brand of the food about to be created"`
+	ServingSize *float64 `json:"serving_size,omitempty" jsonschema:"Serving size of the label nutrition (needed to compare nutrition)"`
+	ServingUnit string   `json:"serving_unit,omitempty" jsonschema:"Serving unit of the label nutrition (e.g., 'g', 'ml'; needed to compare nutrition)"`
+	Calories    *float64 `json:"calories,omitempty" jsonschema:"Calories per serving"`
+	Protein     *float64 `json:"protein,omitempty" jsonschema:"Protein in grams per serving"`
+	Carbs       *float64 `json:"carbs,omitempty" jsonschema:"Carbohydrates in grams per serving"`
+	Fat         *float64 `json:"fat,omitempty" jsonschema:"Fat in grams per serving"`
+	Limit       *int     `json:"limit,omitempty" jsonschema:"Maximum number of candidates to return (default: 5)"`
+}
+
+// DuplicateCandidate is an existing food that may be the same as the new one
+type DuplicateCandidate struct {
+	FoodID         string   `json:"food_id" jsonschema:"Unique identifier of the existing food"`
+	FoodName       string   `json:"food_name" jsonschema:"Name of the existing food"`
+	Brand          *string  `json:"brand,omitempty" jsonschema:"Brand of the existing food"`
+	VariantID      string   `json:"variant_id" jsonschema:"ID of the default variant that was compared"`
+	ServingSize    float64  `json:"serving_size" jsonschema:"Serving size of the default variant"`
+	ServingUnit    string   `json:"serving_unit" jsonschema:"Serving unit of the default variant"`
+	Calories       float64  `json:"calories" jsonschema:"Calories per serving of the default variant"`
+	Protein        float64  `json:"protein" jsonschema:"Protein in grams"`
+	Carbs          float64  `json:"carbs" jsonschema:"Carbohydrates in grams"`
+	Fat            float64  `json:"fat" jsonschema:"Fat in grams"`
+	NameScore      float64  `json:"name_score" jsonschema:"Name similarity from 0 to 1"`
+	BrandScore     *float64 `json:"brand_score,omitempty" jsonschema:"Brand similarity from 0 to 1, when both foods have a brand"`
+	NutritionScore *float64 `json:"nutrition_score,omitempty" jsonschema:"Closeness of calories and macros per 100 g or 100 ml from 0 to 1, when comparable"`
+	Confidence     float64  `json:"confidence" jsonschema:"Overall confidence from 0 to 1 that this is the same food"`
+	Level          string   `json:"level" jsonschema:"Confidence level: high, medium or low"`
+	Reasons        []string `json:"reasons,omitempty" jsonschema:"What the confidence is based on"`
+}
+
+// FindDuplicateFoodsOutput defines the output structure
+type FindDuplicateFoodsOutput struct {
+	Candidates     []DuplicateCandidate `json:"candidates" jsonschema:"Possible duplicates, most likely first"`
+	Total          int                  `json:"total" jsonschema:"Number of candidates returned"`
+	Queries        []string             `json:"queries" jsonschema:"Searches run against the database"`
+	Recommendation string               `json:"recommendation" jsonschema:"Recommended action: add_variant, confirm_with_user or create_new"`
+	Message        string               `json:"message" jsonschema:"Explanation of the recommendation"`
+}
+
+const (
+	// duplicateSearchLimit is the number of results fetched per search
+	duplicateSearchLimit = 20
+	// maxTokenQueries caps the single-word searches run for one name
+	maxTokenQueries = 3
+	// minDuplicateConfidence drops candidates that are clearly different foods
+	minDuplicateConfidence = 0.4
+	// highConfidence and mediumConfidence separate the confidence levels
+	highConfidence   = 0.85
+	mediumConfidence = 0.65
+	// differentBrandCap is the highest confidence for foods of another brand
+	differentBrandCap = 0.5
+)
+
+// duplicateWeights weigh the name, brand and nutrition scores; missing
+// scores are left out and the remaining weights renormalized
+var duplicateWeights = struct{ name, brand, nutrition float64 }{0.5, 0.2, 0.3}
+
+// stopWords are skipped when searching for single words of a name
+var stopWords = map[string]bool{
+	"and": true, "with": true, "the": true, "for": true, "from": true, "in": true, "of": true,
+}
+
+// RegisterFindDuplicateFoods registers the find_duplicate_foods tool with the MCP server
+func (r *Registry) RegisterFindDuplicateFoods(server *mcp.Server, client *sparkyfitness.Client) error {
+	tool := &mcp.Tool{
+		Name:  "find_duplicate_foods",
+		Title: "Find Duplicate Foods",
+		Description: "🧐 Check whether a food about to be created already exists, with a confidence score and a recommended action.\n\n" +
+			"**When to Use:**\n" +
+			"• BEFORE create_food_variant, instead of judging search_foods results by eye\n" +
+			"• When search_foods returns several similar foods and it is unclear which (if any) is the same product\n\n" +
+			"**How it works:**\n" +
+			"• Runs several searches: the full name, the name without the brand, and its main words\n" +
+			"• Scores every candidate by name similarity, brand similarity, and closeness of calories and macros per 100 g " +
+			"(only the candidate's default variant is compared)\n\n" +
+			"**Required Input:**\n" +
+			"• name: Food name from the label\n\n" +
+			"**Optional Input (improves accuracy):**\n" +
+			"• brand\n" +
+			"• serving_size + serving_unit + calories/protein/carbs/fat from the label\n" +
+			"• limit: Maximum candidates (default: 5)\n\n" +
+			"**Output:**\n" +
+			"• candidates: ranked with confidence (0-1), level (high/medium/low) and reasons\n" +
+			"• recommendation:\n" +
+			"  - add_variant: a high-confidence match exists → add_food_variant to its food_id (get_food first to check the serving)\n" +
+			"  - confirm_with_user: possible matches → show them and ask the user\n" +
+			"  - create_new: no likely duplicate → create_food_variant",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}
+
+	handler := func(ctx context.Context, request *mcp.CallToolRequest, input FindDuplicateFoodsInput) (*mcp.CallToolResult, FindDuplicateFoodsOutput, error) {
+		output, err := findDuplicateFoods(ctx, client, input)
+		if err != nil {
+			return nil, FindDuplicateFoodsOutput{}, err
+		}
+		return nil, output, nil
+	}
+
+	mcp.AddTool(server, tool, handler)
+	return nil
+}
+
+// findDuplicateFoods searches for and scores existing foods that may be the
+// food described by input. It backs find_duplicate_foods and batch_create_foods.
+func findDuplicateFoods(ctx context.Context, client *sparkyfitness.Client, input FindDuplicateFoodsInput) (FindDuplicateFoodsOutput, error) {
+	// Validate required parameters
+	if strings.TrimSpace(input.Name) == "" {
+		return FindDuplicateFoodsOutput{}, fmt.Errorf("name parameter is required")
+	}
+
+	limit := 5
+	if input.Limit != nil {
+		limit = *input.Limit
+	}
+	if limit <= 0 {
+		return FindDuplicateFoodsOutput{}, fmt.Errorf("limit must be greater than 0")
+	}
+
+	brand := ""
+	if input.Brand != nil {
+		brand = strings.TrimSpace(*input.Brand)
+	}
+
+	label, labelFields, err := labelVariant(input)
+	if err != nil {
+		return FindDuplicateFoodsOutput{}, err
+	}
+
+	// Call backend API to collect candidates from every search
+	queries := duplicateQueries(input.Name, brand)
+	var foods []sparkyfitness.Food
+	seen := map[string]bool{}
+	for _, query := range queries {
+		results, err := client.SearchFoods(ctx, query, true, duplicateSearchLimit)
+		if err != nil {
+			return FindDuplicateFoodsOutput{}, backendError("search foods", err)
+		}
+		for _, food := range results {
+			if food.DefaultVariant == nil || seen[food.ID] {
+				continue
+			}
+			seen[food.ID] = true
+			foods = append(foods, food)
+		}
+	}
+
+	// Score and rank candidates
+	candidates := []DuplicateCandidate{}
+	for _, food := range foods {
+		candidate := scoreDuplicate(input.Name, brand, label, labelFields, food)
+		if candidate.Confidence >= minDuplicateConfidence {
+			candidates = append(candidates, candidate)
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b DuplicateCandidate) int {
+		return cmp.Compare(b.Confidence, a.Confidence)
+	})
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	// Prepare output
+	output := FindDuplicateFoodsOutput{
+		Candidates: candidates,
+		Total:      len(candidates),
+		Queries:    queries,
+	}
+	switch {
+	case len(candidates) > 0 && candidates[0].Level == "high":
+		output.Recommendation = "add_variant"
+		output.Message = fmt.Sprintf("'%s' (food_id=%s) is very likely the same food. Add the serving to it with add_food_variant "+
+			"(check get_food first in case the serving already exists) instead of creating a duplicate.",
+			candidates[0].FoodName, candidates[0].FoodID)
+	case len(candidates) > 0 && candidates[0].Level == "medium":
+		output.Recommendation = "confirm_with_user"
+		output.Message = "Found possible duplicates. Show them to the user and ask whether to add a variant to one of them or create a new food."
+	default:
+		output.Recommendation = "create_new"
+		output.Message = "No likely duplicate found. Create the food with create_food_variant."
+	}
+
+	return output, nil
+}
+
+// labelVariant builds the variant described by the label nutrition in the
+// input and lists the nutrients given, or returns nil when no nutrition was
+// given
+func labelVariant(input FindDuplicateFoodsInput) (*sparkyfitness.FoodVariant, []string, error) {
+	v := &sparkyfitness.FoodVariant{}
+	var fields []string
+	for _, f := range []struct {
+		name  string
+		dst   *float64
+		value *float64
+	}{
+		{"calories", &v.Calories, input.Calories},
+		{"protein", &v.Protein, input.Protein},
+		{"carbs", &v.Carbs, input.Carbs},
+		{"fat", &v.Fat, input.Fat},
+	} {
+		if f.value != nil {
+			*f.dst = *f.value
+			fields = append(fields, f.name)
+		}
+	}
+	if len(fields) == 0 {
+		return nil, nil, nil
+	}
+
+	if input.ServingSize == nil || *input.ServingSize <= 0 || input.ServingUnit == "" {
+		return nil, nil, fmt.Errorf("serving_size and serving_unit are required to compare nutrition")
+	}
+	v.ServingSize = *input.ServingSize
+	v.ServingUnit = input.ServingUnit
+	return v, fields, nil
+}
+
+// duplicateQueries lists the searches to run for a name: the name itself,
+// the name without the brand's words and its longest significant words
+func duplicateQueries(name, brand string) []string {
+	words := strings.Fields(name)
+
+	queries := []string{strings.TrimSpace(name)}
+	if brand != "" {
+		brandWords := map[string]bool{}
+		for _, w := range strings.Fields(normalizeText(brand)) {
+			brandWords[w] = true
+		}
+		var stripped []string
+		for _, w := range words {
+			if !brandWords[normalizeText(w)] {
+				stripped = append(stripped, w)
+			}
+		}
+		queries = append(queries, strings.Join(stripped, " "))
+	}
+
+	var tokens []string
+	for _, w := range words {
+		w = strings.TrimFunc(w, func(r rune) bool { return !unicode.IsLetter(r) && !unicode.IsDigit(r) })
+		if len([]rune(w)) >= 3 && !stopWords[strings.ToLower(w)] {
+			tokens = append(tokens, w)
+		}
+	}
+	slices.SortStableFunc(tokens, func(a, b string) int { return cmp.Compare(len(b), len(a)) })
+	queries = append(queries, tokens[:min(len(tokens), maxTokenQueries)]...)
+
+	// Drop empty and repeated searches
+	var unique []string
+	for _, q := range queries {
+		if q != "" && !slices.ContainsFunc(unique, func(u string) bool { return normalizeText(u) == normalizeText(q) }) {
+			unique = append(unique, q)
+		}
+	}
+	return unique
+}
+
+// scoreDuplicate scores how likely food is the food described by name,
+// brand and the optional label variant with the given nutrients
+func scoreDuplicate(name, brand string, label *sparkyfitness.FoodVariant, labelFields []string, food sparkyfitness.Food) DuplicateCandidate {
+	variant := food.DefaultVariant
+	candidate := DuplicateCandidate{
+		FoodID:      food.ID,
+		FoodName:    food.Name,
+		Brand:       food.Brand,
+		VariantID:   variant.ID,
+		ServingSize: variant.ServingSize,
+		ServingUnit: variant.ServingUnit,
+		Calories:    variant.Calories,
+		Protein:     variant.Protein,
+		Carbs:       variant.Carbs,
+		Fat:         variant.Fat,
+	}
+
+	// Names match in either direction, e.g., "Greek Yogurt" and "Plain Greek Yogurt"
+	nameScore := max(nameRelevance(name, food.Name), nameRelevance(food.Name, name))
+	candidate.NameScore = round2(nameScore)
+	total, weights := duplicateWeights.name*nameScore, duplicateWeights.name
+
+	differentBrand := false
+	if brand != "" && food.Brand != nil && strings.TrimSpace(*food.Brand) != "" {
+		brandScore := similarity(brand, *food.Brand)
+		candidate.BrandScore = ptr(round2(brandScore))
+		total += duplicateWeights.brand * brandScore
+		weights += duplicateWeights.brand
+		if brandScore >= brandMatchThreshold {
+			candidate.Reasons = append(candidate.Reasons, "same brand")
+		} else {
+			differentBrand = true
+			candidate.Reasons = append(candidate.Reasons, fmt.Sprintf("different brand (%s)", *food.Brand))
+		}
+	}
+
+	if label != nil {
+		if score, ok := nutritionCloseness(*label, *variant, labelFields); ok {
+			candidate.NutritionScore = ptr(round2(score))
+			total += duplicateWeights.nutrition * score
+			weights += duplicateWeights.nutrition
+			candidate.Reasons = append(candidate.Reasons, fmt.Sprintf("nutrition per 100 %s is %.0f%% similar",
+				normalizedUnit(*variant), score*100))
+		} else {
+			candidate.Reasons = append(candidate.Reasons, fmt.Sprintf("nutrition not comparable (%s vs %s servings)",
+				variant.ServingUnit, label.ServingUnit))
+		}
+	}
+
+	confidence := total / weights
+	if differentBrand {
+		confidence = math.Min(confidence, differentBrandCap)
+	}
+	candidate.Confidence = round2(confidence)
+
+	switch {
+	case confidence >= highConfidence:
+		candidate.Level = "high"
+	case confidence >= mediumConfidence:
+		candidate.Level = "medium"
+	default:
+		candidate.Level = "low"
+	}
+	return candidate
+}
+
+// nutritionCloseness compares the named nutrients of two variants per 100 g
+// or 100 ml from 0 (unrelated) to 1 (equal). It fails when either serving
+// cannot be normalized or they differ in dimension (mass vs volume).
+func nutritionCloseness(a, b sparkyfitness.FoodVariant, fields []string) (float64, bool) {
+	na, err := nutrition.Normalize(a)
+	if err != nil {
+		return 0, false
+	}
+	nb, err := nutrition.Normalize(b)
+	if err != nil || na.ServingUnit != nb.ServingUnit {
+		return 0, false
+	}
+
+	// Differences below the floor (kcal or g per 100) count as rounding
+	var diff float64
+	for _, field := range fields {
+		va, _ := nutrition.NutrientField(&na.Nutrients, field)
+		vb, _ := nutrition.NutrientField(&nb.Nutrients, field)
+		floor := 2.0
+		if field == "calories" {
+			floor = 20
+		}
+		diff += math.Min(math.Abs(*va-*vb)/math.Max(math.Max(*va, *vb), floor), 1)
+	}
+	return 1 - diff/float64(len(fields)), true
+}
+
+// normalizedUnit returns the unit a variant normalizes to ("g" or "ml")
+func normalizedUnit(v sparkyfitness.FoodVariant) string {
+	if u, ok := nutrition.LookupUnit(v.ServingUnit); ok && u.Dimension == nutrition.DimensionVolume {
+		return "ml"
+	}
+	return "g"
+}
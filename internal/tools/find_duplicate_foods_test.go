@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+)
+
+func TestFindDuplicateFoods(t *testing.T) {
+	backend, session := newTestSession(t)
+
+	fage, chobani := "Fage", "Chobani"
+	yogurt := sparkyfitness.FoodVariant{ServingSize: 170, ServingUnit: "g", Nutrients: sparkyfitness.Nutrients{Calories: 100, Protein: 18, Carbs: 6, Fat: 0}}
+	fageYogurt := backend.AddFood(sparkyfitness.Food{Name: "Greek Yogurt", Brand: &fage}, yogurt)
+	backend.AddFood(sparkyfitness.Food{Name: "Greek Yogurt", Brand: &chobani}, yogurt)
+	backend.AddFood(sparkyfitness.Food{Name: "Granola"},
+		sparkyfitness.FoodVariant{ServingSize: 50, ServingUnit: "g", Nutrients: sparkyfitness.Nutrients{Calories: 230, Protein: 5, Carbs: 32, Fat: 9}},
+	)
+
+	t.Run("same brand and nutrition", func(t *testing.T) {
+		out := callTool[FindDuplicateFoodsOutput](t, session, "find_duplicate_foods", map[string]any{
+			"name": "Fage Greek Yogurt", "brand": "FAGE",
+			"serving_size": 100, "serving_unit": "g", "calories": 59, "protein": 10.6, "carbs": 3.5, "fat": 0,
+		})
+
+		if out.Recommendation != "add_variant" {
+			t.Errorf("Recommendation = %q, want add_variant", out.Recommendation)
+		}
+		if out.Total != 2 {
+			t.Fatalf("Total = %d, want 2: %+v", out.Total, out.Candidates)
+		}
+		top := out.Candidates[0]
+		if top.FoodID != fageYogurt.ID || top.Level != "high" || top.NutritionScore == nil || *top.NutritionScore < 0.95 {
+			t.Errorf("Candidates[0] = %+v, want the Fage yogurt with high confidence", top)
+		}
+		if other := out.Candidates[1]; other.Confidence > differentBrandCap || other.Level != "low" {
+			t.Errorf("Candidates[1] = %+v, want a low-confidence different brand", other)
+		}
+		if want := []string{"Fage Greek Yogurt", "Greek Yogurt", "Yogurt", "Greek", "Fage"}; !slices.Equal(out.Queries, want) {
+			t.Errorf("Queries = %q, want %q", out.Queries, want)
+		}
+	})
+
+	t.Run("same name with different nutrition", func(t *testing.T) {
+		out := callTool[FindDuplicateFoodsOutput](t, session, "find_duplicate_foods", map[string]any{
+			"name": "Greek Yogurt", "serving_size": 100, "serving_unit": "g", "calories": 150, "protein": 5, "carbs": 10, "fat": 10,
+		})
+
+		if out.Recommendation != "confirm_with_user" {
+			t.Errorf("Recommendation = %q, want confirm_with_user: %+v", out.Recommendation, out.Candidates)
+		}
+		if out.Total == 0 || out.Candidates[0].Level != "medium" {
+			t.Errorf("Candidates = %+v, want medium confidence", out.Candidates)
+		}
+	})
+
+	t.Run("no duplicates", func(t *testing.T) {
+		out := callTool[FindDuplicateFoodsOutput](t, session, "find_duplicate_foods", map[string]any{"name": "Quinoa Salad"})
+
+		if out.Recommendation != "create_new" || out.Total != 0 {
+			t.Errorf("output = %+v, want create_new without candidates", out)
+		}
+	})
+
+	t.Run("nutrition without serving", func(t *testing.T) {
+		msg := callToolError(t, session, "find_duplicate_foods", map[string]any{"name": "Greek Yogurt", "calories": 59})
+
+		if !strings.Contains(msg, "serving_size and serving_unit are required") {
+			t.Errorf("error = %q", msg)
+		}
+	})
+}
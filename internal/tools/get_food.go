@@ -0,0 +1,166 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// GetFoodInput defines the input parameters for the get_food tool
+type GetFoodInput struct {
+	FoodID string `json:"food_id" jsonschema:"required,Unique identifier of the food (from search_foods results)"`
+}
+
+// VariantResult represents a single serving size variant with full nutrition
+type VariantResult struct {
+	VariantID          string                 `json:"variant_id" jsonschema:"Unique identifier of the variant"`
+	ServingSize        float64                `json:"serving_size" jsonschema:"Serving size amount"`
+	ServingUnit        string                 `json:"serving_unit" jsonschema:"Unit of measurement for serving"`
+	IsDefault          bool                   `json:"is_default" jsonschema:"Whether this is the food's default variant"`
+	Calories           float64                `json:"calories" jsonschema:"Calories per serving"`
+	Protein            float64                `json:"protein" jsonschema:"Protein in grams"`
+	Carbs              float64                `json:"carbs" jsonschema:"Carbohydrates in grams"`
+	Fat                float64                `json:"fat" jsonschema:"Fat in grams"`
+	SaturatedFat       float64                `json:"saturated_fat,omitempty" jsonschema:"Saturated fat in grams"`
+	PolyunsaturatedFat float64                `json:"polyunsaturated_fat,omitempty" jsonschema:"Polyunsaturated fat in grams"`
+	MonounsaturatedFat float64                `json:"monounsaturated_fat,omitempty" jsonschema:"Monounsaturated fat in grams"`
+	TransFat           float64                `json:"trans_fat,omitempty" jsonschema:"Trans fat in grams"`
+	Cholesterol        float64                `json:"cholesterol,omitempty" jsonschema:"Cholesterol in milligrams"`
+	Sodium             float64                `json:"sodium,omitempty" jsonschema:"Sodium in milligrams"`
+	Potassium          float64                `json:"potassium,omitempty" jsonschema:"Potassium in milligrams"`
+	DietaryFiber       float64                `json:"dietary_fiber,omitempty" jsonschema:"Dietary fiber in grams"`
+	Sugars             float64                `json:"sugars,omitempty" jsonschema:"Sugars in grams"`
+	VitaminA           float64                `json:"vitamin_a,omitempty" jsonschema:"Vitamin A in micrograms (mcg RAE)"`
+	VitaminC           float64                `json:"vitamin_c,omitempty" jsonschema:"Vitamin C in milligrams"`
+	Calcium            float64                `json:"calcium,omitempty" jsonschema:"Calcium in milligrams"`
+	Iron               float64                `json:"iron,omitempty" jsonschema:"Iron in milligrams"`
+	GlycemicIndex      *string                `json:"glycemic_index,omitempty" jsonschema:"Glycemic index if available"`
+	CustomNutrients    []CustomNutrientResult `json:"custom_nutrients,omitempty" jsonschema:"Amounts of the user's custom nutrients"`
+}
+
+// GetFoodOutput defines the output structure
+type GetFoodOutput struct {
+	FoodID        string          `json:"food_id" jsonschema:"Unique identifier of the food"`
+	FoodName      string          `json:"food_name" jsonschema:"Name of the food"`
+	Brand         *string         `json:"brand,omitempty" jsonschema:"Brand name if available"`
+	IsCustom      bool            `json:"is_custom" jsonschema:"Whether this is a custom food"`
+	ProviderType  *string         `json:"provider_type,omitempty" jsonschema:"Provider type (e.g., usda, nutritionix)"`
+	Variants      []VariantResult `json:"variants" jsonschema:"Every serving size variant of the food, default first"`
+	TotalVariants int             `json:"total_variants" jsonschema:"Number of variants"`
+}
+
+// RegisterGetFood registers the get_food tool with the MCP server
+func (r *Registry) RegisterGetFood(server *mcp.Server, client *sparkyfitness.Client) error {
+	tool := &mcp.Tool{
+		Name:  "get_food",
+		Title: "Get Food with All Variants",
+		Description: "📋 Get a food and ALL of its serving size variants with full nutrition.\n\n" +
+			"**When to Use:**\n" +
+			"• BEFORE add_food_variant, to check whether the serving size already exists\n" +
+			"• When the user asks about a specific food found via search_foods\n\n" +
+			"**Why:**\n" +
+			"search_foods only returns each food's default variant. A food can also have '1 cup', '1 piece' or other servings that are only visible here.\n\n" +
+			"**Required Input:**\n" +
+			"• food_id: UUID from search_foods results\n\n" +
+			"**Output:**\n" +
+			"• Food metadata (name, brand, custom/provider)\n" +
+			"• variants: every variant with variant_id, serving size/unit, is_default and full nutrition (default first), " +
+			"including the user's custom nutrients\n\n" +
+			"**Example Workflow:**\n" +
+			"User: 'Add a 1 cup serving for Brown Rice'\n" +
+			"1. search_foods(name='Brown Rice') → food_id='abc-123'\n" +
+			"2. get_food(food_id='abc-123') → variants: 100 g, 1 cup\n" +
+			"3. A 1 cup variant already exists → tell the user instead of calling add_food_variant",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}
+
+	handler := func(ctx context.Context, request *mcp.CallToolRequest, input GetFoodInput) (*mcp.CallToolResult, GetFoodOutput, error) {
+		// Validate required parameters
+		if input.FoodID == "" {
+			return nil, GetFoodOutput{}, fmt.Errorf("food_id parameter is required")
+		}
+
+		// Fetch food metadata and variants from backend API
+		food, err := client.GetFood(ctx, input.FoodID)
+		if err != nil {
+			return nil, GetFoodOutput{}, backendError("get food", err)
+		}
+
+		variants, err := client.ListFoodVariants(ctx, input.FoodID)
+		if err != nil {
+			return nil, GetFoodOutput{}, backendError("list food variants", err)
+		}
+
+		// Fall back to the default variant if the listing came back empty
+		if len(variants) == 0 && food.DefaultVariant != nil {
+			variants = []sparkyfitness.FoodVariant{*food.DefaultVariant}
+		}
+
+		// Convert variants, default first
+		results := make([]VariantResult, 0, len(variants))
+		custom := make([]map[string]interface{}, 0, len(variants))
+		for _, variant := range variants {
+			if variant.IsDefault {
+				results = append([]VariantResult{convertVariantToResult(variant)}, results...)
+			} else {
+				results = append(results, convertVariantToResult(variant))
+			}
+			custom = append(custom, variant.CustomNutrients)
+		}
+
+		// Add the units of custom nutrients
+		units := customNutrientUnits(ctx, client, custom...)
+		for i := range results {
+			setCustomNutrientUnits(results[i].CustomNutrients, units)
+		}
+
+		// Prepare output
+		output := GetFoodOutput{
+			FoodID:        food.ID,
+			FoodName:      food.Name,
+			Brand:         food.Brand,
+			IsCustom:      food.IsCustom,
+			ProviderType:  food.ProviderType,
+			Variants:      results,
+			TotalVariants: len(results),
+		}
+
+		return nil, output, nil
+	}
+
+	mcp.AddTool(server, tool, handler)
+	return nil
+}
+
+// convertVariantToResult converts a FoodVariant from backend API to VariantResult
+func convertVariantToResult(variant sparkyfitness.FoodVariant) VariantResult {
+	return VariantResult{
+		VariantID:          variant.ID,
+		ServingSize:        variant.ServingSize,
+		ServingUnit:        variant.ServingUnit,
+		IsDefault:          variant.IsDefault,
+		Calories:           variant.Calories,
+		Protein:            variant.Protein,
+		Carbs:              variant.Carbs,
+		Fat:                variant.Fat,
+		SaturatedFat:       variant.SaturatedFat,
+		PolyunsaturatedFat: variant.PolyunsaturatedFat,
+		MonounsaturatedFat: variant.MonounsaturatedFat,
+		TransFat:           variant.TransFat,
+		Cholesterol:        variant.Cholesterol,
+		Sodium:             variant.Sodium,
+		Potassium:          variant.Potassium,
+		DietaryFiber:       variant.DietaryFiber,
+		Sugars:             variant.Sugars,
+		VitaminA:           variant.VitaminA,
+		VitaminC:           variant.VitaminC,
+		Calcium:            variant.Calcium,
+		Iron:               variant.Iron,
+		GlycemicIndex:      variant.GlycemicIndex,
+		CustomNutrients:    convertCustomNutrientsToResult(variant.CustomNutrients),
+	}
+}
@@ -0,0 +1,188 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxDiaryDays bounds the date range get_food_diary returns in one call
+const maxDiaryDays = 31
+
+// GetFoodDiaryInput defines the input parameters for the get_food_diary tool
+type GetFoodDiaryInput struct {
+	Date     string `json:"date,omitempty" jsonschema:"Diary date (or range start) in YYYY-MM-DD format (default: today)"`
+	EndDate  string `json:"end_date,omitempty" jsonschema:"Last date of a range in YYYY-MM-DD format, inclusive (default: same as date)"`
+	MealType string `json:"meal_type,omitempty" jsonschema:"Only return this meal: breakfast, lunch, dinner or snacks"`
+}
+
+// DiaryMealResult groups the entries of one meal with their totals
+type DiaryMealResult struct {
+	MealType string             `json:"meal_type" jsonschema:"Meal: breakfast, lunch, dinner or snacks"`
+	Entries  []DiaryEntryResult `json:"entries" jsonschema:"Entries logged to this meal"`
+	Totals   NutrientsResult    `json:"totals" jsonschema:"Nutrient totals of the meal"`
+}
+
+// DiaryDayResult groups one day's meals with the daily totals
+type DiaryDayResult struct {
+	Date   string            `json:"date" jsonschema:"Diary date"`
+	Meals  []DiaryMealResult `json:"meals" jsonschema:"Meals with at least one entry, in breakfast, lunch, dinner, snacks order"`
+	Totals NutrientsResult   `json:"totals" jsonschema:"Nutrient totals of the day"`
+}
+
+// GetFoodDiaryOutput defines the output structure
+type GetFoodDiaryOutput struct {
+	StartDate    string           `json:"start_date" jsonschema:"First date returned"`
+	EndDate      string           `json:"end_date" jsonschema:"Last date returned"`
+	Days         []DiaryDayResult `json:"days" jsonschema:"Every date in the range, oldest first"`
+	Totals       NutrientsResult  `json:"totals" jsonschema:"Nutrient totals across the whole range"`
+	TotalEntries int              `json:"total_entries" jsonschema:"Number of entries returned"`
+}
+
+// RegisterGetFoodDiary registers the get_food_diary tool with the MCP server
+func (r *Registry) RegisterGetFoodDiary(server *mcp.Server, client *sparkyfitness.Client) error {
+	tool := &mcp.Tool{
+		Name:  "get_food_diary",
+		Title: "Get Food Diary",
+		Description: "📒 Read the user's food diary for a date or date range, with totals.\n\n" +
+			"**When to Use:**\n" +
+			"• 'How much protein have I had today?'\n" +
+			"• 'What did I eat for breakfast yesterday?'\n" +
+			"• Find entry_id values for update_food_entry or delete_food_entry\n\n" +
+			"**Required Input:**\n" +
+			"• Nothing: defaults to today\n" +
+			"• date / end_date: YYYY-MM-DD, a range of up to 31 days (inclusive)\n" +
+			"• meal_type: optionally restrict to breakfast, lunch, dinner or snacks\n\n" +
+			"**Output:**\n" +
+			"• days → meals → entries, each entry with food name, serving and nutrients\n" +
+			"• totals per meal, per day and for the whole range",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}
+
+	handler := func(ctx context.Context, request *mcp.CallToolRequest, input GetFoodDiaryInput) (*mcp.CallToolResult, GetFoodDiaryOutput, error) {
+		// Validate parameters
+		startDate, err := parseDiaryDate(input.Date, r.timezone())
+		if err != nil {
+			return nil, GetFoodDiaryOutput{}, err
+		}
+		endDate := startDate
+		if input.EndDate != "" {
+			if endDate, err = parseDiaryDate(input.EndDate, r.timezone()); err != nil {
+				return nil, GetFoodDiaryOutput{}, fmt.Errorf("end_date: %w", err)
+			}
+		}
+		dates, err := dateRange(startDate, endDate)
+		if err != nil {
+			return nil, GetFoodDiaryOutput{}, err
+		}
+		mealFilter := ""
+		if input.MealType != "" {
+			if mealFilter, err = normalizeMealType(input.MealType); err != nil {
+				return nil, GetFoodDiaryOutput{}, err
+			}
+		}
+
+		// Call backend API to list entries
+		var entries []sparkyfitness.FoodEntry
+		if startDate == endDate {
+			entries, err = client.ListFoodEntries(ctx, startDate)
+		} else {
+			entries, err = client.ListFoodEntriesRange(ctx, startDate, endDate)
+		}
+		if err != nil {
+			return nil, GetFoodDiaryOutput{}, backendError("list food entries", err)
+		}
+
+		// Group entries by date and meal
+		byDay := map[string]map[string][]sparkyfitness.FoodEntry{}
+		for _, entry := range entries {
+			if mealFilter != "" && entry.MealType != mealFilter {
+				continue
+			}
+			if byDay[entry.EntryDate] == nil {
+				byDay[entry.EntryDate] = map[string][]sparkyfitness.FoodEntry{}
+			}
+			byDay[entry.EntryDate][entry.MealType] = append(byDay[entry.EntryDate][entry.MealType], entry)
+		}
+
+		// Prepare output
+		output := GetFoodDiaryOutput{
+			StartDate: startDate,
+			EndDate:   endDate,
+			Days:      make([]DiaryDayResult, 0, len(dates)),
+		}
+		var rangeTotals sparkyfitness.Nutrients
+		for _, date := range dates {
+			day := DiaryDayResult{Date: date, Meals: []DiaryMealResult{}}
+			var dayTotals sparkyfitness.Nutrients
+
+			for _, mealType := range diaryMealOrder(byDay[date]) {
+				meal := DiaryMealResult{MealType: mealType}
+				var mealTotals sparkyfitness.Nutrients
+				for _, entry := range byDay[date][mealType] {
+					meal.Entries = append(meal.Entries, convertEntryToResult(entry))
+					mealTotals = mealTotals.Add(entry.LoggedNutrients())
+					output.TotalEntries++
+				}
+				meal.Totals = convertNutrientsToResult(mealTotals)
+				day.Meals = append(day.Meals, meal)
+				dayTotals = dayTotals.Add(mealTotals)
+			}
+
+			day.Totals = convertNutrientsToResult(dayTotals)
+			output.Days = append(output.Days, day)
+			rangeTotals = rangeTotals.Add(dayTotals)
+		}
+		output.Totals = convertNutrientsToResult(rangeTotals)
+
+		return nil, output, nil
+	}
+
+	mcp.AddTool(server, tool, handler)
+	return nil
+}
+
+// dateRange returns every YYYY-MM-DD date from start to end, inclusive
+func dateRange(start, end string) ([]string, error) {
+	from, _ := time.Parse(time.DateOnly, start)
+	to, _ := time.Parse(time.DateOnly, end)
+	if to.Before(from) {
+		return nil, fmt.Errorf("end_date %s is before date %s", end, start)
+	}
+
+	days := int(to.Sub(from).Hours()/24) + 1
+	if days > maxDiaryDays {
+		return nil, fmt.Errorf("date range covers %d days; request at most %d days at a time", days, maxDiaryDays)
+	}
+
+	dates := make([]string, 0, days)
+	for d := from; !d.After(to); d = d.AddDate(0, 0, 1) {
+		dates = append(dates, d.Format(time.DateOnly))
+	}
+	return dates, nil
+}
+
+// diaryMealOrder returns the meal types present in meals, known meals in
+// diary order followed by any others the backend returned
+func diaryMealOrder(meals map[string][]sparkyfitness.FoodEntry) []string {
+	var order []string
+	for _, mealType := range sparkyfitness.MealTypes {
+		if len(meals[mealType]) > 0 {
+			order = append(order, mealType)
+		}
+	}
+	var others []string
+	for mealType := range meals {
+		if !slices.Contains(sparkyfitness.MealTypes, mealType) {
+			others = append(others, mealType)
+		}
+	}
+	slices.Sort(others)
+	return append(order, others...)
+}
@@ -0,0 +1,111 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness/sparkyfitnesstest"
+)
+
+// seedDiary logs rice and eggs across two days
+func seedDiary(backend *sparkyfitnesstest.Server) (rice, egg sparkyfitness.Food) {
+	rice = backend.AddFood(sparkyfitness.Food{Name: "Rice"},
+		sparkyfitness.FoodVariant{ServingSize: 100, ServingUnit: "g", Nutrients: sparkyfitness.Nutrients{Calories: 130, Protein: 2.7, Carbs: 28.6, Fat: 0.3}},
+	)
+	egg = backend.AddFood(sparkyfitness.Food{Name: "Boiled Egg"},
+		sparkyfitness.FoodVariant{ServingSize: 1, ServingUnit: "piece", Nutrients: sparkyfitness.Nutrients{Calories: 78, Protein: 6.3, Fat: 5.3}},
+	)
+
+	backend.AddFoodEntry(sparkyfitness.FoodEntry{VariantID: egg.DefaultVariant.ID, MealType: "breakfast", Quantity: 2, EntryDate: "2025-01-15"})
+	backend.AddFoodEntry(sparkyfitness.FoodEntry{VariantID: rice.DefaultVariant.ID, MealType: "lunch", Quantity: 150, EntryDate: "2025-01-15"})
+	backend.AddFoodEntry(sparkyfitness.FoodEntry{VariantID: egg.DefaultVariant.ID, MealType: "lunch", Quantity: 1, EntryDate: "2025-01-15"})
+	backend.AddFoodEntry(sparkyfitness.FoodEntry{VariantID: rice.DefaultVariant.ID, MealType: "dinner", Quantity: 200, EntryDate: "2025-01-16"})
+
+	return rice, egg
+}
+
+func TestGetFoodDiary(t *testing.T) {
+	backend, session := newTestSession(t)
+	seedDiary(backend)
+
+	out := callTool[GetFoodDiaryOutput](t, session, "get_food_diary", map[string]any{"date": "2025-01-15"})
+
+	if len(out.Days) != 1 || out.TotalEntries != 3 {
+		t.Fatalf("output = %+v", out)
+	}
+	day := out.Days[0]
+	if len(day.Meals) != 2 || day.Meals[0].MealType != "breakfast" || day.Meals[1].MealType != "lunch" {
+		t.Fatalf("meals = %+v", day.Meals)
+	}
+
+	// breakfast: 2 eggs; lunch: 150 g rice + 1 egg
+	if got := day.Meals[0].Totals.Protein; got != 12.6 {
+		t.Errorf("breakfast protein = %v, want 12.6", got)
+	}
+	if got := day.Meals[1].Totals.Calories; got != 273 {
+		t.Errorf("lunch calories = %v, want 273", got)
+	}
+	if got := day.Totals.Calories; got != 429 {
+		t.Errorf("daily calories = %v, want 429", got)
+	}
+	if day.Meals[1].Entries[0].FoodName != "Rice" || day.Meals[1].Entries[0].Servings != 1.5 {
+		t.Errorf("lunch entry = %+v", day.Meals[1].Entries[0])
+	}
+}
+
+func TestGetFoodDiaryRange(t *testing.T) {
+	backend, session := newTestSession(t)
+	seedDiary(backend)
+
+	out := callTool[GetFoodDiaryOutput](t, session, "get_food_diary", map[string]any{
+		"date":      "2025-01-15",
+		"end_date":  "2025-01-17",
+		"meal_type": "lunch",
+	})
+
+	if len(out.Days) != 3 || out.TotalEntries != 2 {
+		t.Fatalf("output = %+v", out)
+	}
+	if len(out.Days[1].Meals) != 0 || len(out.Days[2].Meals) != 0 {
+		t.Errorf("days without lunch entries = %+v, want no meals", out.Days[1:])
+	}
+	if got := out.Totals.Calories; got != 273 {
+		t.Errorf("range calories = %v, want 273", got)
+	}
+}
+
+func TestGetFoodDiaryErrors(t *testing.T) {
+	_, session := newTestSession(t)
+
+	tests := []struct {
+		name         string
+		args         map[string]any
+		wantContains string
+	}{
+		{
+			name:         "end before start",
+			args:         map[string]any{"date": "2025-01-15", "end_date": "2025-01-14"},
+			wantContains: "before",
+		},
+		{
+			name:         "range too long",
+			args:         map[string]any{"date": "2025-01-01", "end_date": "2025-03-01"},
+			wantContains: "at most 31 days",
+		},
+		{
+			name:         "invalid meal type",
+			args:         map[string]any{"meal_type": "brunch"},
+			wantContains: "meal_type must be one of",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := callToolError(t, session, "get_food_diary", tt.args)
+			if !strings.Contains(msg, tt.wantContains) {
+				t.Errorf("error = %q, should contain %q", msg, tt.wantContains)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+)
+
+func TestGetFood(t *testing.T) {
+	backend, session := newTestSession(t)
+	food := backend.AddFood(sparkyfitness.Food{Name: "Brown Rice", IsCustom: true},
+		sparkyfitness.FoodVariant{ServingSize: 1, ServingUnit: "cup", Nutrients: sparkyfitness.Nutrients{Calories: 218, Protein: 4.5, Carbs: 45.8, Fat: 1.6}},
+		sparkyfitness.FoodVariant{ServingSize: 100, ServingUnit: "g", Nutrients: sparkyfitness.Nutrients{Calories: 112, Protein: 2.3, Carbs: 23.5, Fat: 0.8, TransFat: 0.1}, IsDefault: true},
+	)
+
+	out := callTool[GetFoodOutput](t, session, "get_food", map[string]any{"food_id": food.ID})
+
+	if out.FoodName != "Brown Rice" || out.TotalVariants != 2 {
+		t.Fatalf("output = %+v", out)
+	}
+	if !out.Variants[0].IsDefault || out.Variants[0].ServingUnit != "g" || out.Variants[0].TransFat != 0.1 {
+		t.Errorf("Variants[0] = %+v, want default 100 g variant first", out.Variants[0])
+	}
+	if out.Variants[1].ServingUnit != "cup" || out.Variants[1].Calories != 218 {
+		t.Errorf("Variants[1] = %+v, want 1 cup variant", out.Variants[1])
+	}
+}
+
+func TestGetFoodNotFound(t *testing.T) {
+	_, session := newTestSession(t)
+
+	msg := callToolError(t, session, "get_food", map[string]any{"food_id": "missing"})
+	if !strings.Contains(msg, "not found") {
+		t.Errorf("error = %q, want not found", msg)
+	}
+}
@@ -0,0 +1,101 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness/sparkyfitnesstest"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// newTestSession starts a fake backend and an MCP server with every tool
+// registered, and returns a client session connected to it in memory
+func newTestSession(t *testing.T) (*sparkyfitnesstest.Server, *mcp.ClientSession) {
+	t.Helper()
+
+	backend := sparkyfitnesstest.NewServer(t)
+	return backend, connectTestSession(t, backend, nil)
+}
+
+// connectTestSession connects an in-memory MCP client to a server whose tools
+// talk to backend. opts configures the client (e.g., elicitation handlers).
+func connectTestSession(t *testing.T, backend *sparkyfitnesstest.Server, opts *mcp.ClientOptions) *mcp.ClientSession {
+	t.Helper()
+	ctx := context.Background()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "sparkyfitness-mcp-test", Version: "test"}, nil)
+	if err := NewRegistry(backend.Config()).RegisterAll(server); err != nil {
+		t.Fatalf("RegisterAll() unexpected error: %v", err)
+	}
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("server.Connect() unexpected error: %v", err)
+	}
+	t.Cleanup(func() { serverSession.Close() })
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "test"}, opts)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client.Connect() unexpected error: %v", err)
+	}
+	t.Cleanup(func() { session.Close() })
+
+	return session
+}
+
+// callTool calls a tool and decodes its structured output into Out.
+// It fails the test if the call itself fails or the tool reports an error.
+func callTool[Out any](t *testing.T, session *mcp.ClientSession, name string, args any) Out {
+	t.Helper()
+
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: name, Arguments: args})
+	if err != nil {
+		t.Fatalf("CallTool(%s) unexpected error: %v", name, err)
+	}
+	if result.IsError {
+		t.Fatalf("CallTool(%s) returned tool error: %s", name, toolErrorText(result))
+	}
+
+	raw, err := json.Marshal(result.StructuredContent)
+	if err != nil {
+		t.Fatalf("failed to marshal structured content: %v", err)
+	}
+
+	var out Out
+	if err := json.Unmarshal(raw, &out); err != nil {
+		t.Fatalf("failed to decode %s output: %v", name, err)
+	}
+
+	return out
+}
+
+// callToolError calls a tool that is expected to fail and returns its error text
+func callToolError(t *testing.T, session *mcp.ClientSession, name string, args any) string {
+	t.Helper()
+
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: name, Arguments: args})
+	if err != nil {
+		// Input schema violations are reported as protocol errors
+		return err.Error()
+	}
+	if !result.IsError {
+		t.Fatalf("CallTool(%s) expected tool error, got success", name)
+	}
+
+	return toolErrorText(result)
+}
+
+// toolErrorText joins the text content of a tool result
+func toolErrorText(result *mcp.CallToolResult) string {
+	var parts []string
+	for _, c := range result.Content {
+		if text, ok := c.(*mcp.TextContent); ok {
+			parts = append(parts, text.Text)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
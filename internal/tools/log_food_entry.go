@@ -0,0 +1,130 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// LogFoodEntryInput defines the input parameters for the log_food_entry tool
+type LogFoodEntryInput struct {
+	FoodID    string   `json:"food_id,omitempty" jsonschema:"Food to log (from search_foods); its default variant is used unless variant_id is given"`
+	VariantID string   `json:"variant_id,omitempty" jsonschema:"Specific serving variant to log (from search_foods or get_food)"`
+	Query     string   `json:"query,omitempty" jsonschema:"Food name to search for when food_id and variant_id are unknown"`
+	Quantity  *float64 `json:"quantity,omitempty" jsonschema:"Amount eaten in the variant's serving unit (e.g., 150 for 150 g)"`
+	Servings  *float64 `json:"servings,omitempty" jsonschema:"Number of servings eaten, alternative to quantity (default: 1)"`
+	MealType  string   `json:"meal_type" jsonschema:"required,Meal to log to: breakfast, lunch, dinner or snacks"`
+	Date      string   `json:"date,omitempty" jsonschema:"Diary date in YYYY-MM-DD format (default: today)"`
+}
+
+// LogFoodEntryOutput defines the output structure
+type LogFoodEntryOutput struct {
+	EntryID   string          `json:"entry_id" jsonschema:"ID of the new diary entry"`
+	FoodID    string          `json:"food_id" jsonschema:"ID of the logged food"`
+	FoodName  string          `json:"food_name" jsonschema:"Name of the logged food"`
+	VariantID string          `json:"variant_id" jsonschema:"ID of the logged variant"`
+	MealType  string          `json:"meal_type" jsonschema:"Meal the entry was logged to"`
+	Date      string          `json:"date" jsonschema:"Diary date of the entry"`
+	Quantity  float64         `json:"quantity" jsonschema:"Amount logged"`
+	Unit      string          `json:"unit" jsonschema:"Unit of the quantity"`
+	Servings  float64         `json:"servings" jsonschema:"Number of variant servings logged"`
+	Nutrients NutrientsResult `json:"nutrients" jsonschema:"Nutrients logged for this quantity"`
+	Message   string          `json:"message" jsonschema:"Success message"`
+}
+
+// RegisterLogFoodEntry registers the log_food_entry tool with the MCP server
+func (r *Registry) RegisterLogFoodEntry(server *mcp.Server, client *sparkyfitness.Client) error {
+	tool := &mcp.Tool{
+		Name:  "log_food_entry",
+		Title: "Log Food to Diary",
+		Description: "🍽️ Log what the user ate to their SparkyFitness food diary.\n\n" +
+			"**When to Use:**\n" +
+			"• The user says what they ate (e.g., 'I had 150g of rice for lunch')\n\n" +
+			"**Choosing the Food:**\n" +
+			"• variant_id: log a specific serving variant (from get_food)\n" +
+			"• food_id: log the food's default variant\n" +
+			"• query: search by name; fails with the candidates if the name is ambiguous\n\n" +
+			"**Amount:**\n" +
+			"• quantity: amount in the variant's serving unit (e.g., 150 for a 100 g variant → 1.5 servings)\n" +
+			"• servings: number of servings instead (default: 1)\n\n" +
+			"**Required Input:**\n" +
+			"• meal_type: breakfast, lunch, dinner or snacks\n" +
+			"• One of variant_id, food_id or query\n" +
+			"• date: YYYY-MM-DD (default: today)\n\n" +
+			"**Output:**\n" +
+			"• entry_id of the diary entry\n" +
+			"• nutrients: calories and macros actually logged for this amount\n\n" +
+			"**Example:**\n" +
+			"User: 'I had 2 eggs for breakfast'\n" +
+			"→ log_food_entry(query='egg', servings=2, meal_type='breakfast')",
+	}
+
+	handler := func(ctx context.Context, request *mcp.CallToolRequest, input LogFoodEntryInput) (*mcp.CallToolResult, LogFoodEntryOutput, error) {
+		// Validate required parameters
+		mealType, err := normalizeMealType(input.MealType)
+		if err != nil {
+			return nil, LogFoodEntryOutput{}, err
+		}
+		date, err := parseDiaryDate(input.Date, r.timezone())
+		if err != nil {
+			return nil, LogFoodEntryOutput{}, err
+		}
+
+		// Find the food and variant to log
+		food, variant, err := resolveVariant(ctx, client, input.FoodID, input.VariantID, input.Query)
+		if err != nil {
+			return nil, LogFoodEntryOutput{}, err
+		}
+
+		// Quantity is expressed in the variant's serving unit
+		quantity, err := resolveQuantity(input.Quantity, input.Servings, variant)
+		if err != nil {
+			return nil, LogFoodEntryOutput{}, err
+		}
+
+		// Call backend API to log the entry
+		entry, err := client.CreateFoodEntry(ctx, &sparkyfitness.CreateFoodEntryRequest{
+			FoodID:    food.ID,
+			VariantID: variant.ID,
+			MealType:  mealType,
+			Quantity:  quantity,
+			Unit:      variant.ServingUnit,
+			EntryDate: date,
+		})
+		if err != nil {
+			return nil, LogFoodEntryOutput{}, backendError("log food entry", err)
+		}
+
+		// Compute logged nutrients from the variant in case the backend
+		// response does not carry the nutrient snapshot
+		if entry.ServingSize <= 0 {
+			entry.ServingSize = variant.ServingSize
+			entry.Nutrients = variant.Nutrients
+		}
+		nutrients := entry.LoggedNutrients()
+
+		// Prepare output
+		output := LogFoodEntryOutput{
+			EntryID:   entry.ID,
+			FoodID:    food.ID,
+			FoodName:  food.Name,
+			VariantID: variant.ID,
+			MealType:  mealType,
+			Date:      date,
+			Quantity:  quantity,
+			Unit:      variant.ServingUnit,
+			Servings:  round2(entry.Servings()),
+			Nutrients: convertNutrientsToResult(nutrients),
+			Message: fmt.Sprintf("Logged %g %s of %s to %s on %s (%.0f kcal, %.1fg protein, %.1fg carbs, %.1fg fat)",
+				quantity, variant.ServingUnit, food.Name, mealType, date,
+				nutrients.Calories, nutrients.Protein, nutrients.Carbs, nutrients.Fat),
+		}
+
+		return nil, output, nil
+	}
+
+	mcp.AddTool(server, tool, handler)
+	return nil
+}
@@ -0,0 +1,143 @@
+package tools
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness/sparkyfitnesstest"
+)
+
+func TestLogFoodEntry(t *testing.T) {
+	backend, session := newTestSession(t)
+	rice := backend.AddFood(sparkyfitness.Food{Name: "Rice"},
+		sparkyfitness.FoodVariant{ServingSize: 100, ServingUnit: "g", Nutrients: sparkyfitness.Nutrients{Calories: 130, Protein: 2.7, Carbs: 28.6, Fat: 0.3}},
+		sparkyfitness.FoodVariant{ServingSize: 1, ServingUnit: "cup", Nutrients: sparkyfitness.Nutrients{Calories: 205, Protein: 4.3, Carbs: 44.5, Fat: 0.4}},
+	)
+	backend.AddFood(sparkyfitness.Food{Name: "Boiled Egg"},
+		sparkyfitness.FoodVariant{ServingSize: 1, ServingUnit: "piece", Nutrients: sparkyfitness.Nutrients{Calories: 78, Protein: 6.3}},
+	)
+	_, riceVariants, _ := backend.Food(rice.ID)
+
+	tests := []struct {
+		name         string
+		args         map[string]any
+		wantFood     string
+		wantQuantity float64
+		wantCalories float64
+	}{
+		{
+			name:         "food_id with quantity",
+			args:         map[string]any{"food_id": rice.ID, "quantity": 150, "meal_type": "lunch", "date": "2025-01-15"},
+			wantFood:     "Rice",
+			wantQuantity: 150,
+			wantCalories: 195,
+		},
+		{
+			name:         "variant_id defaults to one serving",
+			args:         map[string]any{"variant_id": riceVariants[1].ID, "meal_type": "dinner", "date": "2025-01-15"},
+			wantFood:     "Rice",
+			wantQuantity: 1,
+			wantCalories: 205,
+		},
+		{
+			name:         "query with servings",
+			args:         map[string]any{"query": "egg", "servings": 2, "meal_type": "Snack", "date": "2025-01-15"},
+			wantFood:     "Boiled Egg",
+			wantQuantity: 2,
+			wantCalories: 156,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := callTool[LogFoodEntryOutput](t, session, "log_food_entry", tt.args)
+
+			if out.EntryID == "" || out.FoodName != tt.wantFood || out.Date != "2025-01-15" {
+				t.Errorf("output = %+v", out)
+			}
+			if out.Quantity != tt.wantQuantity {
+				t.Errorf("Quantity = %v, want %v", out.Quantity, tt.wantQuantity)
+			}
+			if out.Nutrients.Calories != tt.wantCalories {
+				t.Errorf("Nutrients.Calories = %v, want %v", out.Nutrients.Calories, tt.wantCalories)
+			}
+		})
+	}
+
+	entries := backend.Entries()
+	if len(entries) != 3 || entries[2].MealType != sparkyfitness.MealTypeSnacks {
+		t.Errorf("backend entries = %+v", entries)
+	}
+}
+
+func TestLogFoodEntryErrors(t *testing.T) {
+	backend, session := newTestSession(t)
+	backend.AddFood(sparkyfitness.Food{Name: "Brown Rice"}, sparkyfitness.FoodVariant{ServingSize: 100, ServingUnit: "g"})
+	backend.AddFood(sparkyfitness.Food{Name: "White Rice"}, sparkyfitness.FoodVariant{ServingSize: 100, ServingUnit: "g"})
+
+	tests := []struct {
+		name         string
+		args         map[string]any
+		wantContains string
+	}{
+		{
+			name:         "invalid meal type",
+			args:         map[string]any{"query": "rice", "meal_type": "brunch"},
+			wantContains: "meal_type must be one of",
+		},
+		{
+			name:         "invalid date",
+			args:         map[string]any{"query": "rice", "meal_type": "lunch", "date": "15/01/2025"},
+			wantContains: "YYYY-MM-DD",
+		},
+		{
+			name:         "no food given",
+			args:         map[string]any{"meal_type": "lunch"},
+			wantContains: "one of variant_id, food_id or query is required",
+		},
+		{
+			name:         "ambiguous query",
+			args:         map[string]any{"query": "rice", "meal_type": "lunch"},
+			wantContains: "matches several foods",
+		},
+		{
+			name:         "no match",
+			args:         map[string]any{"query": "quinoa", "meal_type": "lunch"},
+			wantContains: "no food matches",
+		},
+		{
+			name:         "quantity and servings",
+			args:         map[string]any{"query": "brown rice", "meal_type": "lunch", "quantity": 100, "servings": 1},
+			wantContains: "not both",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := callToolError(t, session, "log_food_entry", tt.args)
+			if !strings.Contains(msg, tt.wantContains) {
+				t.Errorf("error = %q, should contain %q", msg, tt.wantContains)
+			}
+		})
+	}
+
+	t.Run("variant without food_id", func(t *testing.T) {
+		backend.InjectFault(sparkyfitnesstest.Fault{
+			Method: http.MethodGet,
+			Path:   "/foods/food-variants/orphan",
+			Status: http.StatusOK,
+			Body:   `{"id":"orphan","serving_size":100,"serving_unit":"g"}`,
+		})
+
+		msg := callToolError(t, session, "log_food_entry", map[string]any{"variant_id": "orphan", "meal_type": "lunch"})
+		if !strings.Contains(msg, "has no food_id; pass food_id as well") {
+			t.Errorf("error = %q", msg)
+		}
+	})
+
+	if entries := backend.Entries(); len(entries) != 0 {
+		t.Errorf("backend entries = %d, want 0", len(entries))
+	}
+}
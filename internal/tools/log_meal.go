@@ -0,0 +1,144 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// LogMealInput defines the input parameters for the log_meal tool
+type LogMealInput struct {
+	MealID   string   `json:"meal_id" jsonschema:"required,Unique identifier of the saved meal (from search_meals or create_meal)"`
+	MealType string   `json:"meal_type" jsonschema:"required,Meal to log to: breakfast, lunch, dinner or snacks"`
+	Date     string   `json:"date,omitempty" jsonschema:"Diary date in YYYY-MM-DD format (default: today)"`
+	Portion  *float64 `json:"portion,omitempty" jsonschema:"Multiplier for every amount, e.g. 0.5 for half the meal (default: 1)"`
+}
+
+// LogMealItem reports the outcome of logging one food of the meal
+type LogMealItem struct {
+	FoodName  string          `json:"food_name" jsonschema:"Name of the food"`
+	Quantity  float64         `json:"quantity" jsonschema:"Amount logged"`
+	Unit      string          `json:"unit" jsonschema:"Unit of the quantity"`
+	Status    string          `json:"status" jsonschema:"logged or failed"`
+	EntryID   string          `json:"entry_id,omitempty" jsonschema:"ID of the new diary entry when logged"`
+	Nutrients NutrientsResult `json:"nutrients" jsonschema:"Nutrients of this food"`
+	Error     string          `json:"error,omitempty" jsonschema:"Reason logging failed"`
+}
+
+// LogMealOutput defines the output structure
+type LogMealOutput struct {
+	MealID   string          `json:"meal_id" jsonschema:"ID of the logged meal"`
+	MealName string          `json:"meal_name" jsonschema:"Name of the logged meal"`
+	MealType string          `json:"meal_type" jsonschema:"Meal the foods were logged to"`
+	Date     string          `json:"date" jsonschema:"Diary date"`
+	Items    []LogMealItem   `json:"items" jsonschema:"Outcome of every food in the meal"`
+	Logged   int             `json:"logged" jsonschema:"Number of foods logged"`
+	Failed   int             `json:"failed" jsonschema:"Number of foods that could not be logged"`
+	Totals   NutrientsResult `json:"totals" jsonschema:"Nutrients added to the diary"`
+	Message  string          `json:"message" jsonschema:"Result summary"`
+}
+
+// RegisterLogMeal registers the log_meal tool with the MCP server
+func (r *Registry) RegisterLogMeal(server *mcp.Server, client *sparkyfitness.Client) error {
+	tool := &mcp.Tool{
+		Name:  "log_meal",
+		Title: "Log Saved Meal to Diary",
+		Description: "🍱 Log every food of a saved meal to the diary in one call.\n\n" +
+			"**When to Use:**\n" +
+			"• 'Log my usual breakfast' → search_meals(name='breakfast'), then log_meal\n\n" +
+			"**Required Input:**\n" +
+			"• meal_id: from search_meals or create_meal\n" +
+			"• meal_type: breakfast, lunch, dinner or snacks\n" +
+			"• date: YYYY-MM-DD (default: today)\n" +
+			"• portion: multiplier for every amount (e.g., 0.5 for half, default: 1)\n\n" +
+			"**Output:**\n" +
+			"• items: each food logged as its own diary entry, with status (logged/failed)\n" +
+			"• totals: nutrients added to the diary",
+	}
+
+	handler := func(ctx context.Context, request *mcp.CallToolRequest, input LogMealInput) (*mcp.CallToolResult, LogMealOutput, error) {
+		// Validate required parameters
+		if input.MealID == "" {
+			return nil, LogMealOutput{}, fmt.Errorf("meal_id parameter is required")
+		}
+		mealType, err := normalizeMealType(input.MealType)
+		if err != nil {
+			return nil, LogMealOutput{}, err
+		}
+		date, err := parseDiaryDate(input.Date, r.timezone())
+		if err != nil {
+			return nil, LogMealOutput{}, err
+		}
+		portion := 1.0
+		if input.Portion != nil {
+			if *input.Portion <= 0 {
+				return nil, LogMealOutput{}, fmt.Errorf("portion must be greater than 0")
+			}
+			portion = *input.Portion
+		}
+
+		// Fetch the meal and its nutrition
+		meal, err := client.GetMeal(ctx, input.MealID)
+		if err != nil {
+			return nil, LogMealOutput{}, backendError("get meal", err)
+		}
+		summarizer := newMealSummarizer(client)
+		summary, err := summarizer.summarize(ctx, meal)
+		if err != nil {
+			return nil, LogMealOutput{}, err
+		}
+
+		// Call backend API to log every food
+		results := client.LogMeal(ctx, meal, mealType, date, portion)
+
+		// Prepare output
+		output := LogMealOutput{
+			MealID:   meal.ID,
+			MealName: meal.Name,
+			MealType: mealType,
+			Date:     date,
+			Items:    make([]LogMealItem, 0, len(results)),
+		}
+		var totals sparkyfitness.Nutrients
+		for i, result := range results {
+			item := LogMealItem{
+				FoodName: summary.Items[i].FoodName,
+				Quantity: round2(result.Food.Quantity * portion),
+				Unit:     result.Food.Unit,
+			}
+			if result.Err != nil {
+				item.Status = "failed"
+				item.Error = backendError("log food entry", result.Err).Error()
+				output.Failed++
+			} else {
+				item.Status = "logged"
+				item.EntryID = result.Entry.ID
+				// Fall back to the variant when the entry carries no nutrient snapshot
+				nutrients := result.Entry.LoggedNutrients()
+				if result.Entry.ServingSize <= 0 {
+					nutrients = variantNutrients(summarizer.variants[result.Food.VariantID], result.Food.Quantity*portion)
+				}
+				item.Nutrients = convertNutrientsToResult(nutrients)
+				totals = totals.Add(nutrients)
+				output.Logged++
+			}
+			output.Items = append(output.Items, item)
+		}
+		output.Totals = convertNutrientsToResult(totals)
+
+		if output.Failed == 0 {
+			output.Message = fmt.Sprintf("Logged meal '%s' (%d foods) to %s on %s (%.0f kcal)",
+				meal.Name, output.Logged, mealType, date, totals.Calories)
+		} else {
+			output.Message = fmt.Sprintf("Logged %d of %d foods of meal '%s'; %d failed (see items)",
+				output.Logged, len(results), meal.Name, output.Failed)
+		}
+
+		return nil, output, nil
+	}
+
+	mcp.AddTool(server, tool, handler)
+	return nil
+}
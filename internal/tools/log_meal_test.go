@@ -0,0 +1,84 @@
+package tools
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness/sparkyfitnesstest"
+)
+
+func TestLogMeal(t *testing.T) {
+	backend, session := newTestSession(t)
+	_, dinner := seedMeals(t, backend)
+	before := len(backend.Entries())
+
+	out := callTool[LogMealOutput](t, session, "log_meal", map[string]any{
+		"meal_id":   dinner.ID,
+		"meal_type": "dinner",
+		"date":      "2025-01-20",
+		"portion":   0.5,
+	})
+
+	if out.Logged != 2 || out.Failed != 0 || out.MealName != "Rice and Egg Dinner" {
+		t.Fatalf("output = %+v", out)
+	}
+	if item := out.Items[0]; item.FoodName != "Rice" || item.Quantity != 100 || item.EntryID == "" {
+		t.Errorf("items[0] = %+v", item)
+	}
+	if out.Totals.Calories != 169 {
+		t.Errorf("Totals.Calories = %v, want 169", out.Totals.Calories)
+	}
+	if got := len(backend.Entries()) - before; got != 2 {
+		t.Errorf("new diary entries = %d, want 2", got)
+	}
+}
+
+func TestLogMealPartialFailure(t *testing.T) {
+	backend, session := newTestSession(t)
+	_, dinner := seedMeals(t, backend)
+	backend.InjectFault(sparkyfitnesstest.Fault{Method: http.MethodPost, Path: "/food-entries", Status: http.StatusServiceUnavailable, Times: 1})
+
+	out := callTool[LogMealOutput](t, session, "log_meal", map[string]any{
+		"meal_id":   dinner.ID,
+		"meal_type": "dinner",
+		"date":      "2025-01-20",
+	})
+
+	if out.Logged != 1 || out.Failed != 1 || out.Items[0].Status != "failed" {
+		t.Errorf("output = %+v", out)
+	}
+	if out.Totals.Calories != 78 {
+		t.Errorf("Totals.Calories = %v, want 78 (the egg only)", out.Totals.Calories)
+	}
+}
+
+func TestLogMealDeletedFood(t *testing.T) {
+	backend, session := newTestSession(t)
+	_, dinner := seedMeals(t, backend)
+	if err := backend.NewClient(t).DeleteFood(t.Context(), dinner.Foods[0].FoodID); err != nil {
+		t.Fatalf("DeleteFood() unexpected error: %v", err)
+	}
+
+	out := callTool[LogMealOutput](t, session, "log_meal", map[string]any{
+		"meal_id":   dinner.ID,
+		"meal_type": "dinner",
+		"date":      "2025-01-20",
+	})
+
+	if out.Logged != 1 || out.Failed != 1 || out.Items[0].Status != "failed" || out.Items[0].FoodName != "Rice" {
+		t.Errorf("output = %+v", out)
+	}
+	if out.Totals.Calories != 78 {
+		t.Errorf("Totals.Calories = %v, want 78 (the egg only)", out.Totals.Calories)
+	}
+}
+
+func TestLogMealNotFound(t *testing.T) {
+	_, session := newTestSession(t)
+
+	msg := callToolError(t, session, "log_meal", map[string]any{"meal_id": "missing", "meal_type": "lunch"})
+	if !strings.Contains(msg, "not found") {
+		t.Errorf("error = %q, should contain %q", msg, "not found")
+	}
+}
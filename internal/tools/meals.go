@@ -0,0 +1,165 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+)
+
+// MealItemResult represents one component of a saved meal with its nutrients
+type MealItemResult struct {
+	FoodID    string          `json:"food_id" jsonschema:"ID of the food"`
+	FoodName  string          `json:"food_name" jsonschema:"Name of the food"`
+	VariantID string          `json:"variant_id" jsonschema:"ID of the serving variant"`
+	Quantity  float64         `json:"quantity" jsonschema:"Amount in the variant's serving unit"`
+	Unit      string          `json:"unit" jsonschema:"Unit of the quantity"`
+	Nutrients NutrientsResult `json:"nutrients" jsonschema:"Nutrients of this component"`
+	Error     string          `json:"error,omitempty" jsonschema:"Reason the component's nutrition is unknown, e.g. its food was deleted"`
+}
+
+// MealResult represents a saved meal with its aggregated nutrition
+type MealResult struct {
+	MealID      string           `json:"meal_id" jsonschema:"Unique identifier of the meal"`
+	Name        string           `json:"name" jsonschema:"Name of the meal"`
+	Description *string          `json:"description,omitempty" jsonschema:"Description of the meal"`
+	Items       []MealItemResult `json:"items" jsonschema:"Foods in the meal"`
+	Totals      NutrientsResult  `json:"totals" jsonschema:"Nutrient totals of the whole meal"`
+	Missing     int              `json:"missing,omitempty" jsonschema:"Number of foods that no longer exist and are left out of the totals"`
+}
+
+// mealSummarizer computes meal nutrition from the component variants,
+// fetching each variant and food name once per tool call
+type mealSummarizer struct {
+	client   *sparkyfitness.Client
+	variants map[string]*sparkyfitness.FoodVariant
+	names    map[string]string
+}
+
+// newMealSummarizer returns a summarizer with empty caches
+func newMealSummarizer(client *sparkyfitness.Client) *mealSummarizer {
+	return &mealSummarizer{
+		client:   client,
+		variants: map[string]*sparkyfitness.FoodVariant{},
+		names:    map[string]string{},
+	}
+}
+
+// summarize converts a meal to the tool result format, computing each
+// component's nutrients from its variant and the meal totals. Components
+// whose food or variant was deleted are reported with an error and left out
+// of the totals; other backend failures fail the whole summary.
+func (m *mealSummarizer) summarize(ctx context.Context, meal *sparkyfitness.Meal) (MealResult, error) {
+	result := MealResult{
+		MealID:      meal.ID,
+		Name:        meal.Name,
+		Description: meal.Description,
+		Items:       make([]MealItemResult, 0, len(meal.Foods)),
+	}
+
+	var totals sparkyfitness.Nutrients
+	for _, food := range meal.Foods {
+		item := MealItemResult{
+			FoodID:    food.FoodID,
+			FoodName:  food.FoodName,
+			VariantID: food.VariantID,
+			Quantity:  food.Quantity,
+			Unit:      food.Unit,
+		}
+
+		variant, err := m.variant(ctx, food.VariantID)
+		if err != nil {
+			if !sparkyfitness.IsNotFound(err) {
+				return MealResult{}, backendError("get food variant", err)
+			}
+			item.Error = "the food's serving variant no longer exists; left out of the totals"
+			result.Items = append(result.Items, item)
+			result.Missing++
+			continue
+		}
+		item.FoodName, err = m.foodName(ctx, food)
+		if err != nil {
+			if !sparkyfitness.IsNotFound(err) {
+				return MealResult{}, backendError("get food", err)
+			}
+			item.Error = "the food no longer exists; left out of the totals"
+			result.Items = append(result.Items, item)
+			result.Missing++
+			continue
+		}
+
+		nutrients := variantNutrients(variant, food.Quantity)
+		totals = totals.Add(nutrients)
+		item.Nutrients = convertNutrientsToResult(nutrients)
+		result.Items = append(result.Items, item)
+	}
+	result.Totals = convertNutrientsToResult(totals)
+
+	return result, nil
+}
+
+// remember caches a variant and its food fetched elsewhere in the tool call,
+// so summarize does not fetch them again
+func (m *mealSummarizer) remember(food *sparkyfitness.Food, variant *sparkyfitness.FoodVariant) {
+	m.variants[variant.ID] = variant
+	m.names[food.ID] = food.Name
+}
+
+// variant fetches a variant, caching the result
+func (m *mealSummarizer) variant(ctx context.Context, variantID string) (*sparkyfitness.FoodVariant, error) {
+	if v, ok := m.variants[variantID]; ok {
+		return v, nil
+	}
+	v, err := m.client.GetFoodVariant(ctx, variantID)
+	if err != nil {
+		return nil, err
+	}
+	m.variants[variantID] = v
+	return v, nil
+}
+
+// foodName returns the component's food name, fetching the food when the
+// backend did not include it
+func (m *mealSummarizer) foodName(ctx context.Context, food sparkyfitness.MealFood) (string, error) {
+	if food.FoodName != "" {
+		return food.FoodName, nil
+	}
+	if name, ok := m.names[food.FoodID]; ok {
+		return name, nil
+	}
+	f, err := m.client.GetFood(ctx, food.FoodID)
+	if err != nil {
+		return "", err
+	}
+	m.names[food.FoodID] = f.Name
+	return f.Name, nil
+}
+
+// mealWithoutNutrition converts a meal to the tool result format without
+// nutrients, for when the component variants cannot be fetched
+func mealWithoutNutrition(meal *sparkyfitness.Meal) MealResult {
+	result := MealResult{
+		MealID:      meal.ID,
+		Name:        meal.Name,
+		Description: meal.Description,
+		Items:       make([]MealItemResult, 0, len(meal.Foods)),
+	}
+	for _, food := range meal.Foods {
+		result.Items = append(result.Items, MealItemResult{
+			FoodID:    food.FoodID,
+			FoodName:  food.FoodName,
+			VariantID: food.VariantID,
+			Quantity:  food.Quantity,
+			Unit:      food.Unit,
+		})
+	}
+	return result
+}
+
+// variantNutrients returns the nutrients of quantity (in the variant's
+// serving unit) of a variant, or none when the variant is unknown
+func variantNutrients(variant *sparkyfitness.FoodVariant, quantity float64) sparkyfitness.Nutrients {
+	if variant == nil || variant.ServingSize <= 0 {
+		return sparkyfitness.Nutrients{}
+	}
+	return variant.Nutrients.Scale(quantity / variant.ServingSize)
+}
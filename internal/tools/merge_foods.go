@@ -0,0 +1,515 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/nutrition"
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// MergeFoodsInput defines the input parameters for the merge_foods tool
+type MergeFoodsInput struct {
+	TargetFoodID     string   `json:"target_food_id" jsonschema:"required,ID of the food to keep"`
+	SourceFoodIDs    []string `json:"source_food_ids" jsonschema:"required,IDs of the duplicate foods to merge into the target and delete"`
+	DryRun           bool     `json:"dry_run,omitempty" jsonschema:"If true, only list the planned operations without changing anything (default: false)"`
+	EntriesStartDate string   `json:"entries_start_date,omitempty" jsonschema:"First diary date (YYYY-MM-DD) to repoint entries in (default: the whole diary)"`
+	EntriesEndDate   string   `json:"entries_end_date,omitempty" jsonschema:"Last diary date (YYYY-MM-DD) to repoint entries in (default: the whole diary)"`
+}
+
+// MergeOperation is one step of a merge
+type MergeOperation struct {
+	Action       string `json:"action" jsonschema:"add_variant, skip_variant, repoint_entry or delete_food"`
+	SourceFoodID string `json:"source_food_id" jsonschema:"ID of the source food the operation belongs to"`
+	Description  string `json:"description" jsonschema:"What the operation does"`
+	Status       string `json:"status" jsonschema:"planned (dry run), done, failed or skipped"`
+	NewID        string `json:"new_id,omitempty" jsonschema:"ID of the variant added to the target"`
+	Error        string `json:"error,omitempty" jsonschema:"Reason the operation failed or was skipped"`
+}
+
+// MergeFoodsOutput defines the output structure
+type MergeFoodsOutput struct {
+	TargetFoodID     string           `json:"target_food_id" jsonschema:"ID of the food kept"`
+	TargetFoodName   string           `json:"target_food_name" jsonschema:"Name of the food kept"`
+	DryRun           bool             `json:"dry_run" jsonschema:"Whether this was a dry run"`
+	Merged           bool             `json:"merged" jsonschema:"Whether the merge ran (false for dry runs or if the user did not confirm)"`
+	EntriesStartDate string           `json:"entries_start_date,omitempty" jsonschema:"First diary date entries were repointed in, if limited"`
+	EntriesEndDate   string           `json:"entries_end_date,omitempty" jsonschema:"Last diary date entries were repointed in, if limited"`
+	Operations       []MergeOperation `json:"operations" jsonschema:"Every operation in execution order"`
+	VariantsAdded    int              `json:"variants_added" jsonschema:"Variants copied to the target"`
+	VariantsSkipped  int              `json:"variants_skipped" jsonschema:"Source variants with an equivalent serving on the target"`
+	EntriesRepointed int              `json:"entries_repointed" jsonschema:"Diary entries moved to the target"`
+	FoodsDeleted     int              `json:"foods_deleted" jsonschema:"Source foods deleted"`
+	Failed           int              `json:"failed" jsonschema:"Operations that failed"`
+	Message          string           `json:"message" jsonschema:"Result summary"`
+}
+
+// Merge operation actions and statuses
+const (
+	mergeAddVariant   = "add_variant"
+	mergeSkipVariant  = "skip_variant"
+	mergeRepointEntry = "repoint_entry"
+	mergeDeleteFood   = "delete_food"
+
+	mergePlanned = "planned"
+	mergeDone    = "done"
+	mergeFailed  = "failed"
+	mergeSkipped = "skipped"
+)
+
+// mergeDiaryStart and mergeDiaryEnd bound the scan for diary entries of the
+// source foods. The backend cannot list entries by food, so the whole diary
+// is scanned, in smaller date ranges when it is large: a source is only
+// deleted when none of its entries are left pointing at it.
+const (
+	mergeDiaryStart = "1900-01-01"
+	mergeDiaryEnd   = "9999-12-31"
+)
+
+// mergeTarget is the target variant a source variant maps to. VariantID is
+// empty for a variant still to be added to the target.
+type mergeTarget struct {
+	VariantID   string
+	ServingSize float64
+	ServingUnit string
+}
+
+// mergeStep is a planned operation with what is needed to execute it. Steps
+// that cannot run are planned with status skipped.
+type mergeStep struct {
+	op       MergeOperation
+	variant  *sparkyfitness.FoodVariant // add_variant: source variant to copy
+	entry    *sparkyfitness.FoodEntry   // repoint_entry: entry to move
+	quantity float64                    // repoint_entry: quantity in the target unit
+	target   *mergeTarget               // add_variant, repoint_entry
+}
+
+// RegisterMergeFoods registers the merge_foods tool with the MCP server
+func (r *Registry) RegisterMergeFoods(server *mcp.Server, client *sparkyfitness.Client) error {
+	tool := &mcp.Tool{
+		Name:  "merge_foods",
+		Title: "Merge Duplicate Foods",
+		Description: "🔀 Merge duplicate foods into one: move their serving size variants and diary entries to a target food, then delete them.\n\n" +
+			"**When to Use:**\n" +
+			"• The user wants to clean up near-identical foods (find them with search_foods or find_duplicate_foods)\n\n" +
+			"**How it works:**\n" +
+			"1. Each source variant is copied to the target, unless the target already has an equivalent serving " +
+			"(same amount, e.g., '100 g' and '0.1 kg')\n" +
+			"2. Diary entries of the sources are repointed to the matching target variant " +
+			"(the backend re-snapshots their nutrients from it)\n" +
+			"3. Sources whose operations all succeeded are deleted. A source is kept when it is still used by a saved meal " +
+			"(meals cannot be edited) or by diary entries outside the date range\n\n" +
+			"**Always run with dry_run=true first** and show the planned operations to the user. " +
+			"If the client supports it, the user is also asked to confirm before anything changes.\n\n" +
+			"**Required Input:**\n" +
+			"• target_food_id: The food to keep\n" +
+			"• source_food_ids: The duplicates to merge into it\n\n" +
+			"**Optional Input:**\n" +
+			"• dry_run: Only plan (default: false)\n" +
+			"• entries_start_date / entries_end_date: Only repoint entries in this range (default: the whole diary). " +
+			"Sources with entries outside it are kept\n\n" +
+			"**Output:**\n" +
+			"• operations: every step with status (planned/done/failed/skipped) — report failures to the user\n" +
+			"• Counts of variants added/skipped, entries repointed and foods deleted",
+		Annotations: &mcp.ToolAnnotations{
+			DestructiveHint: ptr(true),
+		},
+	}
+
+	handler := func(ctx context.Context, request *mcp.CallToolRequest, input MergeFoodsInput) (*mcp.CallToolResult, MergeFoodsOutput, error) {
+		// Validate required parameters
+		if input.TargetFoodID == "" {
+			return nil, MergeFoodsOutput{}, fmt.Errorf("target_food_id parameter is required")
+		}
+		if len(input.SourceFoodIDs) == 0 {
+			return nil, MergeFoodsOutput{}, fmt.Errorf("source_food_ids must list at least one food")
+		}
+		for i, id := range input.SourceFoodIDs {
+			if id == "" {
+				return nil, MergeFoodsOutput{}, fmt.Errorf("source_food_ids[%d] is empty", i)
+			}
+			if id == input.TargetFoodID {
+				return nil, MergeFoodsOutput{}, fmt.Errorf("source_food_ids[%d] is the target food; a food cannot be merged into itself", i)
+			}
+			if slices.Contains(input.SourceFoodIDs[:i], id) {
+				return nil, MergeFoodsOutput{}, fmt.Errorf("source_food_ids[%d] is listed more than once", i)
+			}
+		}
+
+		window := entryWindow{start: mergeDiaryStart, end: mergeDiaryEnd}
+		if input.EntriesStartDate != "" {
+			if _, err := time.Parse(time.DateOnly, input.EntriesStartDate); err != nil {
+				return nil, MergeFoodsOutput{}, fmt.Errorf("entries_start_date must be in YYYY-MM-DD format (got %q)", input.EntriesStartDate)
+			}
+			window.start = input.EntriesStartDate
+		}
+		if input.EntriesEndDate != "" {
+			if _, err := time.Parse(time.DateOnly, input.EntriesEndDate); err != nil {
+				return nil, MergeFoodsOutput{}, fmt.Errorf("entries_end_date must be in YYYY-MM-DD format (got %q)", input.EntriesEndDate)
+			}
+			window.end = input.EntriesEndDate
+		}
+		if window.start > window.end {
+			return nil, MergeFoodsOutput{}, fmt.Errorf("entries_start_date must not be after entries_end_date")
+		}
+
+		// Fetch the target, the sources, the diary entries and the saved
+		// meals to plan the merge
+		target, err := client.GetFood(ctx, input.TargetFoodID)
+		if err != nil {
+			return nil, MergeFoodsOutput{}, backendError("get target food", err)
+		}
+		targetVariants, err := client.ListFoodVariants(ctx, target.ID)
+		if err != nil {
+			return nil, MergeFoodsOutput{}, backendError("list target food variants", err)
+		}
+		entries, err := client.ListFoodEntriesMatching(ctx, mergeDiaryStart, mergeDiaryEnd, func(e *sparkyfitness.FoodEntry) bool {
+			return slices.Contains(input.SourceFoodIDs, e.FoodID)
+		})
+		if err != nil {
+			return nil, MergeFoodsOutput{}, backendError("list food entries", err)
+		}
+		meals, err := mealsUsingFoods(ctx, client, input.SourceFoodIDs)
+		if err != nil {
+			return nil, MergeFoodsOutput{}, err
+		}
+
+		targets := make([]*mergeTarget, 0, len(targetVariants))
+		for _, v := range targetVariants {
+			targets = append(targets, &mergeTarget{VariantID: v.ID, ServingSize: v.ServingSize, ServingUnit: v.ServingUnit})
+		}
+
+		var steps []*mergeStep
+		for _, sourceID := range input.SourceFoodIDs {
+			source, err := client.GetFood(ctx, sourceID)
+			if err != nil {
+				return nil, MergeFoodsOutput{}, backendError("get source food "+sourceID, err)
+			}
+			variants, err := client.ListFoodVariants(ctx, source.ID)
+			if err != nil {
+				return nil, MergeFoodsOutput{}, backendError("list source food variants", err)
+			}
+			steps = append(steps, planSourceMerge(source, variants, entries, window, meals[source.ID], &targets)...)
+		}
+
+		output := MergeFoodsOutput{
+			TargetFoodID:     target.ID,
+			TargetFoodName:   target.Name,
+			DryRun:           input.DryRun,
+			EntriesStartDate: input.EntriesStartDate,
+			EntriesEndDate:   input.EntriesEndDate,
+		}
+
+		if input.DryRun {
+			collectMergeOperations(steps, &output)
+			output.Message = fmt.Sprintf("Dry run: merging %d food(s) into '%s' would add %d variant(s), skip %d equivalent variant(s), "+
+				"repoint %d diary entr(ies) and delete %d food(s). Nothing was changed.",
+				len(input.SourceFoodIDs), target.Name, output.VariantsAdded, output.VariantsSkipped, output.EntriesRepointed, output.FoodsDeleted)
+			return nil, output, nil
+		}
+
+		// Ask the user before changing anything
+		confirmed, err := confirmAction(ctx, request, fmt.Sprintf(
+			"Merge %d food(s) into '%s' and delete them? Their variants and diary entries%s move to '%s'. This cannot be undone.",
+			len(input.SourceFoodIDs), target.Name, window.describe(), target.Name))
+		if err != nil {
+			return nil, MergeFoodsOutput{}, err
+		}
+		if !confirmed {
+			output.Operations = []MergeOperation{}
+			output.Message = fmt.Sprintf("Foods were not merged into '%s': the user did not confirm", target.Name)
+			return nil, output, nil
+		}
+
+		// Call backend API to run the steps
+		executeMerge(ctx, client, target.ID, steps)
+
+		// Prepare output
+		output.Merged = true
+		collectMergeOperations(steps, &output)
+		output.Message = fmt.Sprintf("Merged into '%s': added %d variant(s), skipped %d equivalent variant(s), "+
+			"repointed %d diary entr(ies) and deleted %d food(s)",
+			target.Name, output.VariantsAdded, output.VariantsSkipped, output.EntriesRepointed, output.FoodsDeleted)
+		if output.Failed > 0 {
+			output.Message += fmt.Sprintf("; %d operation(s) failed, see operations", output.Failed)
+		}
+
+		return nil, output, nil
+	}
+
+	mcp.AddTool(server, tool, handler)
+	return nil
+}
+
+// entryWindow is the inclusive range of diary dates to repoint entries in
+type entryWindow struct {
+	start, end string
+}
+
+// contains reports whether date falls in the window
+func (w entryWindow) contains(date string) bool {
+	return date >= w.start && date <= w.end
+}
+
+// describe returns the window for messages, empty when it is the whole diary
+func (w entryWindow) describe() string {
+	switch {
+	case w.start == mergeDiaryStart && w.end == mergeDiaryEnd:
+		return ""
+	case w.start == mergeDiaryStart:
+		return " up to " + w.end
+	case w.end == mergeDiaryEnd:
+		return " from " + w.start
+	default:
+		return fmt.Sprintf(" from %s to %s", w.start, w.end)
+	}
+}
+
+// mealsUsingFoods returns the names of the saved meals using each of the
+// given foods, keyed by food ID. Meals cannot be edited, so a food still
+// used by one must not be deleted.
+func mealsUsingFoods(ctx context.Context, client *sparkyfitness.Client, foodIDs []string) (map[string][]string, error) {
+	meals, err := client.SearchMeals(ctx, "")
+	if err != nil {
+		return nil, backendError("search meals", err)
+	}
+
+	used := map[string][]string{}
+	for _, meal := range meals {
+		// Fetch the components when the search did not include them
+		if len(meal.Foods) == 0 {
+			full, err := client.GetMeal(ctx, meal.ID)
+			if err != nil {
+				return nil, backendError("get meal", err)
+			}
+			meal = *full
+		}
+		for _, id := range foodIDs {
+			if slices.ContainsFunc(meal.Foods, func(f sparkyfitness.MealFood) bool { return f.FoodID == id }) {
+				used[id] = append(used[id], meal.Name)
+			}
+		}
+	}
+	return used, nil
+}
+
+// planSourceMerge plans merging one source food: its variants are mapped to
+// an equivalent target variant or copied (and the copy added to targets so
+// later sources reuse it), its entries in the window are repointed and the
+// food deleted. The food is kept when a reference to it would be left
+// behind: an entry that cannot be repointed or lies outside the window, or a
+// saved meal using it.
+func planSourceMerge(source *sparkyfitness.Food, variants []sparkyfitness.FoodVariant, entries []sparkyfitness.FoodEntry, window entryWindow, meals []string, targets *[]*mergeTarget) []*mergeStep {
+	var steps []*mergeStep
+	mapped := map[string]*mergeTarget{}
+	var kept []string
+	blocked := false
+	outside := 0
+
+	for i := range variants {
+		v := &variants[i]
+		serving := fmt.Sprintf("%g %s", v.ServingSize, v.ServingUnit)
+
+		if t := findEquivalentServing(*targets, v.ServingSize, v.ServingUnit); t != nil {
+			mapped[v.ID] = t
+			steps = append(steps, &mergeStep{op: MergeOperation{
+				Action:       mergeSkipVariant,
+				SourceFoodID: source.ID,
+				Description:  fmt.Sprintf("Skip '%s' %s: the target already has %g %s", source.Name, serving, t.ServingSize, t.ServingUnit),
+			}})
+			continue
+		}
+
+		t := &mergeTarget{ServingSize: v.ServingSize, ServingUnit: v.ServingUnit}
+		*targets = append(*targets, t)
+		mapped[v.ID] = t
+		steps = append(steps, &mergeStep{
+			op: MergeOperation{
+				Action:       mergeAddVariant,
+				SourceFoodID: source.ID,
+				Description:  fmt.Sprintf("Copy '%s' %s (%g kcal) to the target", source.Name, serving, v.Calories),
+			},
+			variant: v,
+			target:  t,
+		})
+	}
+
+	for i := range entries {
+		entry := &entries[i]
+		if entry.FoodID != source.ID {
+			continue
+		}
+		if !window.contains(entry.EntryDate) {
+			outside++
+			continue
+		}
+		step := &mergeStep{
+			op: MergeOperation{
+				Action:       mergeRepointEntry,
+				SourceFoodID: source.ID,
+				Description: fmt.Sprintf("Repoint %s %s entry of %g %s '%s' (entry_id=%s)",
+					entry.EntryDate, entry.MealType, entry.Quantity, entry.Unit, entry.FoodName, entry.ID),
+			},
+			entry:  entry,
+			target: mapped[entry.VariantID],
+		}
+		steps = append(steps, step)
+
+		if step.target == nil {
+			step.op.Status = mergeSkipped
+			step.op.Error = "the entry's variant no longer exists on the source food"
+			blocked = true
+			continue
+		}
+		quantity, err := nutrition.Convert(entry.Quantity, entry.Unit, step.target.ServingUnit, 0)
+		if err != nil {
+			step.op.Status = mergeSkipped
+			step.op.Error = err.Error()
+			blocked = true
+			continue
+		}
+		step.quantity = round2(quantity)
+	}
+
+	deleteStep := &mergeStep{op: MergeOperation{
+		Action:       mergeDeleteFood,
+		SourceFoodID: source.ID,
+		Description:  fmt.Sprintf("Delete '%s' and its %d variant(s)", source.Name, len(variants)),
+	}}
+	if blocked {
+		kept = append(kept, "some of its diary entries cannot be repointed")
+	}
+	if outside > 0 {
+		kept = append(kept, fmt.Sprintf("%d of its diary entr(ies) are outside the entries_start_date/entries_end_date range; "+
+			"leave both empty to repoint them", outside))
+	}
+	if len(meals) > 0 {
+		kept = append(kept, fmt.Sprintf("saved meal(s) '%s' use it and cannot be changed; recreate them with the target food first",
+			strings.Join(meals, "', '")))
+	}
+	if len(kept) > 0 {
+		deleteStep.op.Status = mergeSkipped
+		deleteStep.op.Error = "kept because " + strings.Join(kept, "; ")
+	}
+	return append(steps, deleteStep)
+}
+
+// findEquivalentServing returns the target with the same serving amount,
+// comparing convertible units (e.g., 100 g and 0.1 kg) after conversion
+func findEquivalentServing(targets []*mergeTarget, size float64, unit string) *mergeTarget {
+	for _, t := range targets {
+		amount, err := nutrition.Convert(size, unit, t.ServingUnit, 0)
+		if err == nil && math.Abs(amount-t.ServingSize) <= 0.01*t.ServingSize {
+			return t
+		}
+	}
+	return nil
+}
+
+// executeMerge runs the planned steps in order. A source food is only
+// deleted when all of its other steps succeeded, and an entry is only
+// repointed when its target variant exists.
+func executeMerge(ctx context.Context, client *sparkyfitness.Client, targetFoodID string, steps []*mergeStep) {
+	failed := map[string]bool{}
+	fail := func(step *mergeStep, status string, err error) {
+		step.op.Status = status
+		step.op.Error = err.Error()
+		failed[step.op.SourceFoodID] = true
+	}
+
+	for _, step := range steps {
+		// Steps skipped when planning block deleting their source
+		if step.op.Status == mergeSkipped {
+			failed[step.op.SourceFoodID] = true
+			continue
+		}
+
+		switch step.op.Action {
+		case mergeSkipVariant:
+			step.op.Status = mergeDone
+
+		case mergeAddVariant:
+			v := step.variant
+			resp, err := client.AddFoodVariant(ctx, &sparkyfitness.AddFoodVariantRequest{
+				FoodID:          targetFoodID,
+				ServingSize:     v.ServingSize,
+				ServingUnit:     v.ServingUnit,
+				Nutrients:       v.Nutrients,
+				GlycemicIndex:   v.GlycemicIndex,
+				CustomNutrients: v.CustomNutrients,
+			})
+			if err != nil {
+				fail(step, mergeFailed, backendError("add food variant", err))
+				continue
+			}
+			step.target.VariantID = resp.ID
+			step.op.Status = mergeDone
+			step.op.NewID = resp.ID
+
+		case mergeRepointEntry:
+			entry := step.entry
+			if step.target.VariantID == "" {
+				fail(step, mergeSkipped, fmt.Errorf("the entry's variant was not copied to the target"))
+				continue
+			}
+			_, err := client.UpdateFoodEntry(ctx, entry.ID, &sparkyfitness.UpdateFoodEntryRequest{
+				FoodID:    targetFoodID,
+				VariantID: step.target.VariantID,
+				MealType:  entry.MealType,
+				Quantity:  step.quantity,
+				Unit:      step.target.ServingUnit,
+				EntryDate: entry.EntryDate,
+			})
+			if err != nil {
+				fail(step, mergeFailed, backendError("update food entry", err))
+				continue
+			}
+			step.op.Status = mergeDone
+
+		case mergeDeleteFood:
+			if failed[step.op.SourceFoodID] {
+				step.op.Status = mergeSkipped
+				step.op.Error = "not deleted because other operations for this food failed"
+				continue
+			}
+			if err := client.DeleteFood(ctx, step.op.SourceFoodID); err != nil {
+				fail(step, mergeFailed, backendError("delete food", err))
+				continue
+			}
+			step.op.Status = mergeDone
+		}
+	}
+}
+
+// collectMergeOperations sets the operations of output from the steps,
+// marking unexecuted ones as planned, and counts them
+func collectMergeOperations(steps []*mergeStep, output *MergeFoodsOutput) {
+	output.Operations = make([]MergeOperation, 0, len(steps))
+	for _, step := range steps {
+		op := step.op
+		if op.Status == "" {
+			op.Status = mergePlanned
+		}
+		output.Operations = append(output.Operations, op)
+
+		if op.Status == mergeFailed || op.Status == mergeSkipped {
+			output.Failed++
+			continue
+		}
+		switch op.Action {
+		case mergeAddVariant:
+			output.VariantsAdded++
+		case mergeSkipVariant:
+			output.VariantsSkipped++
+		case mergeRepointEntry:
+			output.EntriesRepointed++
+		case mergeDeleteFood:
+			output.FoodsDeleted++
+		}
+	}
+}
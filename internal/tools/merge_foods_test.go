@@ -0,0 +1,194 @@
+package tools
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness/sparkyfitnesstest"
+)
+
+// addMergeFixture adds a target oat milk with a 100 ml serving and two
+// duplicates: one with 0.1 l and 250 ml servings, one with a 250 ml serving,
+// each logged once today
+func addMergeFixture(t *testing.T, backend *sparkyfitnesstest.Server) (target, dupA, dupB sparkyfitness.Food) {
+	t.Helper()
+
+	milk := func(size float64, unit string, kcal float64) sparkyfitness.FoodVariant {
+		return sparkyfitness.FoodVariant{ServingSize: size, ServingUnit: unit, Nutrients: sparkyfitness.Nutrients{Calories: kcal, Protein: 1, Carbs: 6.5, Fat: 1.5}}
+	}
+	target = backend.AddFood(sparkyfitness.Food{Name: "Oat Milk"}, milk(100, "ml", 46))
+	dupA = backend.AddFood(sparkyfitness.Food{Name: "Oatmilk"}, milk(0.1, "l", 45), milk(250, "ml", 113))
+	dupB = backend.AddFood(sparkyfitness.Food{Name: "Oat milk"}, milk(250, "ml", 115))
+
+	today := time.Now().Format(time.DateOnly)
+	_, variantsA, _ := backend.Food(dupA.ID)
+	_, variantsB, _ := backend.Food(dupB.ID)
+	backend.AddFoodEntry(sparkyfitness.FoodEntry{FoodID: dupA.ID, VariantID: variantsA[0].ID, MealType: "breakfast", Quantity: 0.2, Unit: "l", EntryDate: today})
+	backend.AddFoodEntry(sparkyfitness.FoodEntry{FoodID: dupA.ID, VariantID: variantsA[1].ID, MealType: "lunch", Quantity: 250, Unit: "ml", EntryDate: today})
+	backend.AddFoodEntry(sparkyfitness.FoodEntry{FoodID: dupB.ID, VariantID: variantsB[0].ID, MealType: "dinner", Quantity: 500, Unit: "ml", EntryDate: today})
+
+	return target, dupA, dupB
+}
+
+func TestMergeFoods(t *testing.T) {
+	t.Run("dry run plans without changes", func(t *testing.T) {
+		backend, session := newTestSession(t)
+		target, dupA, dupB := addMergeFixture(t, backend)
+
+		out := callTool[MergeFoodsOutput](t, session, "merge_foods", map[string]any{
+			"target_food_id": target.ID, "source_food_ids": []string{dupA.ID, dupB.ID}, "dry_run": true,
+		})
+
+		if out.Merged || out.VariantsAdded != 1 || out.VariantsSkipped != 2 || out.EntriesRepointed != 3 || out.FoodsDeleted != 2 {
+			t.Errorf("output = %+v", out)
+		}
+		for _, op := range out.Operations {
+			if op.Status != "planned" {
+				t.Errorf("operation %+v, want planned", op)
+			}
+		}
+		if got := len(backend.Foods()); got != 3 {
+			t.Errorf("foods = %d, want 3 (unchanged)", got)
+		}
+	})
+
+	t.Run("merge moves variants and entries", func(t *testing.T) {
+		backend, session := newTestSession(t)
+		target, dupA, dupB := addMergeFixture(t, backend)
+
+		out := callTool[MergeFoodsOutput](t, session, "merge_foods", map[string]any{
+			"target_food_id": target.ID, "source_food_ids": []string{dupA.ID, dupB.ID},
+		})
+
+		if !out.Merged || out.Failed != 0 || out.FoodsDeleted != 2 || out.EntriesRepointed != 3 {
+			t.Fatalf("output = %+v", out)
+		}
+		if got := len(backend.Foods()); got != 1 {
+			t.Errorf("foods = %d, want only the target", got)
+		}
+
+		_, variants, _ := backend.Food(target.ID)
+		if len(variants) != 2 {
+			t.Fatalf("target variants = %+v, want 100 ml and 250 ml", variants)
+		}
+		byUnit := map[float64]string{}
+		for _, v := range variants {
+			byUnit[v.ServingSize] = v.ID
+		}
+
+		want := map[string]struct {
+			variantID string
+			quantity  float64
+		}{
+			"breakfast": {byUnit[100], 200},
+			"lunch":     {byUnit[250], 250},
+			"dinner":    {byUnit[250], 500},
+		}
+		for _, entry := range backend.Entries() {
+			w := want[entry.MealType]
+			if entry.FoodID != target.ID || entry.VariantID != w.variantID || entry.Quantity != w.quantity || entry.Unit != "ml" {
+				t.Errorf("%s entry = %+v, want variant %s, %g ml", entry.MealType, entry, w.variantID, w.quantity)
+			}
+		}
+	})
+
+	t.Run("failed copy keeps the source", func(t *testing.T) {
+		backend, session := newTestSession(t)
+		target, dupA, dupB := addMergeFixture(t, backend)
+		backend.InjectFault(sparkyfitnesstest.Fault{Method: http.MethodPost, Path: "/foods/food-variants", Status: http.StatusInternalServerError, Body: `{"error":"db down"}`, Times: 1})
+
+		out := callTool[MergeFoodsOutput](t, session, "merge_foods", map[string]any{
+			"target_food_id": target.ID, "source_food_ids": []string{dupA.ID, dupB.ID},
+		})
+
+		// The 250 ml copy from the first source fails, so neither source's
+		// 250 ml entries can be repointed and both sources are kept
+		if out.FoodsDeleted != 0 || out.EntriesRepointed != 1 || out.Failed != 5 {
+			t.Errorf("output = %+v", out)
+		}
+		if got := len(backend.Foods()); got != 3 {
+			t.Errorf("foods = %d, want 3", got)
+		}
+	})
+
+	t.Run("old entries are repointed by default", func(t *testing.T) {
+		backend, session := newTestSession(t)
+		target, dupA, _ := addMergeFixture(t, backend)
+		_, variantsA, _ := backend.Food(dupA.ID)
+		old := backend.AddFoodEntry(sparkyfitness.FoodEntry{FoodID: dupA.ID, VariantID: variantsA[1].ID, MealType: "snacks", Quantity: 250, Unit: "ml", EntryDate: "2020-03-01"})
+
+		out := callTool[MergeFoodsOutput](t, session, "merge_foods", map[string]any{
+			"target_food_id": target.ID, "source_food_ids": []string{dupA.ID},
+		})
+
+		if out.Failed != 0 || out.FoodsDeleted != 1 || out.EntriesRepointed != 3 {
+			t.Errorf("output = %+v", out)
+		}
+		for _, entry := range backend.Entries() {
+			if entry.ID == old.ID && entry.FoodID != target.ID {
+				t.Errorf("old entry = %+v, want it repointed", entry)
+			}
+		}
+	})
+
+	t.Run("sources still referenced are kept", func(t *testing.T) {
+		backend, session := newTestSession(t)
+		target, dupA, dupB := addMergeFixture(t, backend)
+		_, variantsA, _ := backend.Food(dupA.ID)
+		_, variantsB, _ := backend.Food(dupB.ID)
+		old := backend.AddFoodEntry(sparkyfitness.FoodEntry{FoodID: dupB.ID, VariantID: variantsB[0].ID, MealType: "snacks", Quantity: 250, Unit: "ml", EntryDate: "2020-03-01"})
+		_, err := backend.NewClient(t).CreateMeal(t.Context(), &sparkyfitness.CreateMealRequest{
+			Name:  "Porridge",
+			Foods: []sparkyfitness.MealFood{{FoodID: dupA.ID, VariantID: variantsA[1].ID, Quantity: 250, Unit: "ml"}},
+		})
+		if err != nil {
+			t.Fatalf("CreateMeal() unexpected error: %v", err)
+		}
+
+		out := callTool[MergeFoodsOutput](t, session, "merge_foods", map[string]any{
+			"target_food_id":     target.ID,
+			"source_food_ids":    []string{dupA.ID, dupB.ID},
+			"entries_start_date": time.Now().AddDate(0, 0, -30).Format(time.DateOnly),
+		})
+
+		// Today's entries are repointed, but the meal and the old entry
+		// would be left pointing at deleted foods
+		if out.FoodsDeleted != 0 || out.EntriesRepointed != 3 {
+			t.Errorf("output = %+v", out)
+		}
+		reasons := map[string]string{}
+		for _, op := range out.Operations {
+			if op.Action == "delete_food" {
+				if op.Status != "skipped" {
+					t.Errorf("delete operation = %+v, want skipped", op)
+				}
+				reasons[op.SourceFoodID] = op.Error
+			}
+		}
+		if !strings.Contains(reasons[dupA.ID], "'Porridge'") || !strings.Contains(reasons[dupB.ID], "1 of its diary entr(ies)") {
+			t.Errorf("reasons = %q", reasons)
+		}
+		if got := len(backend.Foods()); got != 3 {
+			t.Errorf("foods = %d, want 3", got)
+		}
+		for _, entry := range backend.Entries() {
+			if entry.ID == old.ID && entry.FoodID != dupB.ID {
+				t.Errorf("old entry = %+v, want it untouched", entry)
+			}
+		}
+	})
+
+	t.Run("target among sources", func(t *testing.T) {
+		backend, session := newTestSession(t)
+		target, _, _ := addMergeFixture(t, backend)
+
+		msg := callToolError(t, session, "merge_foods", map[string]any{"target_food_id": target.ID, "source_food_ids": []string{target.ID}})
+
+		if !strings.Contains(msg, "cannot be merged into itself") {
+			t.Errorf("error = %q", msg)
+		}
+	})
+}
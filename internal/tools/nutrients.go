@@ -0,0 +1,186 @@
+package tools
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/nutrition"
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+)
+
+// OptionalNutrientsInput defines the optional nutrient inputs shared by the
+// food tools. Omitted (nil) fields are left unchanged when applied.
+type OptionalNutrientsInput struct {
+	SaturatedFat       *float64 `json:"saturated_fat,omitempty" jsonschema:"Saturated fat in grams"`
+	PolyunsaturatedFat *float64 `json:"polyunsaturated_fat,omitempty" jsonschema:"Polyunsaturated fat in grams"`
+	MonounsaturatedFat *float64 `json:"monounsaturated_fat,omitempty" jsonschema:"Monounsaturated fat in grams"`
+	TransFat           *float64 `json:"trans_fat,omitempty" jsonschema:"Trans fat in grams"`
+	Cholesterol        *float64 `json:"cholesterol,omitempty" jsonschema:"Cholesterol in milligrams"`
+	Sodium             *float64 `json:"sodium,omitempty" jsonschema:"Sodium in milligrams"`
+	Potassium          *float64 `json:"potassium,omitempty" jsonschema:"Potassium in milligrams"`
+	DietaryFiber       *float64 `json:"dietary_fiber,omitempty" jsonschema:"Dietary fiber in grams"`
+	Sugars             *float64 `json:"sugars,omitempty" jsonschema:"Sugars in grams"`
+	VitaminA           *float64 `json:"vitamin_a,omitempty" jsonschema:"Vitamin A in micrograms (mcg RAE)"`
+	VitaminC           *float64 `json:"vitamin_c,omitempty" jsonschema:"Vitamin C in milligrams"`
+	Calcium            *float64 `json:"calcium,omitempty" jsonschema:"Calcium in milligrams"`
+	Iron               *float64 `json:"iron,omitempty" jsonschema:"Iron in milligrams"`
+}
+
+// applyTo copies every provided nutrient onto dst and returns the JSON names
+// of the fields that were set
+func (n *OptionalNutrientsInput) applyTo(dst *sparkyfitness.Nutrients) []string {
+	var set []string
+	apply := func(name string, src *float64, field *float64) {
+		if src != nil {
+			*field = *src
+			set = append(set, name)
+		}
+	}
+
+	apply("saturated_fat", n.SaturatedFat, &dst.SaturatedFat)
+	apply("polyunsaturated_fat", n.PolyunsaturatedFat, &dst.PolyunsaturatedFat)
+	apply("monounsaturated_fat", n.MonounsaturatedFat, &dst.MonounsaturatedFat)
+	apply("trans_fat", n.TransFat, &dst.TransFat)
+	apply("cholesterol", n.Cholesterol, &dst.Cholesterol)
+	apply("sodium", n.Sodium, &dst.Sodium)
+	apply("potassium", n.Potassium, &dst.Potassium)
+	apply("dietary_fiber", n.DietaryFiber, &dst.DietaryFiber)
+	apply("sugars", n.Sugars, &dst.Sugars)
+	apply("vitamin_a", n.VitaminA, &dst.VitaminA)
+	apply("vitamin_c", n.VitaminC, &dst.VitaminC)
+	apply("calcium", n.Calcium, &dst.Calcium)
+	apply("iron", n.Iron, &dst.Iron)
+
+	return set
+}
+
+// LabelConversionsInput defines nutrient inputs in label units that need
+// converting: EU salt and US %DV values
+type LabelConversionsInput struct {
+	Salt        *float64           `json:"salt,omitempty" jsonschema:"Salt in grams as on EU labels; converted to sodium (1 g salt = 400 mg sodium)"`
+	DailyValues map[string]float64 `json:"daily_values,omitempty" jsonschema:"Percent daily values as on US labels, keyed by nutrient (vitamin_a, vitamin_c, calcium, iron, potassium, sodium, cholesterol, saturated_fat, dietary_fiber), e.g. {\"calcium\": 15} for 15%; converted to absolute amounts"`
+}
+
+// applyTo converts the label values onto dst and returns a description of
+// each conversion. set holds the nutrients already given as absolute
+// amounts; giving one of them as %DV too is an error, and salt is
+// cross-checked against a given sodium amount instead of replacing it.
+func (l *LabelConversionsInput) applyTo(dst *sparkyfitness.Nutrients, set []string) ([]string, error) {
+	var conversions []string
+
+	if l.Salt != nil {
+		if *l.Salt < 0 {
+			return nil, fmt.Errorf("salt must not be negative")
+		}
+		if slices.Contains(set, "sodium") {
+			if err := nutrition.CheckSaltSodium(*l.Salt, dst.Sodium); err != nil {
+				return nil, fmt.Errorf("salt and sodium do not match (%w); re-check the label", err)
+			}
+			conversions = append(conversions, fmt.Sprintf("salt: %g g matches %g mg sodium; saved the sodium value", *l.Salt, dst.Sodium))
+		} else {
+			dst.Sodium = round2(nutrition.SaltToSodium(*l.Salt))
+			set = append(set, "sodium")
+			conversions = append(conversions, fmt.Sprintf("salt: converted %g g salt to %g mg sodium", *l.Salt, dst.Sodium))
+		}
+	}
+
+	// Convert in a stable order so the output is deterministic
+	fields := make([]string, 0, len(l.DailyValues))
+	for field := range l.DailyValues {
+		fields = append(fields, field)
+	}
+	slices.Sort(fields)
+
+	for _, field := range fields {
+		percent := l.DailyValues[field]
+		if percent < 0 {
+			return nil, fmt.Errorf("daily_values.%s must not be negative", field)
+		}
+		if slices.Contains(set, field) {
+			return nil, fmt.Errorf("%s is given both as an amount and in daily_values; provide only one", field)
+		}
+		amount, dv, err := nutrition.FromPercentDV(field, percent)
+		if err != nil {
+			return nil, fmt.Errorf("daily_values: %w", err)
+		}
+		target, _ := nutrition.NutrientField(dst, field)
+		*target = round2(amount)
+		conversions = append(conversions, fmt.Sprintf("%s: converted %g%% DV to %g %s (daily value %g %s)",
+			field, percent, *target, dv.Unit, dv.Amount, dv.Unit))
+	}
+
+	return conversions, nil
+}
+
+// resolveCalories returns the calories to save from the calories (kcal) and
+// energy_kj inputs, along with a description of any conversion applied
+func resolveCalories(kcal, kj *float64) (float64, []string, error) {
+	if kcal == nil && kj == nil {
+		return 0, nil, fmt.Errorf("calories or energy_kj is required")
+	}
+	calories, err := nutrition.ResolveEnergy(kcal, kj)
+	if err != nil {
+		return 0, nil, fmt.Errorf("calories and energy_kj do not match (%w); re-check which label value is kcal and which is kJ", err)
+	}
+
+	var conversions []string
+	switch {
+	case kcal == nil:
+		calories = round2(calories)
+		conversions = append(conversions, fmt.Sprintf("energy: converted %g kJ to %g kcal", *kj, calories))
+	case kj != nil:
+		conversions = append(conversions, fmt.Sprintf("energy: %g kJ matches %g kcal; saved the kcal value", *kj, calories))
+	}
+	return calories, conversions, nil
+}
+
+// NutrientsResult reports computed nutrient amounts (e.g., what was logged)
+type NutrientsResult struct {
+	Calories           float64 `json:"calories" jsonschema:"Calories"`
+	Protein            float64 `json:"protein" jsonschema:"Protein in grams"`
+	Carbs              float64 `json:"carbs" jsonschema:"Carbohydrates in grams"`
+	Fat                float64 `json:"fat" jsonschema:"Fat in grams"`
+	SaturatedFat       float64 `json:"saturated_fat,omitempty" jsonschema:"Saturated fat in grams"`
+	PolyunsaturatedFat float64 `json:"polyunsaturated_fat,omitempty" jsonschema:"Polyunsaturated fat in grams"`
+	MonounsaturatedFat float64 `json:"monounsaturated_fat,omitempty" jsonschema:"Monounsaturated fat in grams"`
+	TransFat           float64 `json:"trans_fat,omitempty" jsonschema:"Trans fat in grams"`
+	Cholesterol        float64 `json:"cholesterol,omitempty" jsonschema:"Cholesterol in milligrams"`
+	Sodium             float64 `json:"sodium,omitempty" jsonschema:"Sodium in milligrams"`
+	Potassium          float64 `json:"potassium,omitempty" jsonschema:"Potassium in milligrams"`
+	DietaryFiber       float64 `json:"dietary_fiber,omitempty" jsonschema:"Dietary fiber in grams"`
+	Sugars             float64 `json:"sugars,omitempty" jsonschema:"Sugars in grams"`
+	VitaminA           float64 `json:"vitamin_a,omitempty" jsonschema:"Vitamin A in micrograms (mcg RAE)"`
+	VitaminC           float64 `json:"vitamin_c,omitempty" jsonschema:"Vitamin C in milligrams"`
+	Calcium            float64 `json:"calcium,omitempty" jsonschema:"Calcium in milligrams"`
+	Iron               float64 `json:"iron,omitempty" jsonschema:"Iron in milligrams"`
+}
+
+// convertNutrientsToResult converts computed nutrients to the tool result
+// format, rounded to two decimals to hide floating point noise
+func convertNutrientsToResult(n sparkyfitness.Nutrients) NutrientsResult {
+	return NutrientsResult{
+		Calories:           round2(n.Calories),
+		Protein:            round2(n.Protein),
+		Carbs:              round2(n.Carbs),
+		Fat:                round2(n.Fat),
+		SaturatedFat:       round2(n.SaturatedFat),
+		PolyunsaturatedFat: round2(n.PolyunsaturatedFat),
+		MonounsaturatedFat: round2(n.MonounsaturatedFat),
+		TransFat:           round2(n.TransFat),
+		Cholesterol:        round2(n.Cholesterol),
+		Sodium:             round2(n.Sodium),
+		Potassium:          round2(n.Potassium),
+		DietaryFiber:       round2(n.DietaryFiber),
+		Sugars:             round2(n.Sugars),
+		VitaminA:           round2(n.VitaminA),
+		VitaminC:           round2(n.VitaminC),
+		Calcium:            round2(n.Calcium),
+		Iron:               round2(n.Iron),
+	}
+}
+
+// round2 rounds v to two decimals
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -2,6 +2,7 @@ package tools
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/chickenzord/sparkyfitness-mcp/internal/config"
 	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
@@ -21,6 +22,14 @@ func NewRegistry(cfg *config.Config) *Registry {
 	}
 }
 
+// timezone returns the location diary tools use for today's date
+func (r *Registry) timezone() *time.Location {
+	if r.config.Timezone == nil {
+		return time.Local
+	}
+	return r.config.Timezone
+}
+
 // RegisterAll registers all available tools with the MCP server
 func (r *Registry) RegisterAll(server *mcp.Server) error {
 	// Initialize SparkyFitness API client
@@ -35,6 +44,16 @@ func (r *Registry) RegisterAll(server *mcp.Server) error {
 		return fmt.Errorf("failed to register search_foods: %w", err)
 	}
 
+	// Register get_food tool
+	if err := r.RegisterGetFood(server, client); err != nil {
+		return fmt.Errorf("failed to register get_food: %w", err)
+	}
+
+	// Register find_duplicate_foods tool
+	if err := r.RegisterFindDuplicateFoods(server, client); err != nil {
+		return fmt.Errorf("failed to register find_duplicate_foods: %w", err)
+	}
+
 	// Register add_food_variant tool (sfmcp-248.3)
 	if err := r.RegisterAddFoodVariant(server, client); err != nil {
 		return fmt.Errorf("failed to register add_food_variant: %w", err)
@@ -45,5 +64,85 @@ func (r *Registry) RegisterAll(server *mcp.Server) error {
 		return fmt.Errorf("failed to register create_food_variant: %w", err)
 	}
 
+	// Register batch_create_foods tool
+	if err := r.RegisterBatchCreateFoods(server, client); err != nil {
+		return fmt.Errorf("failed to register batch_create_foods: %w", err)
+	}
+
+	// Register update_food tool
+	if err := r.RegisterUpdateFood(server, client); err != nil {
+		return fmt.Errorf("failed to register update_food: %w", err)
+	}
+
+	// Register update_food_variant tool
+	if err := r.RegisterUpdateFoodVariant(server, client); err != nil {
+		return fmt.Errorf("failed to register update_food_variant: %w", err)
+	}
+
+	// Register set_default_variant tool
+	if err := r.RegisterSetDefaultVariant(server, client); err != nil {
+		return fmt.Errorf("failed to register set_default_variant: %w", err)
+	}
+
+	// Register delete_food tool
+	if err := r.RegisterDeleteFood(server, client); err != nil {
+		return fmt.Errorf("failed to register delete_food: %w", err)
+	}
+
+	// Register delete_food_variant tool
+	if err := r.RegisterDeleteFoodVariant(server, client); err != nil {
+		return fmt.Errorf("failed to register delete_food_variant: %w", err)
+	}
+
+	// Register merge_foods tool
+	if err := r.RegisterMergeFoods(server, client); err != nil {
+		return fmt.Errorf("failed to register merge_foods: %w", err)
+	}
+
+	// Register log_food_entry tool
+	if err := r.RegisterLogFoodEntry(server, client); err != nil {
+		return fmt.Errorf("failed to register log_food_entry: %w", err)
+	}
+
+	// Register get_food_diary tool
+	if err := r.RegisterGetFoodDiary(server, client); err != nil {
+		return fmt.Errorf("failed to register get_food_diary: %w", err)
+	}
+
+	// Register update_food_entry tool
+	if err := r.RegisterUpdateFoodEntry(server, client); err != nil {
+		return fmt.Errorf("failed to register update_food_entry: %w", err)
+	}
+
+	// Register delete_food_entry tool
+	if err := r.RegisterDeleteFoodEntry(server, client); err != nil {
+		return fmt.Errorf("failed to register delete_food_entry: %w", err)
+	}
+
+	// Register copy_food_entries tool
+	if err := r.RegisterCopyFoodEntries(server, client); err != nil {
+		return fmt.Errorf("failed to register copy_food_entries: %w", err)
+	}
+
+	// Register create_meal tool
+	if err := r.RegisterCreateMeal(server, client); err != nil {
+		return fmt.Errorf("failed to register create_meal: %w", err)
+	}
+
+	// Register search_meals tool
+	if err := r.RegisterSearchMeals(server, client); err != nil {
+		return fmt.Errorf("failed to register search_meals: %w", err)
+	}
+
+	// Register log_meal tool
+	if err := r.RegisterLogMeal(server, client); err != nil {
+		return fmt.Errorf("failed to register log_meal: %w", err)
+	}
+
+	// Register create_recipe_food tool
+	if err := r.RegisterCreateRecipeFood(server, client); err != nil {
+		return fmt.Errorf("failed to register create_recipe_food: %w", err)
+	}
+
 	return nil
 }
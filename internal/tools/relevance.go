@@ -0,0 +1,141 @@
+package tools
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	// brandMatchThreshold is the lowest brand similarity accepted as a match,
+	// high enough to reject different brands sharing a word or a prefix
+	brandMatchThreshold = 0.8
+	// nameWeight and brandWeight combine name and brand similarity into the
+	// relevance score when a brand is searched for
+	nameWeight  = 0.7
+	brandWeight = 0.3
+)
+
+// accentFolder maps accented Latin letters to their unaccented forms
+var accentFolder = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ä", "a", "ã", "a", "å", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "ö", "o", "õ", "o", "ø", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ý", "y", "ÿ", "y", "ñ", "n", "ç", "c",
+	"ß", "ss", "æ", "ae", "œ", "oe",
+)
+
+// normalizeText lowercases s, folds accents, drops apostrophes, spells out
+// "&" and turns other punctuation into spaces, so that "Trader Joe's",
+// "TRADER JOES" and "Trader-Joes" all normalize to "trader joes"
+func normalizeText(s string) string {
+	s = accentFolder.Replace(strings.ToLower(s))
+
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r == '\'' || r == '’' || r == '`':
+			// "Joe's" and "Joes" are the same brand
+		case r == '&':
+			b.WriteString(" and ")
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		default:
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// similarity scores how alike two strings are from 0 (unrelated) to 1
+// (equal after normalization). Spacing is ignored, and a string that makes
+// up whole words of the other ("Kirkland" in "Kirkland Signature") scores
+// at least 0.8, scaled by how much of it is covered. Containment within a
+// word ("Co" in "Costco") gets no such credit.
+func similarity(a, b string) float64 {
+	aWords := strings.Fields(normalizeText(a))
+	bWords := strings.Fields(normalizeText(b))
+	a, b = strings.Join(aWords, ""), strings.Join(bWords, "")
+	if a == "" || b == "" {
+		return 0
+	}
+	if a == b {
+		return 1
+	}
+
+	shorter, longer, longerWords := a, b, bWords
+	if len([]rune(shorter)) > len([]rune(longer)) {
+		shorter, longer, longerWords = b, a, aWords
+	}
+	score := 1 - float64(levenshtein([]rune(shorter), []rune(longer)))/float64(len([]rune(longer)))
+	if containsWords(longerWords, shorter) {
+		score = max(score, 0.8+0.2*float64(len([]rune(shorter)))/float64(len([]rune(longer))))
+	}
+	return score
+}
+
+// containsWords reports whether s, with spaces removed, spells out a run of
+// consecutive whole words, so that "kirkland" and "kirk land" are found in
+// [kirkland signature] but "co" is not found in [costco]
+func containsWords(words []string, s string) bool {
+	for i := range words {
+		joined := ""
+		for _, w := range words[i:] {
+			joined += w
+			if joined == s {
+				return true
+			}
+			if len(joined) >= len(s) {
+				break
+			}
+		}
+	}
+	return false
+}
+
+// nameRelevance scores a food name against the search query. Names
+// containing every query word score at least 0.8, closer names higher.
+func nameRelevance(query, name string) float64 {
+	score := similarity(query, name)
+
+	nameWords := strings.Fields(normalizeText(name))
+	queryWords := strings.Fields(normalizeText(query))
+	if len(queryWords) == 0 {
+		return score
+	}
+	for _, qw := range queryWords {
+		found := false
+		for _, nw := range nameWords {
+			if strings.HasPrefix(nw, qw) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return score
+		}
+	}
+	return max(score, 0.8+0.2*float64(len(queryWords))/float64(len(nameWords)))
+}
+
+// levenshtein returns the edit distance between two rune slices
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,54 @@
+package tools
+
+import "testing"
+
+func TestNormalizeText(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Trader Joe's", "trader joes"},
+		{"TRADER JOES", "trader joes"},
+		{"Trader-Joe’s", "trader joes"},
+		{"Ben & Jerry's", "ben and jerrys"},
+		{"Nestlé", "nestle"},
+		{"  Häagen-Dazs  ", "haagen dazs"},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeText(tt.in); got != tt.want {
+			t.Errorf("normalizeText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		name      string
+		a, b      string
+		wantMatch bool
+	}{
+		{name: "apostrophe", a: "Trader Joe's", b: "Trader Joes", wantMatch: true},
+		{name: "accent", a: "Nestle", b: "Nestlé", wantMatch: true},
+		{name: "spacing", a: "Kirk Land", b: "Kirkland", wantMatch: true},
+		{name: "typo", a: "Kirklnd", b: "Kirkland", wantMatch: true},
+		{name: "sub-brand", a: "Kirkland", b: "Kirkland Signature", wantMatch: true},
+		{name: "different brand", a: "Great Value", b: "Great Taste", wantMatch: false},
+		{name: "spaced sub-brand", a: "Kirk Land", b: "Kirkland Signature", wantMatch: true},
+		{name: "short brand word", a: "Co", b: "Co Yo", wantMatch: true},
+		{name: "short brand inside a word", a: "Co", b: "Costco", wantMatch: false},
+		{name: "short brand prefix", a: "Co", b: "Coca-Cola", wantMatch: false},
+		{name: "one letter", a: "K", b: "Kellogg's", wantMatch: false},
+		{name: "shared prefix", a: "Kind", b: "Kindred Farms", wantMatch: false},
+		{name: "word prefix", a: "Nature", b: "Nature's Path", wantMatch: false},
+		{name: "empty", a: "", b: "Heinz", wantMatch: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := similarity(tt.a, tt.b)
+			if got := score >= brandMatchThreshold; got != tt.wantMatch {
+				t.Errorf("similarity(%q, %q) = %.2f, want match %v", tt.a, tt.b, score, tt.wantMatch)
+			}
+		})
+	}
+}
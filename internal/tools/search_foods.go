@@ -1,46 +1,62 @@
 package tools
 
 import (
+	"cmp"
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 
+	"github.com/chickenzord/sparkyfitness-mcp/internal/nutrition"
 	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
 // SearchFoodsInput defines the input parameters for the search_foods tool
 type SearchFoodsInput struct {
-	Name       string  `json:"name" jsonschema:"Food name to search for"`
-	Brand      *string `json:"brand,omitempty" jsonschema:"Optional brand name to filter results"`
-	BroadMatch *bool   `json:"broad_match,omitempty" jsonschema:"If true, performs broad match search (default: true)"`
-	Limit      *int    `json:"limit,omitempty" jsonschema:"Maximum number of results to return (default: 10)"`
+	Name       string   `json:"name" jsonschema:"Food name to search for"`
+	Brand      *string  `json:"brand,omitempty" jsonschema:"Optional brand name to filter results"`
+	BroadMatch *bool    `json:"broad_match,omitempty" jsonschema:"If true, performs broad match search (default: true)"`
+	Limit      *int     `json:"limit,omitempty" jsonschema:"Maximum number of results to return (default: 10)"`
+	Fields     []string `json:"fields,omitempty" jsonschema:"Optional nutrients to include (e.g., ['sodium', 'sugars']); calories, protein, carbs and fat are always included. Omit for every nutrient"`
 }
 
-// FoodResult represents a single food with its default variant in the search results
+// FoodResult represents a single food with its default variant in the search results.
+// Optional nutrients are set when selected by fields (every one when fields is
+// omitted), so a selected zero amount is still emitted.
 type FoodResult struct {
-	FoodID        string  `json:"food_id" jsonschema:"Unique identifier of the food"`
-	FoodName      string  `json:"food_name" jsonschema:"Name of the food"`
-	Brand         *string `json:"brand,omitempty" jsonschema:"Brand name if available"`
-	IsCustom      bool    `json:"is_custom" jsonschema:"Whether this is a custom food"`
-	ProviderType  *string `json:"provider_type,omitempty" jsonschema:"Provider type (e.g., usda, nutritionix)"`
-	VariantID     string  `json:"variant_id" jsonschema:"Unique identifier of the default variant"`
-	ServingSize   float64 `json:"serving_size" jsonschema:"Serving size amount"`
-	ServingUnit   string  `json:"serving_unit" jsonschema:"Unit of measurement for serving"`
-	Calories      float64 `json:"calories" jsonschema:"Calories per serving"`
-	Protein       float64 `json:"protein" jsonschema:"Protein in grams"`
-	Carbs         float64 `json:"carbs" jsonschema:"Carbohydrates in grams"`
-	Fat           float64 `json:"fat" jsonschema:"Fat in grams"`
-	SaturatedFat  float64 `json:"saturated_fat,omitempty" jsonschema:"Saturated fat in grams"`
-	DietaryFiber  float64 `json:"dietary_fiber,omitempty" jsonschema:"Dietary fiber in grams"`
-	Sugars        float64 `json:"sugars,omitempty" jsonschema:"Sugars in grams"`
-	Sodium        float64 `json:"sodium,omitempty" jsonschema:"Sodium in milligrams"`
-	Cholesterol   float64 `json:"cholesterol,omitempty" jsonschema:"Cholesterol in milligrams"`
-	Potassium     float64 `json:"potassium,omitempty" jsonschema:"Potassium in milligrams"`
-	VitaminA      float64 `json:"vitamin_a,omitempty" jsonschema:"Vitamin A"`
-	VitaminC      float64 `json:"vitamin_c,omitempty" jsonschema:"Vitamin C"`
-	Calcium       float64 `json:"calcium,omitempty" jsonschema:"Calcium"`
-	Iron          float64 `json:"iron,omitempty" jsonschema:"Iron"`
-	GlycemicIndex *string `json:"glycemic_index,omitempty" jsonschema:"Glycemic index if available"`
+	FoodID             string                 `json:"food_id" jsonschema:"Unique identifier of the food"`
+	FoodName           string                 `json:"food_name" jsonschema:"Name of the food"`
+	Brand              *string                `json:"brand,omitempty" jsonschema:"Brand name if available"`
+	IsCustom           bool                   `json:"is_custom" jsonschema:"Whether this is a custom food"`
+	UserID             string                 `json:"user_id,omitempty" jsonschema:"ID of the user who owns the food"`
+	SharedWithPublic   bool                   `json:"shared_with_public" jsonschema:"Whether the food is shared with all users"`
+	ProviderType       *string                `json:"provider_type,omitempty" jsonschema:"Provider type (e.g., usda, nutritionix)"`
+	ProviderExternalID *string                `json:"provider_external_id,omitempty" jsonschema:"ID of the food at its external provider"`
+	VariantID          string                 `json:"variant_id" jsonschema:"Unique identifier of the default variant"`
+	IsDefault          bool                   `json:"is_default" jsonschema:"Whether the variant is the food's default variant"`
+	ServingSize        float64                `json:"serving_size" jsonschema:"Serving size amount"`
+	ServingUnit        string                 `json:"serving_unit" jsonschema:"Unit of measurement for serving"`
+	Calories           float64                `json:"calories" jsonschema:"Calories per serving"`
+	Protein            float64                `json:"protein" jsonschema:"Protein in grams"`
+	Carbs              float64                `json:"carbs" jsonschema:"Carbohydrates in grams"`
+	Fat                float64                `json:"fat" jsonschema:"Fat in grams"`
+	SaturatedFat       *float64               `json:"saturated_fat,omitempty" jsonschema:"Saturated fat in grams"`
+	PolyunsaturatedFat *float64               `json:"polyunsaturated_fat,omitempty" jsonschema:"Polyunsaturated fat in grams"`
+	MonounsaturatedFat *float64               `json:"monounsaturated_fat,omitempty" jsonschema:"Monounsaturated fat in grams"`
+	TransFat           *float64               `json:"trans_fat,omitempty" jsonschema:"Trans fat in grams"`
+	Cholesterol        *float64               `json:"cholesterol,omitempty" jsonschema:"Cholesterol in milligrams"`
+	Sodium             *float64               `json:"sodium,omitempty" jsonschema:"Sodium in milligrams"`
+	Potassium          *float64               `json:"potassium,omitempty" jsonschema:"Potassium in milligrams"`
+	DietaryFiber       *float64               `json:"dietary_fiber,omitempty" jsonschema:"Dietary fiber in grams"`
+	Sugars             *float64               `json:"sugars,omitempty" jsonschema:"Sugars in grams"`
+	VitaminA           *float64               `json:"vitamin_a,omitempty" jsonschema:"Vitamin A in micrograms (mcg RAE)"`
+	VitaminC           *float64               `json:"vitamin_c,omitempty" jsonschema:"Vitamin C in milligrams"`
+	Calcium            *float64               `json:"calcium,omitempty" jsonschema:"Calcium in milligrams"`
+	Iron               *float64               `json:"iron,omitempty" jsonschema:"Iron in milligrams"`
+	GlycemicIndex      *string                `json:"glycemic_index,omitempty" jsonschema:"Glycemic index if available"`
+	CustomNutrients    []CustomNutrientResult `json:"custom_nutrients,omitempty" jsonschema:"Amounts of the user's custom nutrients"`
+	Score              float64                `json:"score,omitempty" jsonschema:"Relevance to the search from 0 to 1, combining name and brand similarity"`
 }
 
 // SearchFoodsOutput defines the output structure
@@ -49,6 +65,20 @@ type SearchFoodsOutput struct {
 	Total int          `json:"total" jsonschema:"Total number of foods found"`
 }
 
+const (
+	// brandSearchFactor is how many results to fetch per requested result
+	// when filtering by brand, which happens after the backend search
+	brandSearchFactor = 4
+	// maxSearchFetch caps the number of foods fetched for one search
+	maxSearchFetch = 200
+)
+
+// scoredFood is a search result with its relevance score
+type scoredFood struct {
+	food  sparkyfitness.Food
+	score float64
+}
+
 // RegisterSearchFoods registers the search_foods tool with the MCP server
 func (r *Registry) RegisterSearchFoods(server *mcp.Server, client *sparkyfitness.Client) error {
 	tool := &mcp.Tool{
@@ -58,16 +88,19 @@ func (r *Registry) RegisterSearchFoods(server *mcp.Server, client *sparkyfitness
 			"**IMPORTANT: ALWAYS call this FIRST before creating any food to prevent duplicates.**\n\n" +
 			"Use Cases:\n" +
 			"• Before creating a new food entry, search to see if it already exists\n" +
-			"• Find existing foods by name and optionally filter by brand\n" +
+			"• Find existing foods by name and optionally filter by brand (tolerant of case, accents and punctuation, e.g., 'Trader Joes' matches \"Trader Joe's\")\n" +
 			"• Discover food_id values needed for add_food_variant tool\n\n" +
 			"Search Behavior:\n" +
 			"• broad_match=true (default): Fuzzy case-insensitive search - finds similar foods (e.g., 'rice' matches 'White Rice', 'Brown Rice')\n" +
 			"• broad_match=false: Exact matching - only finds precise matches\n" +
-			"• Returns up to 'limit' results (default: 10, recommended: 5 for duplicate checking)\n\n" +
+			"• Returns up to 'limit' results (default: 10, recommended: 5 for duplicate checking), most relevant first\n\n" +
 			"Response:\n" +
 			"• Each result includes food_id (required for add_food_variant)\n" +
 			"• Each result includes variant_id (the default variant)\n" +
-			"• Full nutrition data for the default variant is included\n\n" +
+			"• Each result includes a relevance score from 0 to 1; low scores are loose matches\n" +
+			"• Full nutrition data for the default variant is included, with the user's custom nutrients\n" +
+			"• fields: list only the nutrients you need (e.g., ['sodium']) to keep large result sets compact\n" +
+			"• Other serving sizes are NOT listed - call get_food(food_id) to see every variant\n\n" +
 			"Workflow:\n" +
 			"1. User uploads nutrition label photo\n" +
 			"2. Extract food name, brand, nutrition data\n" +
@@ -81,6 +114,12 @@ func (r *Registry) RegisterSearchFoods(server *mcp.Server, client *sparkyfitness
 		if input.Name == "" {
 			return nil, SearchFoodsOutput{}, fmt.Errorf("name parameter is required")
 		}
+		if err := validateSearchFields(input.Fields); err != nil {
+			return nil, SearchFoodsOutput{}, err
+		}
+		if input.Limit != nil && *input.Limit <= 0 {
+			return nil, SearchFoodsOutput{}, fmt.Errorf("limit must be greater than 0")
+		}
 
 		// Set defaults
 		broadMatch := true
@@ -93,43 +132,50 @@ func (r *Registry) RegisterSearchFoods(server *mcp.Server, client *sparkyfitness
 			limit = *input.Limit
 		}
 
-		// Search for foods using backend API
-		foods, err := client.SearchFoods(ctx, input.Name, broadMatch, limit)
-		if err != nil {
-			return nil, SearchFoodsOutput{}, fmt.Errorf("failed to search foods: %w", err)
+		brand := ""
+		if input.Brand != nil {
+			brand = strings.TrimSpace(*input.Brand)
 		}
 
-		// Filter by brand if specified
-		var filteredFoods []sparkyfitness.Food
-		if input.Brand != nil && *input.Brand != "" {
-			for _, food := range foods {
-				// Check brand (handle both nil and empty string)
-				if food.Brand != nil && *food.Brand == *input.Brand {
-					filteredFoods = append(filteredFoods, food)
-				}
+		// Search for foods using backend API. The backend has no brand filter
+		// or paging, so when filtering by brand, fetch more results until
+		// enough brands match or the backend has no more foods.
+		fetchLimit := limit
+		if brand != "" {
+			fetchLimit = min(limit*brandSearchFactor, maxSearchFetch)
+		}
+		var matches []scoredFood
+		for {
+			foods, err := client.SearchFoods(ctx, input.Name, broadMatch, fetchLimit)
+			if err != nil {
+				return nil, SearchFoodsOutput{}, backendError("search foods", err)
+			}
+			matches = rankFoods(foods, input.Name, brand)
+			if brand == "" || len(matches) >= limit || len(foods) < fetchLimit || fetchLimit >= maxSearchFetch {
+				break
 			}
-		} else {
-			filteredFoods = foods
+			fetchLimit = min(fetchLimit*2, maxSearchFetch)
 		}
-
-		// No foods found
-		if len(filteredFoods) == 0 {
-			return nil, SearchFoodsOutput{
-				Foods: []FoodResult{},
-				Total: 0,
-			}, nil
+		if len(matches) > limit {
+			matches = matches[:limit]
 		}
 
 		// Convert foods to result format
-		var results []FoodResult
-		for _, food := range filteredFoods {
-			// Skip foods without default variant
-			if food.DefaultVariant == nil {
-				continue
+		results := []FoodResult{}
+		var custom []map[string]interface{}
+		for _, match := range matches {
+			result := convertFoodToResult(match.food, input.Fields)
+			result.Score = match.score
+			results = append(results, result)
+			if len(result.CustomNutrients) > 0 {
+				custom = append(custom, match.food.DefaultVariant.CustomNutrients)
 			}
+		}
 
-			result := convertFoodToResult(food)
-			results = append(results, result)
+		// Add the units of custom nutrients, unless fields left them out
+		units := customNutrientUnits(ctx, client, custom...)
+		for i := range results {
+			setCustomNutrientUnits(results[i].CustomNutrients, units)
 		}
 
 		// Prepare output
@@ -145,33 +191,106 @@ func (r *Registry) RegisterSearchFoods(server *mcp.Server, client *sparkyfitness
 	return nil
 }
 
-// convertFoodToResult converts a Food from backend API to FoodResult
-func convertFoodToResult(food sparkyfitness.Food) FoodResult {
+// rankFoods scores foods against the searched name and brand and returns
+// those with a default variant, best first. When brand is set, foods whose
+// brand is not similar enough are dropped and the score combines name and
+// brand similarity.
+func rankFoods(foods []sparkyfitness.Food, name, brand string) []scoredFood {
+	var ranked []scoredFood
+	for _, food := range foods {
+		// Skip foods without default variant
+		if food.DefaultVariant == nil {
+			continue
+		}
+
+		score := nameRelevance(name, food.Name)
+		if brand != "" {
+			if food.Brand == nil {
+				continue
+			}
+			brandScore := similarity(brand, *food.Brand)
+			if brandScore < brandMatchThreshold {
+				continue
+			}
+			score = nameWeight*score + brandWeight*brandScore
+		}
+		ranked = append(ranked, scoredFood{food: food, score: round2(score)})
+	}
+
+	slices.SortStableFunc(ranked, func(a, b scoredFood) int {
+		return cmp.Compare(b.score, a.score)
+	})
+	return ranked
+}
+
+// convertFoodToResult converts a Food from backend API to FoodResult. When
+// fields is non-empty, only those optional fields are included besides the
+// food metadata, serving and core macros.
+func convertFoodToResult(food sparkyfitness.Food, fields []string) FoodResult {
 	variant := food.DefaultVariant
+	n := variant.Nutrients
+
+	selected := func(field string) bool {
+		return len(fields) == 0 || slices.Contains(fields, field)
+	}
+	optional := func(field string, value float64) *float64 {
+		if !selected(field) {
+			return nil
+		}
+		return &value
+	}
 
-	return FoodResult{
-		FoodID:        food.ID,
-		FoodName:      food.Name,
-		Brand:         food.Brand,
-		IsCustom:      food.IsCustom,
-		ProviderType:  food.ProviderType,
-		VariantID:     variant.ID,
-		ServingSize:   variant.ServingSize,
-		ServingUnit:   variant.ServingUnit,
-		Calories:      variant.Calories,
-		Protein:       variant.Protein,
-		Carbs:         variant.Carbs,
-		Fat:           variant.Fat,
-		SaturatedFat:  variant.SaturatedFat,
-		DietaryFiber:  variant.DietaryFiber,
-		Sugars:        variant.Sugars,
-		Sodium:        variant.Sodium,
-		Cholesterol:   variant.Cholesterol,
-		Potassium:     variant.Potassium,
-		VitaminA:      variant.VitaminA,
-		VitaminC:      variant.VitaminC,
-		Calcium:       variant.Calcium,
-		Iron:          variant.Iron,
-		GlycemicIndex: variant.GlycemicIndex,
+	result := FoodResult{
+		FoodID:             food.ID,
+		FoodName:           food.Name,
+		Brand:              food.Brand,
+		IsCustom:           food.IsCustom,
+		UserID:             food.UserID,
+		SharedWithPublic:   food.SharedWithPublic,
+		ProviderType:       food.ProviderType,
+		ProviderExternalID: food.ProviderExternalID,
+		VariantID:          variant.ID,
+		IsDefault:          variant.IsDefault,
+		ServingSize:        variant.ServingSize,
+		ServingUnit:        variant.ServingUnit,
+		Calories:           n.Calories,
+		Protein:            n.Protein,
+		Carbs:              n.Carbs,
+		Fat:                n.Fat,
+		SaturatedFat:       optional("saturated_fat", n.SaturatedFat),
+		PolyunsaturatedFat: optional("polyunsaturated_fat", n.PolyunsaturatedFat),
+		MonounsaturatedFat: optional("monounsaturated_fat", n.MonounsaturatedFat),
+		TransFat:           optional("trans_fat", n.TransFat),
+		Cholesterol:        optional("cholesterol", n.Cholesterol),
+		Sodium:             optional("sodium", n.Sodium),
+		Potassium:          optional("potassium", n.Potassium),
+		DietaryFiber:       optional("dietary_fiber", n.DietaryFiber),
+		Sugars:             optional("sugars", n.Sugars),
+		VitaminA:           optional("vitamin_a", n.VitaminA),
+		VitaminC:           optional("vitamin_c", n.VitaminC),
+		Calcium:            optional("calcium", n.Calcium),
+		Iron:               optional("iron", n.Iron),
 	}
+	if selected("glycemic_index") {
+		result.GlycemicIndex = variant.GlycemicIndex
+	}
+	if selected("custom_nutrients") {
+		result.CustomNutrients = convertCustomNutrientsToResult(variant.CustomNutrients)
+	}
+
+	return result
+}
+
+// validateSearchFields checks that every requested projection field exists
+func validateSearchFields(fields []string) error {
+	for _, field := range fields {
+		if field == "glycemic_index" || field == "custom_nutrients" {
+			continue
+		}
+		if _, ok := nutrition.NutrientField(&sparkyfitness.Nutrients{}, field); !ok {
+			return fmt.Errorf("unknown field '%s' in fields; use nutrient names such as saturated_fat, sodium, "+
+				"dietary_fiber, sugars, vitamin_c, or glycemic_index and custom_nutrients", field)
+		}
+	}
+	return nil
 }
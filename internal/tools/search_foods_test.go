@@ -0,0 +1,204 @@
+package tools
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness/sparkyfitnesstest"
+)
+
+func TestSearchFoods(t *testing.T) {
+	backend, session := newTestSession(t)
+
+	brand := "Trader Joe's"
+	backend.AddFood(sparkyfitness.Food{Name: "Jasmine Rice", Brand: &brand, IsCustom: true},
+		sparkyfitness.FoodVariant{ServingSize: 45, ServingUnit: "g", Nutrients: sparkyfitness.Nutrients{Calories: 160, Protein: 3, Carbs: 36, Fat: 0}},
+	)
+	backend.AddFood(sparkyfitness.Food{Name: "Brown Rice", IsCustom: true},
+		sparkyfitness.FoodVariant{ServingSize: 100, ServingUnit: "g", Nutrients: sparkyfitness.Nutrients{Calories: 140, Protein: 3.5, Carbs: 29.8, Fat: 0.9}},
+	)
+
+	t.Run("broad match returns all matches", func(t *testing.T) {
+		out := callTool[SearchFoodsOutput](t, session, "search_foods", map[string]any{"name": "rice"})
+
+		if out.Total != 2 {
+			t.Fatalf("Total = %d, want 2", out.Total)
+		}
+		if out.Foods[0].FoodName != "Jasmine Rice" || out.Foods[0].Calories != 160 {
+			t.Errorf("Foods[0] = %+v", out.Foods[0])
+		}
+	})
+
+	t.Run("brand filter", func(t *testing.T) {
+		out := callTool[SearchFoodsOutput](t, session, "search_foods", map[string]any{"name": "rice", "brand": "Trader Joe's"})
+
+		if out.Total != 1 || out.Foods[0].FoodName != "Jasmine Rice" {
+			t.Errorf("Foods = %+v, want only Jasmine Rice", out.Foods)
+		}
+	})
+
+	t.Run("no matches", func(t *testing.T) {
+		out := callTool[SearchFoodsOutput](t, session, "search_foods", map[string]any{"name": "quinoa"})
+
+		if out.Total != 0 || len(out.Foods) != 0 {
+			t.Errorf("Foods = %+v, want none", out.Foods)
+		}
+	})
+}
+
+func TestSearchFoodsBrandMatching(t *testing.T) {
+	backend, session := newTestSession(t)
+
+	variant := sparkyfitness.FoodVariant{ServingSize: 100, ServingUnit: "g", Nutrients: sparkyfitness.Nutrients{Calories: 130, Protein: 2.7, Carbs: 28, Fat: 0.3}}
+	for range 30 {
+		backend.AddFood(sparkyfitness.Food{Name: "Rice Cakes"}, variant)
+	}
+	joes := "Trader Joe's"
+	backend.AddFood(sparkyfitness.Food{Name: "Rice Cakes", Brand: &joes}, variant)
+	backend.AddFood(sparkyfitness.Food{Name: "Rice", Brand: &joes}, variant)
+	other := "Great Value"
+	backend.AddFood(sparkyfitness.Food{Name: "Rice", Brand: &other}, variant)
+
+	t.Run("brand beyond limit with different punctuation", func(t *testing.T) {
+		out := callTool[SearchFoodsOutput](t, session, "search_foods", map[string]any{"name": "rice", "brand": "trader joes", "limit": 5})
+
+		if out.Total != 2 {
+			t.Fatalf("Total = %d, want 2: %+v", out.Total, out.Foods)
+		}
+		for _, food := range out.Foods {
+			if food.Brand == nil || *food.Brand != joes {
+				t.Errorf("Brand = %v, want %q", food.Brand, joes)
+			}
+		}
+	})
+
+	t.Run("ranked by relevance", func(t *testing.T) {
+		out := callTool[SearchFoodsOutput](t, session, "search_foods", map[string]any{"name": "rice", "brand": "Trader Joe's"})
+
+		if out.Foods[0].FoodName != "Rice" || out.Foods[1].FoodName != "Rice Cakes" {
+			t.Fatalf("order = %s, %s; want Rice first", out.Foods[0].FoodName, out.Foods[1].FoodName)
+		}
+		if out.Foods[0].Score != 1 || out.Foods[1].Score >= out.Foods[0].Score {
+			t.Errorf("scores = %g, %g", out.Foods[0].Score, out.Foods[1].Score)
+		}
+	})
+}
+
+func TestSearchFoodsFullVariant(t *testing.T) {
+	backend, session := newTestSession(t)
+
+	externalID := "usda-171705"
+	backend.AddFood(sparkyfitness.Food{Name: "Avocado", UserID: "user-1", SharedWithPublic: true, ProviderExternalID: &externalID},
+		sparkyfitness.FoodVariant{ServingSize: 100, ServingUnit: "g", IsDefault: true, Nutrients: sparkyfitness.Nutrients{
+			Calories: 160, Protein: 2, Carbs: 8.5, Fat: 14.7,
+			SaturatedFat: 2.1, MonounsaturatedFat: 9.8, PolyunsaturatedFat: 1.8, TransFat: 0.1,
+			Sodium: 7, DietaryFiber: 6.7,
+		}, CustomNutrients: map[string]interface{}{"Caffeine": 0.0}},
+	)
+	backend.AddCustomNutrient("Caffeine", "mg")
+
+	t.Run("complete metadata and nutrients", func(t *testing.T) {
+		out := callTool[SearchFoodsOutput](t, session, "search_foods", map[string]any{"name": "avocado"})
+
+		if out.Total != 1 {
+			t.Fatalf("Total = %d, want 1", out.Total)
+		}
+		got := out.Foods[0]
+		if got.UserID != "user-1" || !got.SharedWithPublic || got.ProviderExternalID == nil || *got.ProviderExternalID != externalID || !got.IsDefault {
+			t.Errorf("metadata = %+v", got)
+		}
+		if *got.MonounsaturatedFat != 9.8 || *got.PolyunsaturatedFat != 1.8 || *got.TransFat != 0.1 || *got.Sodium != 7 {
+			t.Errorf("nutrients = %+v", got)
+		}
+		if got.Sugars == nil || *got.Sugars != 0 {
+			t.Errorf("Sugars = %v, want a zero amount rather than none", got.Sugars)
+		}
+	})
+
+	t.Run("fields projects nutrients", func(t *testing.T) {
+		out := callTool[SearchFoodsOutput](t, session, "search_foods", map[string]any{"name": "avocado", "fields": []string{"sodium", "sugars"}})
+
+		got := out.Foods[0]
+		if got.Calories != 160 || got.Fat != 14.7 || got.Sodium == nil || *got.Sodium != 7 {
+			t.Errorf("selected nutrients = %+v", got)
+		}
+		if got.Sugars == nil || *got.Sugars != 0 {
+			t.Errorf("Sugars = %v, want the selected zero amount emitted", got.Sugars)
+		}
+		if got.SaturatedFat != nil || got.MonounsaturatedFat != nil || got.DietaryFiber != nil {
+			t.Errorf("unselected nutrients included: %+v", got)
+		}
+	})
+
+	t.Run("fields without custom nutrients skips their units", func(t *testing.T) {
+		before := len(backend.Requests())
+		callTool[SearchFoodsOutput](t, session, "search_foods", map[string]any{"name": "avocado", "fields": []string{"sodium"}})
+
+		for _, req := range backend.Requests()[before:] {
+			if req.Path == "/custom-nutrients" {
+				t.Errorf("custom nutrients were fetched although fields leaves them out")
+			}
+		}
+	})
+
+	t.Run("unknown field", func(t *testing.T) {
+		msg := callToolError(t, session, "search_foods", map[string]any{"name": "avocado", "fields": []string{"omega_3"}})
+
+		if !strings.Contains(msg, "unknown field 'omega_3'") {
+			t.Errorf("error = %q", msg)
+		}
+	})
+}
+
+func TestSearchFoodsBackendErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		fault        sparkyfitnesstest.Fault
+		wantContains string
+	}{
+		{
+			name:         "auth failure",
+			fault:        sparkyfitnesstest.Fault{Status: http.StatusUnauthorized, Body: `{"error":"Invalid API key"}`},
+			wantContains: "SPARKYFITNESS_API_KEY",
+		},
+		{
+			name:         "server failure",
+			fault:        sparkyfitnesstest.Fault{Status: http.StatusInternalServerError, Body: `{"error":"database unavailable"}`},
+			wantContains: "internal error",
+		},
+		{
+			name:         "rate limited",
+			fault:        sparkyfitnesstest.Fault{Status: http.StatusTooManyRequests},
+			wantContains: "rate limiting",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend, session := newTestSession(t)
+			backend.InjectFault(tt.fault)
+
+			msg := callToolError(t, session, "search_foods", map[string]any{"name": "rice"})
+			if !strings.Contains(msg, tt.wantContains) {
+				t.Errorf("error = %q, should contain %q", msg, tt.wantContains)
+			}
+		})
+	}
+}
+
+func TestSearchFoodsInvalidLimit(t *testing.T) {
+	backend, session := newTestSession(t)
+	seedDiary(backend)
+
+	for _, limit := range []int{0, -1} {
+		msg := callToolError(t, session, "search_foods", map[string]any{"name": "rice", "brand": "Acme", "limit": limit})
+		if !strings.Contains(msg, "limit must be greater than 0") {
+			t.Errorf("limit %d: error = %q", limit, msg)
+		}
+	}
+	if n := len(backend.Requests()); n != 0 {
+		t.Errorf("backend requests = %d, want none", n)
+	}
+}
@@ -0,0 +1,81 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// SearchMealsInput defines the input parameters for the search_meals tool
+type SearchMealsInput struct {
+	Name  string `json:"name,omitempty" jsonschema:"Meal name to search for (omit to list all saved meals)"`
+	Limit *int   `json:"limit,omitempty" jsonschema:"Maximum number of meals to return (default: 10)"`
+}
+
+// SearchMealsOutput defines the output structure
+type SearchMealsOutput struct {
+	Meals []MealResult `json:"meals" jsonschema:"Matching meals with their foods and nutrition"`
+	Total int          `json:"total" jsonschema:"Number of meals returned"`
+}
+
+// RegisterSearchMeals registers the search_meals tool with the MCP server
+func (r *Registry) RegisterSearchMeals(server *mcp.Server, client *sparkyfitness.Client) error {
+	tool := &mcp.Tool{
+		Name:  "search_meals",
+		Title: "Search Saved Meals",
+		Description: "🔍 List or search the user's saved meals.\n\n" +
+			"**When to Use:**\n" +
+			"• The user refers to a meal by name ('log my usual breakfast')\n" +
+			"• Before create_meal, to avoid saving the same meal twice\n\n" +
+			"**Required Input:**\n" +
+			"• name: part of the meal name (omit to list all meals)\n" +
+			"• limit: maximum meals to return (default: 10)\n\n" +
+			"**Output:**\n" +
+			"• meal_id (for log_meal), foods and amounts, nutrition totals per meal\n" +
+			"• Foods deleted since the meal was saved are listed with an error and left out of the totals",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}
+
+	handler := func(ctx context.Context, request *mcp.CallToolRequest, input SearchMealsInput) (*mcp.CallToolResult, SearchMealsOutput, error) {
+		// Validate parameters
+		limit := 10
+		if input.Limit != nil {
+			if *input.Limit <= 0 {
+				return nil, SearchMealsOutput{}, fmt.Errorf("limit must be greater than 0")
+			}
+			limit = *input.Limit
+		}
+
+		// Call backend API to search meals
+		meals, err := client.SearchMeals(ctx, input.Name)
+		if err != nil {
+			return nil, SearchMealsOutput{}, backendError("search meals", err)
+		}
+		if len(meals) > limit {
+			meals = meals[:limit]
+		}
+
+		// Prepare output
+		summarizer := newMealSummarizer(client)
+		output := SearchMealsOutput{
+			Meals: make([]MealResult, 0, len(meals)),
+			Total: len(meals),
+		}
+		for i := range meals {
+			result, err := summarizer.summarize(ctx, &meals[i])
+			if err != nil {
+				return nil, SearchMealsOutput{}, err
+			}
+			output.Meals = append(output.Meals, result)
+		}
+
+		return nil, output, nil
+	}
+
+	mcp.AddTool(server, tool, handler)
+	return nil
+}
@@ -0,0 +1,113 @@
+package tools
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness/sparkyfitnesstest"
+)
+
+// seedMeals saves a breakfast and a dinner meal built from the seedDiary foods
+func seedMeals(t *testing.T, backend *sparkyfitnesstest.Server) (breakfast, dinner sparkyfitness.Meal) {
+	t.Helper()
+
+	rice, egg := seedDiary(backend)
+	client := backend.NewClient(t)
+
+	created, err := client.CreateMeal(t.Context(), &sparkyfitness.CreateMealRequest{
+		Name:  "Egg Breakfast",
+		Foods: []sparkyfitness.MealFood{{FoodID: egg.ID, VariantID: egg.DefaultVariant.ID, Quantity: 2, Unit: "piece"}},
+	})
+	if err != nil {
+		t.Fatalf("CreateMeal() unexpected error: %v", err)
+	}
+	breakfast = *created
+
+	created, err = client.CreateMeal(t.Context(), &sparkyfitness.CreateMealRequest{
+		Name: "Rice and Egg Dinner",
+		Foods: []sparkyfitness.MealFood{
+			{FoodID: rice.ID, VariantID: rice.DefaultVariant.ID, Quantity: 200, Unit: "g"},
+			{FoodID: egg.ID, VariantID: egg.DefaultVariant.ID, Quantity: 1, Unit: "piece"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateMeal() unexpected error: %v", err)
+	}
+	dinner = *created
+
+	return breakfast, dinner
+}
+
+func TestSearchMeals(t *testing.T) {
+	backend, session := newTestSession(t)
+	_, dinner := seedMeals(t, backend)
+
+	tests := []struct {
+		name      string
+		args      map[string]any
+		wantNames []string
+	}{
+		{
+			name:      "all meals",
+			args:      map[string]any{},
+			wantNames: []string{"Egg Breakfast", "Rice and Egg Dinner"},
+		},
+		{
+			name:      "by name",
+			args:      map[string]any{"name": "dinner"},
+			wantNames: []string{"Rice and Egg Dinner"},
+		},
+		{
+			name:      "limit",
+			args:      map[string]any{"name": "egg", "limit": 1},
+			wantNames: []string{"Egg Breakfast"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := callTool[SearchMealsOutput](t, session, "search_meals", tt.args)
+
+			if out.Total != len(tt.wantNames) {
+				t.Fatalf("Total = %d, want %d", out.Total, len(tt.wantNames))
+			}
+			for i, meal := range out.Meals {
+				if meal.Name != tt.wantNames[i] {
+					t.Errorf("Meals[%d].Name = %q, want %q", i, meal.Name, tt.wantNames[i])
+				}
+			}
+		})
+	}
+
+	out := callTool[SearchMealsOutput](t, session, "search_meals", map[string]any{"name": "dinner"})
+	if got := out.Meals[0]; got.MealID != dinner.ID || got.Totals.Calories != 338 {
+		t.Errorf("dinner = %+v, want 338 kcal", got)
+	}
+}
+
+func TestSearchMealsDeletedFood(t *testing.T) {
+	backend, session := newTestSession(t)
+	_, dinner := seedMeals(t, backend)
+	if err := backend.NewClient(t).DeleteFood(t.Context(), dinner.Foods[0].FoodID); err != nil {
+		t.Fatalf("DeleteFood() unexpected error: %v", err)
+	}
+
+	out := callTool[SearchMealsOutput](t, session, "search_meals", map[string]any{"name": "dinner"})
+
+	got := out.Meals[0]
+	if got.Missing != 1 || got.Items[0].Error == "" || got.Items[0].FoodName != "Rice" || got.Items[1].Error != "" {
+		t.Errorf("dinner = %+v, want the rice flagged as missing", got)
+	}
+	if got.Totals.Calories != 78 {
+		t.Errorf("Totals.Calories = %v, want 78 (the egg only)", got.Totals.Calories)
+	}
+
+	// Other backend failures still fail the search
+	backend.InjectFault(sparkyfitnesstest.Fault{Method: http.MethodGet, Path: "/foods/food-variants/" + dinner.Foods[1].VariantID, Status: http.StatusInternalServerError})
+	msg := callToolError(t, session, "search_meals", map[string]any{"name": "dinner"})
+	if !strings.Contains(msg, "internal error") {
+		t.Errorf("error = %q, should contain %q", msg, "internal error")
+	}
+}
@@ -0,0 +1,105 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// SetDefaultVariantInput defines the input parameters for the set_default_variant tool
+type SetDefaultVariantInput struct {
+	VariantID string `json:"variant_id" jsonschema:"required,Unique identifier of the variant to make default (from get_food results)"`
+}
+
+// SetDefaultVariantOutput defines the output structure
+type SetDefaultVariantOutput struct {
+	FoodID          string         `json:"food_id" jsonschema:"ID of the food"`
+	FoodName        string         `json:"food_name" jsonschema:"Name of the food"`
+	PreviousDefault *VariantResult `json:"previous_default,omitempty" jsonschema:"Default variant before the change"`
+	NewDefault      VariantResult  `json:"new_default" jsonschema:"Default variant after the change"`
+	Changed         bool           `json:"changed" jsonschema:"Whether the default variant changed (false if it already was the default)"`
+	Message         string         `json:"message" jsonschema:"Result message"`
+}
+
+// RegisterSetDefaultVariant registers the set_default_variant tool with the MCP server
+func (r *Registry) RegisterSetDefaultVariant(server *mcp.Server, client *sparkyfitness.Client) error {
+	tool := &mcp.Tool{
+		Name:  "set_default_variant",
+		Title: "Set Default Variant",
+		Description: "⭐ Make an EXISTING variant the default serving of its food.\n\n" +
+			"**When to Use:**\n" +
+			"• The user wants a different serving (e.g., '1 bowl' instead of '100 g') shown by default\n" +
+			"• search_foods only shows the default variant, so this changes what search results display\n\n" +
+			"**Required Input:**\n" +
+			"• variant_id: UUID of the variant from get_food results\n\n" +
+			"**Output:**\n" +
+			"• previous_default and new_default: the serving and nutrition before and after\n" +
+			"• changed: false if the variant already was the default\n\n" +
+			"**Example Workflow:**\n" +
+			"User: 'Make 1 bowl the default for Oatmeal'\n" +
+			"1. search_foods(name='Oatmeal') → food_id='abc-123'\n" +
+			"2. get_food(food_id='abc-123') → the 1 bowl variant has variant_id='def-456'\n" +
+			"3. set_default_variant(variant_id='def-456')",
+		Annotations: &mcp.ToolAnnotations{
+			IdempotentHint: true,
+		},
+	}
+
+	handler := func(ctx context.Context, request *mcp.CallToolRequest, input SetDefaultVariantInput) (*mcp.CallToolResult, SetDefaultVariantOutput, error) {
+		// Validate required parameters
+		if input.VariantID == "" {
+			return nil, SetDefaultVariantOutput{}, fmt.Errorf("variant_id parameter is required")
+		}
+
+		// Fetch the variant and its food's current default
+		variant, err := client.GetFoodVariant(ctx, input.VariantID)
+		if err != nil {
+			return nil, SetDefaultVariantOutput{}, backendError("get food variant", err)
+		}
+		food, err := client.GetFood(ctx, variant.FoodID)
+		if err != nil {
+			return nil, SetDefaultVariantOutput{}, backendError("get food", err)
+		}
+
+		output := SetDefaultVariantOutput{
+			FoodID:   food.ID,
+			FoodName: food.Name,
+		}
+		if food.DefaultVariant != nil {
+			previous := convertVariantToResult(*food.DefaultVariant)
+			output.PreviousDefault = &previous
+		}
+
+		// Already the default; skip the write
+		if variant.IsDefault {
+			output.NewDefault = convertVariantToResult(*variant)
+			output.Message = fmt.Sprintf("%g %s is already the default variant of '%s'",
+				variant.ServingSize, variant.ServingUnit, food.Name)
+			return nil, output, nil
+		}
+
+		// Call backend API to promote the variant
+		req := variantUpdateRequest(variant)
+		req.IsDefault = true
+		resp, err := client.UpdateFoodVariant(ctx, variant.ID, req)
+		if err != nil {
+			return nil, SetDefaultVariantOutput{}, backendError("set default variant", err)
+		}
+
+		// Prepare output
+		output.NewDefault = convertVariantToResult(*resp)
+		output.Changed = true
+		output.Message = fmt.Sprintf("Default variant of '%s' is now %g %s",
+			food.Name, resp.ServingSize, resp.ServingUnit)
+		if output.PreviousDefault != nil {
+			output.Message += fmt.Sprintf(" (was %g %s)", output.PreviousDefault.ServingSize, output.PreviousDefault.ServingUnit)
+		}
+
+		return nil, output, nil
+	}
+
+	mcp.AddTool(server, tool, handler)
+	return nil
+}
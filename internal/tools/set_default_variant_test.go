@@ -0,0 +1,58 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+)
+
+func TestSetDefaultVariant(t *testing.T) {
+	backend, session := newTestSession(t)
+	food := backend.AddFood(sparkyfitness.Food{Name: "Oatmeal"},
+		sparkyfitness.FoodVariant{ServingSize: 100, ServingUnit: "g", Nutrients: sparkyfitness.Nutrients{Calories: 68, Protein: 2.4}},
+		sparkyfitness.FoodVariant{ServingSize: 1, ServingUnit: "bowl", Nutrients: sparkyfitness.Nutrients{Calories: 166, Protein: 5.9}},
+	)
+	_, variants, _ := backend.Food(food.ID)
+	bowl := variants[1]
+
+	out := callTool[SetDefaultVariantOutput](t, session, "set_default_variant", map[string]any{
+		"variant_id": bowl.ID,
+	})
+
+	if !out.Changed || out.FoodID != food.ID {
+		t.Errorf("output = %+v", out)
+	}
+	if out.PreviousDefault == nil || out.PreviousDefault.ServingUnit != "g" {
+		t.Errorf("PreviousDefault = %+v, want 100 g", out.PreviousDefault)
+	}
+	if out.NewDefault.VariantID != bowl.ID || out.NewDefault.Calories != 166 {
+		t.Errorf("NewDefault = %+v, want 1 bowl", out.NewDefault)
+	}
+
+	// Exactly one default, with nutrition untouched
+	stored, variants, _ := backend.Food(food.ID)
+	if stored.DefaultVariant == nil || stored.DefaultVariant.ID != bowl.ID {
+		t.Errorf("default variant = %+v, want %s", stored.DefaultVariant, bowl.ID)
+	}
+	if variants[0].IsDefault || variants[1].Protein != 5.9 {
+		t.Errorf("variants = %+v", variants)
+	}
+
+	// Repeating the call is a no-op
+	again := callTool[SetDefaultVariantOutput](t, session, "set_default_variant", map[string]any{
+		"variant_id": bowl.ID,
+	})
+	if again.Changed || !strings.Contains(again.Message, "already") {
+		t.Errorf("repeat output = %+v, want unchanged", again)
+	}
+}
+
+func TestSetDefaultVariantNotFound(t *testing.T) {
+	_, session := newTestSession(t)
+
+	msg := callToolError(t, session, "set_default_variant", map[string]any{"variant_id": "missing"})
+	if !strings.Contains(msg, "not found") {
+		t.Errorf("error = %q, should contain %q", msg, "not found")
+	}
+}
@@ -0,0 +1,137 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// UpdateFoodInput defines the input parameters for the update_food tool
+type UpdateFoodInput struct {
+	FoodID           string  `json:"food_id" jsonschema:"required,Unique identifier of the food to update (from search_foods results)"`
+	Name             *string `json:"name,omitempty" jsonschema:"New food name (omit to keep current)"`
+	Brand            *string `json:"brand,omitempty" jsonschema:"New brand name, empty string removes the brand (omit to keep current)"`
+	SharedWithPublic *bool   `json:"shared_with_public,omitempty" jsonschema:"Share the food with all users (omit to keep current)"`
+}
+
+// UpdateFoodOutput defines the output structure
+type UpdateFoodOutput struct {
+	FoodID           string   `json:"food_id" jsonschema:"ID of the updated food"`
+	FoodName         string   `json:"food_name" jsonschema:"Name of the food after the update"`
+	Brand            *string  `json:"brand,omitempty" jsonschema:"Brand of the food after the update"`
+	SharedWithPublic bool     `json:"shared_with_public" jsonschema:"Whether the food is shared with all users"`
+	UpdatedFields    []string `json:"updated_fields" jsonschema:"Fields changed by this update"`
+	Message          string   `json:"message" jsonschema:"Success message"`
+}
+
+// RegisterUpdateFood registers the update_food tool with the MCP server
+func (r *Registry) RegisterUpdateFood(server *mcp.Server, client *sparkyfitness.Client) error {
+	tool := &mcp.Tool{
+		Name:  "update_food",
+		Title: "Update Food Details",
+		Description: "✏️ Update the name, brand or sharing of an EXISTING food.\n\n" +
+			"**When to Use:**\n" +
+			"• The user wants to fix a typo in a food name or brand\n" +
+			"• The user wants to share a food publicly (or stop sharing it)\n\n" +
+			"**Partial Update:**\n" +
+			"Only the fields you provide are changed; omitted fields keep their current values.\n" +
+			"To change serving size or nutrition, use update_food_variant instead.\n\n" +
+			"**Required Input:**\n" +
+			"• food_id: UUID from search_foods results\n" +
+			"• At least one of: name, brand (empty string removes it), shared_with_public\n\n" +
+			"**Output:**\n" +
+			"• The food's name, brand and sharing after the update\n" +
+			"• updated_fields: which fields actually changed",
+		Annotations: &mcp.ToolAnnotations{
+			IdempotentHint: true,
+		},
+	}
+
+	handler := func(ctx context.Context, request *mcp.CallToolRequest, input UpdateFoodInput) (*mcp.CallToolResult, UpdateFoodOutput, error) {
+		// Validate required parameters
+		if input.FoodID == "" {
+			return nil, UpdateFoodOutput{}, fmt.Errorf("food_id parameter is required")
+		}
+		if input.Name == nil && input.Brand == nil && input.SharedWithPublic == nil {
+			return nil, UpdateFoodOutput{}, fmt.Errorf("nothing to update: provide at least one of name, brand or shared_with_public")
+		}
+		if input.Name != nil && strings.TrimSpace(*input.Name) == "" {
+			return nil, UpdateFoodOutput{}, fmt.Errorf("name cannot be empty")
+		}
+
+		// Fetch current food so omitted fields keep their values
+		food, err := client.GetFood(ctx, input.FoodID)
+		if err != nil {
+			return nil, UpdateFoodOutput{}, backendError("get food", err)
+		}
+
+		// Merge patch onto current values
+		req := &sparkyfitness.UpdateFoodRequest{
+			Name:             food.Name,
+			Brand:            food.Brand,
+			SharedWithPublic: food.SharedWithPublic,
+		}
+
+		updated := []string{}
+		if input.Name != nil && *input.Name != food.Name {
+			req.Name = *input.Name
+			updated = append(updated, "name")
+		}
+		if input.Brand != nil && *input.Brand != stringValue(food.Brand) {
+			if *input.Brand == "" {
+				req.Brand = nil
+			} else {
+				req.Brand = input.Brand
+			}
+			updated = append(updated, "brand")
+		}
+		if input.SharedWithPublic != nil && *input.SharedWithPublic != food.SharedWithPublic {
+			req.SharedWithPublic = *input.SharedWithPublic
+			updated = append(updated, "shared_with_public")
+		}
+
+		// Nothing changed; skip the write
+		if len(updated) == 0 {
+			return nil, UpdateFoodOutput{
+				FoodID:           food.ID,
+				FoodName:         food.Name,
+				Brand:            food.Brand,
+				SharedWithPublic: food.SharedWithPublic,
+				UpdatedFields:    updated,
+				Message:          "No changes: the food already has these values",
+			}, nil
+		}
+
+		// Call backend API to update food
+		resp, err := client.UpdateFood(ctx, input.FoodID, req)
+		if err != nil {
+			return nil, UpdateFoodOutput{}, backendError("update food", err)
+		}
+
+		// Prepare output
+		output := UpdateFoodOutput{
+			FoodID:           resp.ID,
+			FoodName:         resp.Name,
+			Brand:            resp.Brand,
+			SharedWithPublic: resp.SharedWithPublic,
+			UpdatedFields:    updated,
+			Message:          fmt.Sprintf("Successfully updated food '%s' (%s)", resp.Name, strings.Join(updated, ", ")),
+		}
+
+		return nil, output, nil
+	}
+
+	mcp.AddTool(server, tool, handler)
+	return nil
+}
+
+// stringValue returns the value of s, or "" if s is nil
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
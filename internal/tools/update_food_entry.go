@@ -0,0 +1,176 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// UpdateFoodEntryInput defines the input parameters for the update_food_entry tool
+type UpdateFoodEntryInput struct {
+	EntryID   string   `json:"entry_id" jsonschema:"required,Unique identifier of the diary entry (from get_food_diary or log_food_entry)"`
+	VariantID *string  `json:"variant_id,omitempty" jsonschema:"Switch to another serving variant (omit to keep current)"`
+	Quantity  *float64 `json:"quantity,omitempty" jsonschema:"New amount in the variant's serving unit (omit to keep current)"`
+	Servings  *float64 `json:"servings,omitempty" jsonschema:"New number of servings, alternative to quantity (e.g., 0.5 for half)"`
+	MealType  *string  `json:"meal_type,omitempty" jsonschema:"Move to another meal: breakfast, lunch, dinner or snacks (omit to keep current)"`
+	Date      *string  `json:"date,omitempty" jsonschema:"Move to another date in YYYY-MM-DD format (omit to keep current)"`
+}
+
+// UpdateFoodEntryOutput defines the output structure
+type UpdateFoodEntryOutput struct {
+	Entry         DiaryEntryResult `json:"entry" jsonschema:"The diary entry after the update"`
+	Before        NutrientsResult  `json:"before" jsonschema:"Nutrients logged by the entry before the update"`
+	Delta         NutrientsResult  `json:"delta" jsonschema:"Change in logged nutrients (after minus before)"`
+	UpdatedFields []string         `json:"updated_fields" jsonschema:"Fields changed by this update"`
+	Message       string           `json:"message" jsonschema:"Success message"`
+}
+
+// RegisterUpdateFoodEntry registers the update_food_entry tool with the MCP server
+func (r *Registry) RegisterUpdateFoodEntry(server *mcp.Server, client *sparkyfitness.Client) error {
+	tool := &mcp.Tool{
+		Name:  "update_food_entry",
+		Title: "Update Food Diary Entry",
+		Description: "✏️ Change the amount, serving, meal or date of a logged diary entry.\n\n" +
+			"**When to Use:**\n" +
+			"• 'Actually that was lunch, not breakfast' → meal_type\n" +
+			"• 'I only had half' → servings=0.5 (of the variant) or a new quantity\n" +
+			"• 'That was yesterday' → date\n\n" +
+			"**Partial Update:**\n" +
+			"Only the fields you provide are changed. When switching variant_id without a new amount, " +
+			"the number of servings is kept.\n\n" +
+			"**Required Input:**\n" +
+			"• entry_id: from get_food_diary or log_food_entry\n" +
+			"• At least one of: variant_id, quantity or servings, meal_type, date\n\n" +
+			"**Output:**\n" +
+			"• entry: the entry after the update\n" +
+			"• delta: how the logged nutrients changed, to report the effect on the day's totals",
+		Annotations: &mcp.ToolAnnotations{
+			IdempotentHint: true,
+		},
+	}
+
+	handler := func(ctx context.Context, request *mcp.CallToolRequest, input UpdateFoodEntryInput) (*mcp.CallToolResult, UpdateFoodEntryOutput, error) {
+		// Validate required parameters
+		if input.EntryID == "" {
+			return nil, UpdateFoodEntryOutput{}, fmt.Errorf("entry_id parameter is required")
+		}
+		if input.VariantID == nil && input.Quantity == nil && input.Servings == nil && input.MealType == nil && input.Date == nil {
+			return nil, UpdateFoodEntryOutput{}, fmt.Errorf("nothing to update: provide at least one of variant_id, quantity, servings, meal_type or date")
+		}
+		if input.Quantity != nil && input.Servings != nil {
+			return nil, UpdateFoodEntryOutput{}, fmt.Errorf("provide either quantity or servings, not both")
+		}
+		if input.Quantity != nil && *input.Quantity <= 0 {
+			return nil, UpdateFoodEntryOutput{}, fmt.Errorf("quantity must be greater than 0")
+		}
+		if input.Servings != nil && *input.Servings <= 0 {
+			return nil, UpdateFoodEntryOutput{}, fmt.Errorf("servings must be greater than 0")
+		}
+
+		// Fetch current entry so omitted fields keep their values
+		current, err := client.GetFoodEntry(ctx, input.EntryID)
+		if err != nil {
+			return nil, UpdateFoodEntryOutput{}, backendError("get food entry", err)
+		}
+
+		// Merge patch onto current values
+		req := &sparkyfitness.UpdateFoodEntryRequest{
+			FoodID:    current.FoodID,
+			VariantID: current.VariantID,
+			MealType:  current.MealType,
+			Quantity:  current.Quantity,
+			Unit:      current.Unit,
+			EntryDate: current.EntryDate,
+		}
+
+		updated := []string{}
+		servingSize := current.ServingSize
+		if input.VariantID != nil && *input.VariantID != current.VariantID {
+			variant, err := client.GetFoodVariant(ctx, *input.VariantID)
+			if err != nil {
+				return nil, UpdateFoodEntryOutput{}, backendError("get food variant", err)
+			}
+			if variant.FoodID != "" {
+				req.FoodID = variant.FoodID
+			}
+			req.VariantID = variant.ID
+			req.Unit = variant.ServingUnit
+			// Keep the number of servings unless a new amount is given
+			req.Quantity = current.Servings() * variant.ServingSize
+			servingSize = variant.ServingSize
+			updated = append(updated, "variant_id")
+		}
+		if input.Quantity != nil || input.Servings != nil {
+			var quantity float64
+			if input.Quantity != nil {
+				quantity = *input.Quantity
+			} else {
+				quantity = *input.Servings * servingSize
+			}
+			if quantity != req.Quantity {
+				req.Quantity = quantity
+				updated = append(updated, "quantity")
+			}
+		}
+		if input.MealType != nil {
+			mealType, err := normalizeMealType(*input.MealType)
+			if err != nil {
+				return nil, UpdateFoodEntryOutput{}, err
+			}
+			if mealType != current.MealType {
+				req.MealType = mealType
+				updated = append(updated, "meal_type")
+			}
+		}
+		if input.Date != nil {
+			date, err := parseDiaryDate(*input.Date, r.timezone())
+			if err != nil {
+				return nil, UpdateFoodEntryOutput{}, err
+			}
+			if date != current.EntryDate {
+				req.EntryDate = date
+				updated = append(updated, "date")
+			}
+		}
+		if req.Quantity <= 0 {
+			return nil, UpdateFoodEntryOutput{}, fmt.Errorf("the new quantity must be greater than 0; provide quantity or servings")
+		}
+
+		// Nothing changed; skip the write
+		if len(updated) == 0 {
+			return nil, UpdateFoodEntryOutput{
+				Entry:         convertEntryToResult(*current),
+				Before:        convertNutrientsToResult(current.LoggedNutrients()),
+				UpdatedFields: updated,
+				Message:       fmt.Sprintf("No changes: the %s entry already has these values", current.FoodName),
+			}, nil
+		}
+
+		// Call backend API to update entry
+		resp, err := client.UpdateFoodEntry(ctx, input.EntryID, req)
+		if err != nil {
+			return nil, UpdateFoodEntryOutput{}, backendError("update food entry", err)
+		}
+
+		// Prepare output
+		before := current.LoggedNutrients()
+		delta := resp.LoggedNutrients().Sub(before)
+
+		output := UpdateFoodEntryOutput{
+			Entry:         convertEntryToResult(*resp),
+			Before:        convertNutrientsToResult(before),
+			Delta:         convertNutrientsToResult(delta),
+			UpdatedFields: updated,
+			Message: fmt.Sprintf("Updated %s entry (%s): %+.0f kcal, %+.1fg protein",
+				resp.FoodName, strings.Join(updated, ", "), delta.Calories, delta.Protein),
+		}
+
+		return nil, output, nil
+	}
+
+	mcp.AddTool(server, tool, handler)
+	return nil
+}
@@ -0,0 +1,136 @@
+package tools
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+)
+
+func TestUpdateFoodEntry(t *testing.T) {
+	backend, session := newTestSession(t)
+	rice := backend.AddFood(sparkyfitness.Food{Name: "Rice"},
+		sparkyfitness.FoodVariant{ServingSize: 100, ServingUnit: "g", Nutrients: sparkyfitness.Nutrients{Calories: 130, Protein: 2.7}},
+		sparkyfitness.FoodVariant{ServingSize: 1, ServingUnit: "cup", Nutrients: sparkyfitness.Nutrients{Calories: 205, Protein: 4.3}},
+	)
+	_, variants, _ := backend.Food(rice.ID)
+
+	tests := []struct {
+		name         string
+		args         map[string]any
+		wantMeal     string
+		wantQuantity float64
+		wantUnit     string
+		wantDelta    float64
+		wantFields   string
+	}{
+		{
+			name:         "move to lunch",
+			args:         map[string]any{"meal_type": "lunch"},
+			wantMeal:     "lunch",
+			wantQuantity: 200,
+			wantUnit:     "g",
+			wantDelta:    0,
+			wantFields:   "meal_type",
+		},
+		{
+			name:         "half the servings",
+			args:         map[string]any{"servings": 1},
+			wantMeal:     "breakfast",
+			wantQuantity: 100,
+			wantUnit:     "g",
+			wantDelta:    -130,
+			wantFields:   "quantity",
+		},
+		{
+			name:         "switch variant keeps servings",
+			args:         map[string]any{"variant_id": variants[1].ID},
+			wantMeal:     "breakfast",
+			wantQuantity: 2,
+			wantUnit:     "cup",
+			wantDelta:    150,
+			wantFields:   "variant_id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := backend.AddFoodEntry(sparkyfitness.FoodEntry{VariantID: variants[0].ID, MealType: "breakfast", Quantity: 200, EntryDate: "2025-01-15"})
+			tt.args["entry_id"] = entry.ID
+
+			out := callTool[UpdateFoodEntryOutput](t, session, "update_food_entry", tt.args)
+
+			if out.Entry.MealType != tt.wantMeal || out.Entry.Quantity != tt.wantQuantity || out.Entry.Unit != tt.wantUnit {
+				t.Errorf("entry = %+v", out.Entry)
+			}
+			if out.Before.Calories != 260 {
+				t.Errorf("Before.Calories = %v, want 260", out.Before.Calories)
+			}
+			if out.Delta.Calories != tt.wantDelta {
+				t.Errorf("Delta.Calories = %v, want %v", out.Delta.Calories, tt.wantDelta)
+			}
+			if got := strings.Join(out.UpdatedFields, ","); got != tt.wantFields {
+				t.Errorf("UpdatedFields = %v, want %v", got, tt.wantFields)
+			}
+		})
+	}
+}
+
+func TestUpdateFoodEntryNoChanges(t *testing.T) {
+	backend, session := newTestSession(t)
+	rice, _ := seedDiary(backend)
+	entry := backend.AddFoodEntry(sparkyfitness.FoodEntry{VariantID: rice.DefaultVariant.ID, MealType: "lunch", Quantity: 150, EntryDate: "2025-01-20"})
+	before := len(backend.Requests())
+
+	out := callTool[UpdateFoodEntryOutput](t, session, "update_food_entry", map[string]any{
+		"entry_id":  entry.ID,
+		"meal_type": "Lunch",
+		"date":      "2025-01-20",
+		"quantity":  150,
+	})
+
+	if len(out.UpdatedFields) != 0 || !strings.HasPrefix(out.Message, "No changes") || out.Entry.Quantity != 150 {
+		t.Errorf("output = %+v, want a no-op", out)
+	}
+	for _, req := range backend.Requests()[before:] {
+		if req.Method == http.MethodPut {
+			t.Errorf("unexpected %s %s for an unchanged entry", req.Method, req.Path)
+		}
+	}
+}
+
+func TestUpdateFoodEntryErrors(t *testing.T) {
+	_, session := newTestSession(t)
+
+	tests := []struct {
+		name         string
+		args         map[string]any
+		wantContains string
+	}{
+		{
+			name:         "no fields",
+			args:         map[string]any{"entry_id": "abc"},
+			wantContains: "nothing to update",
+		},
+		{
+			name:         "quantity and servings",
+			args:         map[string]any{"entry_id": "abc", "quantity": 1, "servings": 1},
+			wantContains: "not both",
+		},
+		{
+			name:         "unknown entry",
+			args:         map[string]any{"entry_id": "missing", "meal_type": "lunch"},
+			wantContains: "not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := callToolError(t, session, "update_food_entry", tt.args)
+			if !strings.Contains(msg, tt.wantContains) {
+				t.Errorf("error = %q, should contain %q", msg, tt.wantContains)
+			}
+		})
+	}
+}
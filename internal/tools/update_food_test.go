@@ -0,0 +1,47 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+)
+
+func TestUpdateFood(t *testing.T) {
+	backend, session := newTestSession(t)
+	brand := "Trader Joes"
+	food := backend.AddFood(sparkyfitness.Food{Name: "Jasmin Rice", Brand: &brand, IsCustom: true},
+		sparkyfitness.FoodVariant{ServingSize: 45, ServingUnit: "g", Nutrients: sparkyfitness.Nutrients{Calories: 160}},
+	)
+
+	out := callTool[UpdateFoodOutput](t, session, "update_food", map[string]any{
+		"food_id": food.ID,
+		"name":    "Jasmine Rice",
+	})
+
+	if out.FoodName != "Jasmine Rice" || len(out.UpdatedFields) != 1 || out.UpdatedFields[0] != "name" {
+		t.Errorf("output = %+v", out)
+	}
+
+	// Omitted fields keep their values
+	stored, _, _ := backend.Food(food.ID)
+	if stored.Name != "Jasmine Rice" || stored.Brand == nil || *stored.Brand != "Trader Joes" {
+		t.Errorf("stored food = %+v", stored)
+	}
+
+	// Empty brand removes it
+	callTool[UpdateFoodOutput](t, session, "update_food", map[string]any{"food_id": food.ID, "brand": ""})
+	stored, _, _ = backend.Food(food.ID)
+	if stored.Brand != nil {
+		t.Errorf("Brand = %q, want nil", *stored.Brand)
+	}
+}
+
+func TestUpdateFoodRequiresAField(t *testing.T) {
+	_, session := newTestSession(t)
+
+	msg := callToolError(t, session, "update_food", map[string]any{"food_id": "abc"})
+	if !strings.Contains(msg, "nothing to update") {
+		t.Errorf("error = %q", msg)
+	}
+}
@@ -0,0 +1,183 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// UpdateFoodVariantInput defines the input parameters for the update_food_variant tool
+type UpdateFoodVariantInput struct {
+	VariantID   string   `json:"variant_id" jsonschema:"required,Unique identifier of the variant to update (from search_foods or get_food results)"`
+	ServingSize *float64 `json:"serving_size,omitempty" jsonschema:"New numeric serving size amount (omit to keep current)"`
+	ServingUnit *string  `json:"serving_unit,omitempty" jsonschema:"New unit of measurement (omit to keep current)"`
+	Calories    *float64 `json:"calories,omitempty" jsonschema:"Calories per serving (omit to keep current)"`
+	Protein     *float64 `json:"protein,omitempty" jsonschema:"Protein in grams (omit to keep current)"`
+	Carbs       *float64 `json:"carbs,omitempty" jsonschema:"Carbohydrates in grams (omit to keep current)"`
+	Fat         *float64 `json:"fat,omitempty" jsonschema:"Fat in grams (omit to keep current)"`
+	OptionalNutrientsInput
+	CustomNutrients []CustomNutrientInput `json:"custom_nutrients,omitempty" jsonschema:"Custom nutrient amounts to set; other custom nutrients keep their values"`
+	GlycemicIndex   *string               `json:"glycemic_index,omitempty" jsonschema:"Glycemic index (omit to keep current)"`
+}
+
+// UpdateFoodVariantOutput defines the output structure
+type UpdateFoodVariantOutput struct {
+	FoodID        string        `json:"food_id" jsonschema:"ID of the food the variant belongs to"`
+	Variant       VariantResult `json:"variant" jsonschema:"The variant after the update"`
+	UpdatedFields []string      `json:"updated_fields" jsonschema:"Fields provided in this update"`
+	Warnings      []string      `json:"warnings,omitempty" jsonschema:"Label check warnings; verify these values with the user"`
+	Conversions   []string      `json:"conversions,omitempty" jsonschema:"Unit conversions applied to the custom nutrient amounts"`
+	Message       string        `json:"message" jsonschema:"Success message"`
+}
+
+// RegisterUpdateFoodVariant registers the update_food_variant tool with the MCP server
+func (r *Registry) RegisterUpdateFoodVariant(server *mcp.Server, client *sparkyfitness.Client) error {
+	tool := &mcp.Tool{
+		Name:  "update_food_variant",
+		Title: "Update Food Variant Nutrition",
+		Description: "✏️ Correct the serving size or nutrition of an EXISTING food variant.\n\n" +
+			"**When to Use:**\n" +
+			"• A nutrition label was mis-read and a value needs fixing\n" +
+			"• The serving size or unit of a variant is wrong\n\n" +
+			"**Partial Update:**\n" +
+			"Only the fields you provide are changed; every omitted field keeps its current value. " +
+			"Do NOT resend unchanged nutrients.\n\n" +
+			"The updated values are checked like in add_food_variant: negative amounts and impossible totals are rejected, " +
+			"suspicious values are returned as warnings.\n\n" +
+			"**Required Input:**\n" +
+			"• variant_id: UUID from search_foods (variant_id) or get_food results\n" +
+			"• At least one field to change: serving_size, serving_unit, any nutrient, glycemic_index, custom_nutrients\n\n" +
+			"**Output:**\n" +
+			"• variant: the full variant after the update\n" +
+			"• updated_fields: which fields were changed\n" +
+			"• conversions: custom nutrient amounts converted to their configured unit\n\n" +
+			"**Example:**\n" +
+			"User: 'The protein for that yogurt should be 10g, not 1g'\n" +
+			"→ update_food_variant(variant_id='def-456', protein=10)",
+		Annotations: &mcp.ToolAnnotations{
+			IdempotentHint: true,
+		},
+	}
+
+	handler := func(ctx context.Context, request *mcp.CallToolRequest, input UpdateFoodVariantInput) (*mcp.CallToolResult, UpdateFoodVariantOutput, error) {
+		// Validate required parameters
+		if input.VariantID == "" {
+			return nil, UpdateFoodVariantOutput{}, fmt.Errorf("variant_id parameter is required")
+		}
+		if input.ServingSize != nil && *input.ServingSize <= 0 {
+			return nil, UpdateFoodVariantOutput{}, fmt.Errorf("serving_size must be greater than 0")
+		}
+		if input.ServingUnit != nil && *input.ServingUnit == "" {
+			return nil, UpdateFoodVariantOutput{}, fmt.Errorf("serving_unit cannot be empty")
+		}
+
+		// Fetch current variant so omitted fields keep their values
+		current, err := client.GetFoodVariant(ctx, input.VariantID)
+		if err != nil {
+			return nil, UpdateFoodVariantOutput{}, backendError("get food variant", err)
+		}
+
+		// Merge patch onto current values
+		req := variantUpdateRequest(current)
+
+		updated := []string{}
+		if input.ServingSize != nil {
+			req.ServingSize = *input.ServingSize
+			updated = append(updated, "serving_size")
+		}
+		if input.ServingUnit != nil {
+			req.ServingUnit = *input.ServingUnit
+			updated = append(updated, "serving_unit")
+		}
+		if input.Calories != nil {
+			req.Calories = *input.Calories
+			updated = append(updated, "calories")
+		}
+		if input.Protein != nil {
+			req.Protein = *input.Protein
+			updated = append(updated, "protein")
+		}
+		if input.Carbs != nil {
+			req.Carbs = *input.Carbs
+			updated = append(updated, "carbs")
+		}
+		if input.Fat != nil {
+			req.Fat = *input.Fat
+			updated = append(updated, "fat")
+		}
+		updated = append(updated, input.OptionalNutrientsInput.applyTo(&req.Nutrients)...)
+		if input.GlycemicIndex != nil {
+			req.GlycemicIndex = input.GlycemicIndex
+			updated = append(updated, "glycemic_index")
+		}
+		var conversions []string
+		if len(input.CustomNutrients) > 0 {
+			req.CustomNutrients, conversions, err = resolveCustomNutrients(ctx, client, input.CustomNutrients, req.CustomNutrients)
+			if err != nil {
+				return nil, UpdateFoodVariantOutput{}, err
+			}
+			updated = append(updated, "custom_nutrients")
+		}
+
+		if len(updated) == 0 {
+			return nil, UpdateFoodVariantOutput{}, fmt.Errorf("nothing to update: provide at least one serving or nutrient field")
+		}
+
+		// Check the label before writing, unless only the glycemic index changed
+		var warnings []string
+		if len(updated) > 1 || input.GlycemicIndex == nil {
+			warnings, err = checkLabel(sparkyfitness.FoodVariant{
+				ServingSize: req.ServingSize,
+				ServingUnit: req.ServingUnit,
+				Nutrients:   req.Nutrients,
+			}, false)
+			if err != nil {
+				return nil, UpdateFoodVariantOutput{}, err
+			}
+		}
+
+		// Call backend API to update variant
+		resp, err := client.UpdateFoodVariant(ctx, input.VariantID, req)
+		if err != nil {
+			return nil, UpdateFoodVariantOutput{}, backendError("update food variant", err)
+		}
+
+		// Prepare output
+		foodID := resp.FoodID
+		if foodID == "" {
+			foodID = current.FoodID
+		}
+
+		output := UpdateFoodVariantOutput{
+			FoodID:        foodID,
+			Variant:       convertVariantToResult(*resp),
+			UpdatedFields: updated,
+			Warnings:      warnings,
+			Conversions:   conversions,
+			Message: fmt.Sprintf("Successfully updated %g %s variant (%s)",
+				resp.ServingSize, resp.ServingUnit, strings.Join(updated, ", ")),
+		}
+
+		return nil, output, nil
+	}
+
+	mcp.AddTool(server, tool, handler)
+	return nil
+}
+
+// variantUpdateRequest builds a full replacement request carrying the current
+// values of v, ready to be modified before calling UpdateFoodVariant
+func variantUpdateRequest(v *sparkyfitness.FoodVariant) *sparkyfitness.UpdateFoodVariantRequest {
+	return &sparkyfitness.UpdateFoodVariantRequest{
+		FoodID:          v.FoodID,
+		ServingSize:     v.ServingSize,
+		ServingUnit:     v.ServingUnit,
+		Nutrients:       v.Nutrients,
+		IsDefault:       v.IsDefault,
+		GlycemicIndex:   v.GlycemicIndex,
+		CustomNutrients: v.CustomNutrients,
+	}
+}
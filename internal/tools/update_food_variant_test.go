@@ -0,0 +1,115 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+)
+
+func TestUpdateFoodVariant(t *testing.T) {
+	backend, session := newTestSession(t)
+	food := backend.AddFood(sparkyfitness.Food{Name: "Greek Yogurt"},
+		sparkyfitness.FoodVariant{ServingSize: 170, ServingUnit: "g", Nutrients: sparkyfitness.Nutrients{Calories: 100, Protein: 1, Carbs: 6, Fat: 0, Sodium: 65}},
+	)
+
+	out := callTool[UpdateFoodVariantOutput](t, session, "update_food_variant", map[string]any{
+		"variant_id": food.DefaultVariant.ID,
+		"protein":    17,
+		"calcium":    200,
+	})
+
+	if out.FoodID != food.ID || out.Variant.Protein != 17 || out.Variant.Calcium != 200 {
+		t.Errorf("output = %+v", out)
+	}
+	if strings.Join(out.UpdatedFields, ",") != "protein,calcium" {
+		t.Errorf("UpdatedFields = %v", out.UpdatedFields)
+	}
+
+	// Omitted fields keep their values
+	_, variants, _ := backend.Food(food.ID)
+	v := variants[0]
+	if v.ServingSize != 170 || v.Calories != 100 || v.Carbs != 6 || v.Sodium != 65 || !v.IsDefault {
+		t.Errorf("stored variant = %+v, want untouched fields preserved", v)
+	}
+}
+
+func TestUpdateFoodVariantErrors(t *testing.T) {
+	backend, session := newTestSession(t)
+	food := backend.AddFood(sparkyfitness.Food{Name: "Greek Yogurt"},
+		sparkyfitness.FoodVariant{ServingSize: 170, ServingUnit: "g"},
+	)
+
+	tests := []struct {
+		name         string
+		args         map[string]any
+		wantContains string
+	}{
+		{
+			name:         "no fields",
+			args:         map[string]any{"variant_id": food.DefaultVariant.ID},
+			wantContains: "nothing to update",
+		},
+		{
+			name:         "invalid serving size",
+			args:         map[string]any{"variant_id": food.DefaultVariant.ID, "serving_size": -1},
+			wantContains: "serving_size must be greater than 0",
+		},
+		{
+			name:         "negative nutrient",
+			args:         map[string]any{"variant_id": food.DefaultVariant.ID, "protein": -5},
+			wantContains: "protein: must not be negative",
+		},
+		{
+			name:         "unknown variant",
+			args:         map[string]any{"variant_id": "missing", "protein": 1},
+			wantContains: "not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := callToolError(t, session, "update_food_variant", tt.args)
+			if !strings.Contains(msg, tt.wantContains) {
+				t.Errorf("error = %q, should contain %q", msg, tt.wantContains)
+			}
+		})
+	}
+}
+
+func TestUpdateFoodVariantCustomNutrients(t *testing.T) {
+	backend, session := newTestSession(t)
+	backend.AddCustomNutrient("Caffeine", "mg")
+	backend.AddCustomNutrient("Choline", "mg")
+	food := backend.AddFood(sparkyfitness.Food{Name: "Cold Brew"},
+		sparkyfitness.FoodVariant{
+			ServingSize:     250,
+			ServingUnit:     "ml",
+			Nutrients:       sparkyfitness.Nutrients{Calories: 5},
+			CustomNutrients: map[string]interface{}{"Caffeine": 150.0, "Choline": 4.0},
+		},
+	)
+
+	out := callTool[UpdateFoodVariantOutput](t, session, "update_food_variant", map[string]any{
+		"variant_id":       food.DefaultVariant.ID,
+		"custom_nutrients": []map[string]any{{"name": "Caffeine", "amount": 200}},
+	})
+	if strings.Join(out.UpdatedFields, ",") != "custom_nutrients" {
+		t.Errorf("UpdatedFields = %v", out.UpdatedFields)
+	}
+
+	// Other custom nutrients keep their values
+	_, variants, _ := backend.Food(food.ID)
+	if got := variants[0].CustomNutrients; got["Caffeine"] != 200.0 || got["Choline"] != 4.0 {
+		t.Errorf("stored custom nutrients = %v", got)
+	}
+
+	// Conversions to the configured unit are reported
+	out = callTool[UpdateFoodVariantOutput](t, session, "update_food_variant", map[string]any{
+		"variant_id":       food.DefaultVariant.ID,
+		"custom_nutrients": []map[string]any{{"name": "Caffeine", "amount": 0.18, "unit": "g"}},
+	})
+	if len(out.Conversions) != 1 || !strings.Contains(out.Conversions[0], "180 mg") {
+		t.Errorf("Conversions = %v, want g converted to 180 mg", out.Conversions)
+	}
+}
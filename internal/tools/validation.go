@@ -0,0 +1,36 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/chickenzord/sparkyfitness-mcp/internal/nutrition"
+	"github.com/chickenzord/sparkyfitness-mcp/internal/sparkyfitness"
+)
+
+// checkLabel runs the nutrition label sanity checks on a variant about to be
+// written. Errors always block the write; warnings are returned for the
+// output, or block the write too when strict is set.
+func checkLabel(v sparkyfitness.FoodVariant, strict bool) ([]string, error) {
+	var errs, warnings []string
+	for _, issue := range nutrition.ValidateLabel(v) {
+		if issue.Severity == nutrition.SeverityError {
+			errs = append(errs, issue.String())
+		} else {
+			warnings = append(warnings, issue.String())
+		}
+	}
+
+	if len(errs) > 0 {
+		return warnings, fmt.Errorf("nutrition values failed the label check:\n- %s\n"+
+			"Re-check the label photo (swapped columns, per-100 g vs per-serving values, kJ entered as kcal) and correct the values",
+			strings.Join(append(errs, warnings...), "\n- "))
+	}
+	if strict && len(warnings) > 0 {
+		return warnings, fmt.Errorf("nutrition values look suspicious (strict mode):\n- %s\n"+
+			"Re-check the label photo and correct the values, or set strict=false if the user confirms they are right",
+			strings.Join(warnings, "\n- "))
+	}
+
+	return warnings, nil
+}